@@ -0,0 +1,249 @@
+package webwire
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// CachingSessionManagerOptions represents the options
+// of a caching session manager
+type CachingSessionManagerOptions struct {
+	// Size defines the maximum number of cached session lookup results.
+	// The least recently used results are evicted once the limit is reached.
+	// If undefined then the default value of 1024 is applied
+	Size int
+
+	// TTL defines the duration a session lookup result is cached for.
+	// If undefined then the default value of 1 minute is applied
+	TTL time.Duration
+}
+
+// SetDefaults sets the defaults for undefined required values
+func (opts *CachingSessionManagerOptions) SetDefaults() {
+	if opts.Size < 1 {
+		opts.Size = 1024
+	}
+
+	if opts.TTL < 1 {
+		opts.TTL = 1 * time.Minute
+	}
+}
+
+// CacheStats represents the statistics of a session lookup cache
+type CacheStats struct {
+	// Hits is the number of lookups served from the cache
+	Hits uint64
+
+	// Misses is the number of lookups forwarded
+	// to the underlying session manager
+	Misses uint64
+}
+
+// HitRatio returns the ratio of lookups served from the cache
+// or 0 if there were no lookups yet
+func (stats CacheStats) HitRatio() float64 {
+	total := stats.Hits + stats.Misses
+	if total < 1 {
+		return 0
+	}
+	return float64(stats.Hits) / float64(total)
+}
+
+// cacheEntry represents a cached session lookup result
+type cacheEntry struct {
+	key      string
+	result   SessionLookupResult
+	cachedAt time.Time
+}
+
+// CachingSessionManager represents a session manager wrapping another
+// session manager caching the results of its OnSessionLookup hook
+// in a least recently used cache to reduce the load on the underlying
+// session storage under reconnection storms.
+// Cached results are invalidated when the session is created, updated
+// or closed through this session manager. Lookups served from the cache
+// don't update the last lookup time in the underlying session storage.
+// It's safe for concurrent use
+type CachingSessionManager struct {
+	manager SessionManager
+	options CachingSessionManagerOptions
+
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	stats   CacheStats
+
+	// generation is incremented on every invalidation to prevent caching
+	// results of lookups performed concurrently with an invalidation
+	generation uint64
+}
+
+// NewCachingSessionManager constructs a new caching session manager
+// wrapping the given session manager
+func NewCachingSessionManager(
+	manager SessionManager,
+	opts CachingSessionManagerOptions,
+) *CachingSessionManager {
+	opts.SetDefaults()
+	return &CachingSessionManager{
+		manager: manager,
+		options: opts,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Stats returns the hit and miss statistics of the cache
+func (mng *CachingSessionManager) Stats() CacheStats {
+	mng.lock.Lock()
+	defer mng.lock.Unlock()
+	return mng.stats
+}
+
+// Len returns the number of currently cached session lookup results
+func (mng *CachingSessionManager) Len() int {
+	mng.lock.Lock()
+	defer mng.lock.Unlock()
+	return mng.lru.Len()
+}
+
+// invalidate removes the cached lookup result of the given session if any
+func (mng *CachingSessionManager) invalidate(key string) {
+	mng.lock.Lock()
+	defer mng.lock.Unlock()
+	mng.generation++
+	mng.remove(key)
+}
+
+// remove removes the cached lookup result of the given session if any.
+// Expects the lock to be held by the caller
+func (mng *CachingSessionManager) remove(key string) {
+	if element, exists := mng.entries[key]; exists {
+		mng.lru.Remove(element)
+		delete(mng.entries, key)
+	}
+}
+
+// cached returns the cached lookup result of the given session
+// or nil if it's not cached or no longer valid.
+// Expects the lock to be held by the caller
+func (mng *CachingSessionManager) cached(
+	key string,
+	now time.Time,
+) SessionLookupResult {
+	element, exists := mng.entries[key]
+	if !exists {
+		return nil
+	}
+	entry := element.Value.(*cacheEntry)
+
+	expiration := lookupResultExpiration(entry.result)
+	if now.Sub(entry.cachedAt) > mng.options.TTL ||
+		(!expiration.IsZero() && !now.Before(expiration)) {
+		mng.remove(key)
+		return nil
+	}
+
+	mng.lru.MoveToFront(element)
+	return entry.result
+}
+
+// store caches the given lookup result evicting the least recently used
+// result if the cache is full. Expects the lock to be held by the caller
+func (mng *CachingSessionManager) store(
+	key string,
+	result SessionLookupResult,
+	now time.Time,
+) {
+	mng.remove(key)
+	if mng.lru.Len() >= mng.options.Size {
+		oldest := mng.lru.Back()
+		mng.lru.Remove(oldest)
+		delete(mng.entries, oldest.Value.(*cacheEntry).key)
+	}
+	mng.entries[key] = mng.lru.PushFront(&cacheEntry{
+		key:      key,
+		result:   result,
+		cachedAt: now,
+	})
+}
+
+// copyLookupResult returns a copy of the given lookup result
+// to protect cached results from mutations
+func copyLookupResult(result SessionLookupResult) SessionLookupResult {
+	var info map[string]interface{}
+	if result.Info() != nil {
+		info = make(map[string]interface{}, len(result.Info()))
+		for field, value := range result.Info() {
+			info[field] = value
+		}
+	}
+	return NewExpiringSessionLookupResult(
+		result.Creation(),
+		result.LastLookup(),
+		lookupResultExpiration(result),
+		info,
+	)
+}
+
+// OnSessionCreated implements the session manager interface.
+// It forwards the call to the underlying session manager
+// and invalidates the cached lookup result of the session
+func (mng *CachingSessionManager) OnSessionCreated(conn Connection) error {
+	err := mng.manager.OnSessionCreated(conn)
+	mng.invalidate(conn.SessionKey())
+	return err
+}
+
+// OnSessionLookup implements the session manager interface.
+// It returns the cached lookup result if there's a valid one,
+// otherwise it forwards the call to the underlying session manager
+// caching the found session
+func (mng *CachingSessionManager) OnSessionLookup(key string) (
+	SessionLookupResult,
+	error,
+) {
+	now := time.Now()
+
+	mng.lock.Lock()
+	if result := mng.cached(key, now); result != nil {
+		mng.stats.Hits++
+		mng.lock.Unlock()
+		return copyLookupResult(result), nil
+	}
+	mng.stats.Misses++
+	generation := mng.generation
+	mng.lock.Unlock()
+
+	result, err := mng.manager.OnSessionLookup(key)
+	if err != nil || result == nil {
+		return result, err
+	}
+
+	mng.lock.Lock()
+	if mng.generation == generation {
+		mng.store(key, copyLookupResult(result), now)
+	}
+	mng.lock.Unlock()
+
+	return result, nil
+}
+
+// OnSessionClosed implements the session manager interface.
+// It forwards the call to the underlying session manager
+// and invalidates the cached lookup result of the session
+func (mng *CachingSessionManager) OnSessionClosed(sessionKey string) error {
+	err := mng.manager.OnSessionClosed(sessionKey)
+	mng.invalidate(sessionKey)
+	return err
+}
+
+// OnSessionUpdated implements the session manager interface.
+// It forwards the call to the underlying session manager
+// and invalidates the cached lookup result of the session
+func (mng *CachingSessionManager) OnSessionUpdated(conn Connection) error {
+	err := mng.manager.OnSessionUpdated(conn)
+	mng.invalidate(conn.SessionKey())
+	return err
+}
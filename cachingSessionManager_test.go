@@ -0,0 +1,114 @@
+package webwire
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingSessionManager wraps a session manager
+// counting the invocations of the OnSessionLookup hook
+type countingSessionManager struct {
+	*InMemorySessionManager
+	lookups int
+}
+
+// OnSessionLookup implements the session manager interface
+func (mng *countingSessionManager) OnSessionLookup(key string) (
+	SessionLookupResult,
+	error,
+) {
+	mng.lookups++
+	return mng.InMemorySessionManager.OnSessionLookup(key)
+}
+
+// TestCachingSessionManager tests whether lookups are served from the cache
+// and the cache is invalidated when the session is updated or closed
+func TestCachingSessionManager(t *testing.T) {
+	underlying := &countingSessionManager{
+		InMemorySessionManager: NewInMemorySessionManager(),
+	}
+	mng := NewCachingSessionManager(
+		underlying,
+		CachingSessionManagerOptions{},
+	)
+
+	sess := NewSession(
+		GenericSessionInfoParser(map[string]interface{}{"field": "value"}),
+		func() string { return "testkey" },
+	)
+	conn := newTestSessionConnection(sess)
+	require.NoError(t, mng.OnSessionCreated(conn))
+
+	// Expect only the first lookup to reach the underlying session manager
+	for i := 0; i < 3; i++ {
+		result, err := mng.OnSessionLookup("testkey")
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, "value", result.Info()["field"])
+		result.Info()["field"] = "mutated"
+	}
+	require.Equal(t, 1, underlying.lookups)
+	require.Equal(t, CacheStats{Hits: 2, Misses: 1}, mng.Stats())
+	require.InDelta(t, 2.0/3.0, mng.Stats().HitRatio(), 0.001)
+
+	// Expect the update to invalidate the cached result
+	conn.session.Info = GenericSessionInfoParser(
+		map[string]interface{}{"field": "updated"},
+	)
+	require.NoError(t, mng.OnSessionUpdated(conn))
+	result, err := mng.OnSessionLookup("testkey")
+	require.NoError(t, err)
+	require.Equal(t, "updated", result.Info()["field"])
+	require.Equal(t, 2, underlying.lookups)
+
+	// Expect the closure to invalidate the cached result
+	require.NoError(t, mng.OnSessionClosed("testkey"))
+	result, err = mng.OnSessionLookup("testkey")
+	require.NoError(t, err)
+	require.Nil(t, result)
+	require.Equal(t, 0, mng.Len())
+}
+
+// TestCachingSessionManagerEviction tests whether the least recently used
+// results are evicted when the cache is full and results expire
+// after the configured TTL
+func TestCachingSessionManagerEviction(t *testing.T) {
+	underlying := &countingSessionManager{
+		InMemorySessionManager: NewInMemorySessionManager(),
+	}
+	mng := NewCachingSessionManager(
+		underlying,
+		CachingSessionManagerOptions{
+			Size: 2,
+			TTL:  50 * time.Millisecond,
+		},
+	)
+
+	for _, key := range []string{"A", "B", "C"} {
+		key := key
+		sess := NewSession(nil, func() string { return key })
+		require.NoError(t, mng.OnSessionCreated(
+			newTestSessionConnection(sess),
+		))
+		_, err := mng.OnSessionLookup(key)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, mng.Len())
+
+	// Expect A to be evicted
+	_, err := mng.OnSessionLookup("A")
+	require.NoError(t, err)
+	require.Equal(t, 4, underlying.lookups)
+
+	// Expect C to be cached until the TTL elapses
+	_, err = mng.OnSessionLookup("C")
+	require.NoError(t, err)
+	require.Equal(t, 4, underlying.lookups)
+
+	time.Sleep(60 * time.Millisecond)
+	_, err = mng.OnSessionLookup("C")
+	require.NoError(t, err)
+	require.Equal(t, 5, underlying.lookups)
+}
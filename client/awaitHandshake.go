@@ -0,0 +1,43 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	webwire "github.com/qbeon/webwire-go"
+)
+
+// awaitHandshake blocks the calling goroutine until either the handshake
+// of the server is received, the connection is lost or the handshake
+// isn't received within the default request timeout
+func (clt *client) awaitHandshake() error {
+	timeout := time.NewTimer(clt.defaultReqTimeout)
+	defer timeout.Stop()
+
+	select {
+	case err := <-clt.handshake:
+		return err
+	case <-timeout.C:
+		return webwire.NewTimeoutErr(fmt.Errorf(
+			"Server handshake timed out",
+		))
+	}
+}
+
+// resetHandshake discards the handshake result
+// of any previous connection
+func (clt *client) resetHandshake() {
+	select {
+	case <-clt.handshake:
+	default:
+	}
+}
+
+// completeHandshake non-blockingly reports the result of the handshake
+// to the goroutine awaiting it
+func (clt *client) completeHandshake(err error) {
+	select {
+	case clt.handshake <- err:
+	default:
+	}
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"sync/atomic"
 	"time"
 
 	webwire "github.com/qbeon/webwire-go"
@@ -15,19 +16,56 @@ func (clt *client) backgroundReconnect() {
 	clt.connecting = true
 	clt.connectingLock.Unlock()
 	go func() {
+		attempts := uint(0)
+		delay := clt.reconnBackoff.initial
 		for {
+			// Report each attempt, connect sets the status
+			// to connected if the attempt succeeds
+			clt.setReconnecting()
 			err := clt.connect()
 			switch err := err.(type) {
-			case nil:
+			case nil, webwire.SessionRestoreErr:
+				// A failed session restoration doesn't fail the reconnection,
+				// the connection remains established as anonymous
 				clt.connectingLock.Lock()
 				clt.backReconn.flush(nil)
 				clt.connecting = false
 				clt.connectingLock.Unlock()
 				return
 			case webwire.DisconnectedErr:
-				time.Sleep(clt.reconnInterval)
+				clt.setStatus(Disconnected)
+
+				// Stop reconnecting if autoconnect was disabled meanwhile
+				if atomic.LoadInt32(&clt.autoconnect) != autoconnectEnabled {
+					clt.connectingLock.Lock()
+					clt.backReconn.flush(err)
+					clt.connecting = false
+					clt.connectingLock.Unlock()
+					return
+				}
+
+				// Give up and disable the client
+				// when the maximum number of attempts is reached
+				attempts++
+				if clt.maxReconnAttempts > 0 &&
+					attempts >= clt.maxReconnAttempts {
+					atomic.StoreInt32(
+						&clt.autoconnect,
+						autoconnectDeactivated,
+					)
+					clt.setStatus(Disabled)
+					clt.connectingLock.Lock()
+					clt.backReconn.flush(err)
+					clt.connecting = false
+					clt.connectingLock.Unlock()
+					clt.impl.OnReconnectionFailed()
+					return
+				}
+				time.Sleep(clt.reconnBackoff.jittered(delay))
+				delay = clt.reconnBackoff.next(delay)
 			default:
 				// Unexpected error
+				clt.setStatus(Disconnected)
 				clt.backReconn.flush(err)
 				return
 			}
@@ -2,6 +2,9 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
+	"net/http"
+	"net/url"
 	"sync/atomic"
 
 	"fmt"
@@ -15,7 +18,7 @@ import (
 	reqman "github.com/qbeon/webwire-go/requestManager"
 )
 
-const supportedProtocolVersion = "1.4"
+const supportedProtocolVersion = "1.5"
 
 // Status represents the status of a client instance
 type Status = int32
@@ -29,6 +32,9 @@ const (
 
 	// Connected represents a normal connection
 	Connected Status = 2
+
+	// Reconnecting represents an ongoing automatic reconnection attempt
+	Reconnecting Status = 3
 )
 
 // autoconnectStatus represents the activation of auto-reconnection
@@ -50,13 +56,30 @@ type client struct {
 	serverAddr        string
 	impl              Implementation
 	sessionInfoParser webwire.SessionInfoParser
+	sessionCodec      webwire.SessionCodec
+	codec             webwire.Codec
 	status            Status
 	defaultReqTimeout time.Duration
-	reconnInterval    time.Duration
+	reconnBackoff     reconnectionBackoff
+	maxReconnAttempts uint
 	autoconnect       autoconnectStatus
+	heartbeat         webwire.OptionValue
+	heartbeatInterval time.Duration
+	heartbeatTimeout  time.Duration
+	tlsConfig         *tls.Config
+	handshakeTimeout  time.Duration
+	proxy             func(*http.Request) (*url.URL, error)
+
+	// statusLock serializes status changes to publish them in order
+	statusLock sync.Mutex
+	// statusChanges receives the new status whenever it changes
+	statusChanges chan Status
 
 	sessionLock sync.RWMutex
 	session     *webwire.Session
+	// sessionProof represents the proof provided during the restoration
+	// of the current session and is reused during automatic restorations
+	sessionProof []byte
 
 	// The API lock synchronizes concurrent access
 	// to the public client interface.
@@ -76,11 +99,19 @@ type client struct {
 	connecting bool
 	// connectingLock protects the connecting flag from concurrent access
 	connectingLock sync.RWMutex
+	// reconnQueue holds a slot for each request queued while reconnecting,
+	// it's nil if QueueWhileReconnecting is disabled
+	reconnQueue chan struct{}
 
 	connectLock   sync.Mutex
 	conn          webwire.Socket
 	readerClosing chan bool
 
+	// handshake receives the result of the connection handshake
+	handshake chan error
+	// sessionsEnabled is set to 1 if the server has sessions enabled
+	sessionsEnabled int32
+
 	requestManager reqman.RequestManager
 
 	// Loggers
@@ -101,6 +132,8 @@ func (clt *client) Status() Status {
 // Connect connects the client to the configured server and
 // returns an error in case of a connection failure.
 // Automatically tries to restore the previous session.
+// Returns a webwire.SessionRestoreErr error if the connection was established
+// but the session couldn't be restored.
 // Enables autoconnect if it was disabled
 func (clt *client) Connect() error {
 	if atomic.LoadInt32(&clt.autoconnect) == autoconnectDeactivated {
@@ -125,6 +158,10 @@ func (clt *client) Request(
 	clt.apiLock.RLock()
 	defer clt.apiLock.RUnlock()
 
+	if clt.reconnQueue != nil {
+		return clt.queuedRequest(ctx, name, payload)
+	}
+
 	if err := clt.tryAutoconnect(ctx, clt.defaultReqTimeout); err != nil {
 		return nil, err
 	}
@@ -151,7 +188,7 @@ func (clt *client) Signal(name string, payload webwire.Payload) error {
 	}
 
 	// Require either a name or a payload or both
-	if len(name) < 1 && (payload == nil || len(payload.Data()) < 1) {
+	if len(name) < 1 && (payload == nil || payload.IsEmpty()) {
 		return webwire.NewProtocolErr(
 			fmt.Errorf("Invalid request, request message requires " +
 				"either a name, a payload or both but is missing both",
@@ -159,8 +196,9 @@ func (clt *client) Signal(name string, payload webwire.Payload) error {
 		)
 	}
 
-	// Initialize payload encoding & data
-	var encoding webwire.PayloadEncoding
+	// Initialize payload encoding & data,
+	// fallback to binary encoding if no payload is given
+	encoding := webwire.EncodingBinary
 	var data []byte
 	if payload != nil {
 		encoding = payload.Encoding()
@@ -179,17 +217,7 @@ func (clt *client) Signal(name string, payload webwire.Payload) error {
 func (clt *client) Session() *webwire.Session {
 	clt.sessionLock.RLock()
 	defer clt.sessionLock.RUnlock()
-	if clt.session == nil {
-		return nil
-	}
-	clone := &webwire.Session{
-		Key:      clt.session.Key,
-		Creation: clt.session.Creation,
-	}
-	if clt.session.Info != nil {
-		clone.Info = clt.session.Info.Copy()
-	}
-	return clone
+	return clt.session.Clone()
 }
 
 // SessionInfo returns a copy of the session info field value
@@ -205,6 +233,59 @@ func (clt *client) SessionInfo(fieldName string) interface{} {
 	return val
 }
 
+// SetSession assigns a copy of the given session to the client locally.
+// Fails if the client is currently connected
+func (clt *client) SetSession(session *webwire.Session) error {
+	clt.apiLock.Lock()
+	defer clt.apiLock.Unlock()
+
+	if session == nil || len(session.Key) < 1 {
+		return fmt.Errorf("Can't set an invalid session (missing key)")
+	}
+	if atomic.LoadInt32(&clt.status) == Connected {
+		return fmt.Errorf("Can't set the session locally while connected")
+	}
+
+	clt.sessionLock.Lock()
+	clt.session = session.Clone()
+	clt.sessionProof = nil
+	clt.sessionLock.Unlock()
+
+	return nil
+}
+
+// ClearSession resets the current session of the client locally.
+// Fails if the client is currently connected
+func (clt *client) ClearSession() error {
+	clt.apiLock.Lock()
+	defer clt.apiLock.Unlock()
+
+	if atomic.LoadInt32(&clt.status) == Connected {
+		return fmt.Errorf("Can't clear the session locally while connected")
+	}
+
+	clt.sessionLock.Lock()
+	clt.session = nil
+	clt.sessionProof = nil
+	clt.sessionLock.Unlock()
+
+	return nil
+}
+
+// AutoconnectEnabled returns true if autoconnect is currently enabled
+func (clt *client) AutoconnectEnabled() bool {
+	return atomic.LoadInt32(&clt.autoconnect) == autoconnectEnabled
+}
+
+// SetAutoconnect enables or disables autoconnect
+func (clt *client) SetAutoconnect(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&clt.autoconnect, autoconnectEnabled)
+		return
+	}
+	atomic.StoreInt32(&clt.autoconnect, autoconnectDisabled)
+}
+
 // PendingRequests returns the number of currently pending requests
 func (clt *client) PendingRequests() int {
 	return clt.requestManager.PendingRequests()
@@ -213,6 +294,13 @@ func (clt *client) PendingRequests() int {
 // RestoreSession tries to restore the previously opened session.
 // Fails if a session is currently already active
 func (clt *client) RestoreSession(sessionKey []byte) error {
+	return clt.RestoreSessionWithProof(sessionKey, nil)
+}
+
+// RestoreSessionWithProof tries to restore the previously opened session
+// providing an additional proof to be verified by the server.
+// Fails if a session is currently already active
+func (clt *client) RestoreSessionWithProof(sessionKey, proof []byte) error {
 	clt.apiLock.Lock()
 	defer clt.apiLock.Unlock()
 
@@ -232,13 +320,14 @@ func (clt *client) RestoreSession(sessionKey []byte) error {
 		return err
 	}
 
-	restoredSession, err := clt.requestSessionRestoration(sessionKey)
+	restoredSession, err := clt.requestSessionRestoration(sessionKey, proof)
 	if err != nil {
 		return err
 	}
 
 	clt.sessionLock.Lock()
 	clt.session = restoredSession
+	clt.sessionProof = proof
 	clt.sessionLock.Unlock()
 
 	return nil
@@ -275,6 +364,7 @@ func (clt *client) CloseSession() error {
 	// Reset session locally after destroying it on the server
 	clt.sessionLock.Lock()
 	clt.session = nil
+	clt.sessionProof = nil
 	clt.sessionLock.Unlock()
 
 	return nil
@@ -292,10 +382,10 @@ func (clt *client) Close() {
 	}
 
 	if atomic.LoadInt32(&clt.status) != Connected {
-		atomic.StoreInt32(&clt.status, Disabled)
+		clt.setStatus(Disabled)
 		return
 	}
-	atomic.StoreInt32(&clt.status, Disabled)
+	clt.setStatus(Disabled)
 
 	if err := clt.conn.Close(); err != nil {
 		clt.errorLog.Printf("Failed closing connection: %s", err)
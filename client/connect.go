@@ -2,17 +2,22 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"sync/atomic"
+
+	webwire "github.com/qbeon/webwire-go"
 )
 
 // connect will try to establish a connection to the configured webwire server
 // and try to automatically restore the session if there is any.
-// If the session restoration fails connect won't fail,
-// instead it will reset the current session and return normally.
+// If the session restoration fails the connection remains established
+// as anonymous, the current session is reset and a SessionRestoreErr error
+// wrapping the restoration failure is returned.
 // Before establishing the connection - connect verifies
 // protocol compatibility and returns an error if
 // the protocol implemented by the server doesn't match
 // the required protocol version of this client instance.
+// connect returns only after the handshake of the server is received.
 func (clt *client) connect() error {
 	clt.connectLock.Lock()
 	defer clt.connectLock.Unlock()
@@ -24,15 +29,28 @@ func (clt *client) connect() error {
 		return err
 	}
 
+	clt.resetHandshake()
+
 	if err := clt.conn.Dial(clt.serverAddr); err != nil {
 		return err
 	}
 
+	// Start the heartbeat (if enabled)
+	stopHeartbeat := make(chan struct{})
+	if clt.heartbeat == webwire.Enabled {
+		if err := clt.startHeartbeat(stopHeartbeat); err != nil {
+			clt.conn.Close()
+			return err
+		}
+	}
+
 	// Setup reader thread
 	go func() {
 		defer func() {
+			close(stopHeartbeat)
+
 			// Set status
-			atomic.StoreInt32(&clt.status, Disconnected)
+			clt.setStatus(Disconnected)
 			select {
 			case clt.readerClosing <- true:
 			default:
@@ -46,10 +64,15 @@ func (clt *client) connect() error {
 					clt.errorLog.Print("Abnormal closure error:", err)
 				}
 
-				atomic.StoreInt32(&clt.status, Disconnected)
+				// Fail the handshake if it's still awaited
+				clt.completeHandshake(webwire.NewDisconnectedErr(fmt.Errorf(
+					"Connection lost before the handshake was completed",
+				)))
+
+				clt.setStatus(Disconnected)
 
 				// Call hook
-				clt.impl.OnDisconnected()
+				clt.impl.OnDisconnected(disconnectErr(err))
 
 				// Try to reconnect if autoconn wasn't disabled.
 				// reconnect in another goroutine to let this one die
@@ -78,34 +101,55 @@ func (clt *client) connect() error {
 		}
 	}()
 
-	atomic.StoreInt32(&clt.status, Connected)
+	// Block until the handshake is received to ensure the connection
+	// is fully established before returning
+	if err := clt.awaitHandshake(); err != nil {
+		clt.conn.Close()
+
+		// Wait for the reader goroutine to die before returning
+		<-clt.readerClosing
+		return err
+	}
+
+	clt.setStatus(Connected)
 
-	// Read the current sessions key if there is any
+	// Don't try to restore the session if the server has sessions disabled,
+	// the restoration would fail anyway
+	if atomic.LoadInt32(&clt.sessionsEnabled) != 1 {
+		clt.sessionLock.Lock()
+		clt.session = nil
+		clt.sessionProof = nil
+		clt.sessionLock.Unlock()
+		return nil
+	}
+
+	// Read the current sessions key and proof if there is any
 	clt.sessionLock.RLock()
 	if clt.session == nil {
 		clt.sessionLock.RUnlock()
 		return nil
 	}
 	sessionKey := clt.session.Key
+	sessionProof := clt.sessionProof
 	clt.sessionLock.RUnlock()
 
 	// Try to restore session if necessary
-	restoredSession, err := clt.requestSessionRestoration([]byte(sessionKey))
+	restoredSession, err := clt.requestSessionRestoration(
+		[]byte(sessionKey),
+		sessionProof,
+	)
 	if err != nil {
-		// Just log a warning and still return nil,
-		// even if session restoration failed,
-		// because we only care about the connection establishment
-		// in this method
 		clt.warningLog.Printf(
 			"Couldn't restore session on reconnection: %s",
 			err,
 		)
 
-		// Reset the session
+		// Reset the session but keep the connection established
 		clt.sessionLock.Lock()
 		clt.session = nil
+		clt.sessionProof = nil
 		clt.sessionLock.Unlock()
-		return nil
+		return webwire.SessionRestoreErr{Cause: err}
 	}
 
 	clt.sessionLock.Lock()
@@ -113,3 +157,15 @@ func (clt *client) connect() error {
 	clt.sessionLock.Unlock()
 	return nil
 }
+
+// disconnectErr translates the given socket read error into the error
+// reported to the OnDisconnected hook of the client implementation
+func disconnectErr(err webwire.SockReadErr) error {
+	if code, reason, ok := err.CloseFrame(); ok {
+		return webwire.ConnClosedErr{
+			Code:   code,
+			Reason: reason,
+		}
+	}
+	return webwire.NewDisconnectedErr(fmt.Errorf("%s", err))
+}
@@ -9,47 +9,68 @@ import (
 	wwr "github.com/qbeon/webwire-go"
 )
 
+// damBarrier represents a single flushable barrier of a dam.
+// The error is written before the done channel is closed
+// and is thus safe to read after done is closed
+type damBarrier struct {
+	done chan struct{}
+	err  error
+}
+
+// newDamBarrier constructs a new unflushed barrier
+func newDamBarrier() *damBarrier {
+	return &damBarrier{done: make(chan struct{})}
+}
+
 // dam represents a "goroutine dam" that accumulates goroutines blocking them
 // until it's flushed
 type dam struct {
 	lock    sync.RWMutex
-	barrier chan error
+	barrier *damBarrier
 }
 
 // newDam constructs a new dam instance
 func newDam() *dam {
 	return &dam{
 		lock:    sync.RWMutex{},
-		barrier: make(chan error),
+		barrier: newDamBarrier(),
 	}
 }
 
 // await blocks the calling goroutine until the dam is flushed
+// and returns the error the dam was flushed with
 func (dam *dam) await(ctx context.Context, timeout time.Duration) error {
 	dam.lock.RLock()
 	defer dam.lock.RUnlock()
+	barrier := dam.barrier
 	timer := time.NewTimer(timeout)
 	defer timer.Stop()
 	if timeout > 0 {
 		select {
 		case <-ctx.Done():
 			return wwr.TranslateContextError(ctx.Err())
-		case err := <-dam.barrier:
-			return err
+		case <-barrier.done:
+			return barrier.err
 		case <-timer.C:
 			return wwr.NewTimeoutErr(fmt.Errorf("timed out"))
 		}
 	} else {
-		return <-dam.barrier
+		<-barrier.done
+		return barrier.err
 	}
 }
 
 // flush flushes the dam freeing all accumulated goroutines
+// passing them the given error
 func (dam *dam) flush(err error) {
-	close(dam.barrier)
+	dam.lock.RLock()
+	barrier := dam.barrier
+	dam.lock.RUnlock()
+	barrier.err = err
+	close(barrier.done)
 
 	// Reset barrier
 	dam.lock.Lock()
-	dam.barrier = make(chan error)
+	dam.barrier = newDamBarrier()
 	dam.lock.Unlock()
 }
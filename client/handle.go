@@ -3,6 +3,7 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"sync/atomic"
 
 	webwire "github.com/qbeon/webwire-go"
 	msg "github.com/qbeon/webwire-go/message"
@@ -10,9 +11,12 @@ import (
 )
 
 func (clt *client) handleSessionCreated(msgPayload pld.Payload) {
-	var encoded webwire.JSONEncodedSession
-	if err := json.Unmarshal(msgPayload.Data, &encoded); err != nil {
-		clt.errorLog.Printf("Failed unmarshalling session object: %s", err)
+	encoded, err := clt.sessionCodec.Decode(msgPayload.Data)
+	if err != nil {
+		clt.errorLog.Printf("Failed decoding session object: %s", err)
+		clt.impl.OnSessionCreationFailed(
+			fmt.Errorf("Couldn't decode the created session: %s", err),
+		)
 		return
 	}
 
@@ -23,39 +27,130 @@ func (clt *client) handleSessionCreated(msgPayload pld.Payload) {
 	}
 
 	clt.sessionLock.Lock()
+	clt.sessionProof = nil
 	clt.session = &webwire.Session{
-		Key:      encoded.Key,
-		Creation: encoded.Creation,
-		Info:     parsedSessInfo,
+		Key:        encoded.Key,
+		Creation:   encoded.Creation,
+		LastLookup: encoded.LastLookup,
+		Expiration: encoded.ExpirationTime(),
+		Info:       parsedSessInfo,
 	}
 	clt.sessionLock.Unlock()
 	clt.impl.OnSessionCreated(clt.session)
 }
 
-func (clt *client) handleSessionClosed() {
+func (clt *client) handleSessionUpdated(msgPayload pld.Payload) {
+	encoded, err := clt.sessionCodec.Decode(msgPayload.Data)
+	if err != nil {
+		clt.errorLog.Printf("Failed decoding updated session object: %s", err)
+		return
+	}
+
+	// parse attached session info
+	var parsedSessInfo webwire.SessionInfo
+	if encoded.Info != nil && clt.sessionInfoParser != nil {
+		parsedSessInfo = clt.sessionInfoParser(encoded.Info)
+	}
+
+	clt.sessionLock.Lock()
+	if clt.session == nil || clt.session.Key != encoded.Key {
+		clt.sessionLock.Unlock()
+		clt.warningLog.Print("Received an update of an inactive session")
+		return
+	}
+	clt.session.LastLookup = encoded.LastLookup
+	clt.session.Expiration = encoded.ExpirationTime()
+	clt.session.Info = parsedSessInfo
+	clt.sessionLock.Unlock()
+
+	var infoCopy webwire.SessionInfo
+	if parsedSessInfo != nil {
+		infoCopy = parsedSessInfo.Copy()
+	}
+	clt.impl.OnSessionInfoChanged(infoCopy)
+}
+
+func (clt *client) handleHandshake(protocolVersion string, flags []byte) {
+	// Verify the protocol version the server speaks
+	if protocolVersion != supportedProtocolVersion {
+		clt.completeHandshake(webwire.NewConnIncompErr(
+			protocolVersion,
+			supportedProtocolVersion,
+		))
+		return
+	}
+
+	sessionsEnabled := int32(0)
+	if len(flags) > 0 && flags[0] == 1 {
+		sessionsEnabled = 1
+	}
+	atomic.StoreInt32(&clt.sessionsEnabled, sessionsEnabled)
+
+	clt.completeHandshake(nil)
+}
+
+func (clt *client) handleSessionInfoUpdated(msgPayload pld.Payload) {
+	var encoded map[string]interface{}
+	if err := json.Unmarshal(msgPayload.Data, &encoded); err != nil {
+		clt.errorLog.Printf("Failed unmarshalling session info: %s", err)
+		return
+	}
+
+	// parse the updated session info
+	var parsedSessInfo webwire.SessionInfo
+	if encoded != nil && clt.sessionInfoParser != nil {
+		parsedSessInfo = clt.sessionInfoParser(encoded)
+	}
+
+	clt.sessionLock.Lock()
+	if clt.session == nil {
+		clt.sessionLock.Unlock()
+		clt.warningLog.Print("Received a session info update without a session")
+		return
+	}
+	clt.session.Info = parsedSessInfo
+	clt.sessionLock.Unlock()
+
+	var infoCopy webwire.SessionInfo
+	if parsedSessInfo != nil {
+		infoCopy = parsedSessInfo.Copy()
+	}
+	clt.impl.OnSessionInfoChanged(infoCopy)
+}
+
+// handleSessionClosed destroys the local session
+// passing the optional reason of the closure to the hook
+func (clt *client) handleSessionClosed(reason string) {
 	// Destroy local session
 	clt.sessionLock.Lock()
 	clt.session = nil
+	clt.sessionProof = nil
 	clt.sessionLock.Unlock()
 
-	clt.impl.OnSessionClosed()
+	clt.impl.OnSessionClosed(reason)
 }
 
+// handleFailure fails the request associated with the given identifier.
+// The payload is attached to the error if the error reply carried one
 func (clt *client) handleFailure(
 	reqIdent [8]byte,
 	errCode,
 	errMessage string,
+	payload webwire.Payload,
 ) {
 	// Fail request
 	clt.requestManager.Fail(reqIdent, webwire.ReqErr{
 		Code:    errCode,
 		Message: errMessage,
+		Payload: payload,
 	})
 }
 
-func (clt *client) handleInternalError(reqIdent [8]byte) {
+func (clt *client) handleInternalError(reqIdent [8]byte, errMessage string) {
 	// Fail request
-	clt.requestManager.Fail(reqIdent, webwire.ReqInternalErr{})
+	clt.requestManager.Fail(reqIdent, webwire.ReqInternalErr{
+		Message: errMessage,
+	})
 }
 
 func (clt *client) handleReplyShutdown(reqIdent [8]byte) {
@@ -74,7 +169,37 @@ func (clt *client) handleSessionsDisabled(reqIdent [8]byte) {
 	clt.requestManager.Fail(reqIdent, webwire.SessionsDisabledErr{})
 }
 
-func (clt *client) handleReply(reqIdent [8]byte, payload pld.Payload) {
+func (clt *client) handleUnauthenticated(reqIdent [8]byte) {
+	clt.requestManager.Fail(reqIdent, webwire.UnauthenticatedErr{})
+}
+
+func (clt *client) handlePayloadTooLarge(reqIdent [8]byte) {
+	clt.requestManager.Fail(reqIdent, webwire.PayloadTooLargeErr{})
+}
+
+func (clt *client) handleRateLimited(reqIdent [8]byte) {
+	clt.requestManager.Fail(reqIdent, webwire.RateLimitedErr{})
+}
+
+func (clt *client) handleObserver(reqIdent [8]byte) {
+	clt.requestManager.Fail(reqIdent, webwire.ObserverErr{})
+}
+
+func (clt *client) handleProtocolError(reqIdent [8]byte) {
+	clt.requestManager.Fail(reqIdent, webwire.NewProtocolErr(fmt.Errorf(
+		"Request rejected by the server due to a protocol violation",
+	)))
+}
+
+// handleReply fulfills the request associated with the given identifier.
+// The payload encoding is explicitly set according to the type
+// of the reply message to not rely on the zero-value of the encoding type
+func (clt *client) handleReply(
+	reqIdent [8]byte,
+	encoding pld.Encoding,
+	payload pld.Payload,
+) {
+	payload.Encoding = encoding
 	clt.requestManager.Fulfill(reqIdent, payload)
 }
 
@@ -83,21 +208,18 @@ func (clt *client) handleMessage(message []byte) error {
 		return nil
 	}
 
-	var parsedMsg msg.Message
-	typeDetermined, err := parsedMsg.Parse(message)
-	if !typeDetermined {
-		return fmt.Errorf("Couldn't determine message type")
-	} else if err != nil {
+	parsedMsg, err := msg.Parse(message)
+	if err != nil {
 		return err
 	}
 
 	switch parsedMsg.Type {
 	case msg.MsgReplyBinary:
-		clt.handleReply(parsedMsg.Identifier, parsedMsg.Payload)
+		clt.handleReply(parsedMsg.Identifier, pld.Binary, parsedMsg.Payload)
 	case msg.MsgReplyUtf8:
-		clt.handleReply(parsedMsg.Identifier, parsedMsg.Payload)
+		clt.handleReply(parsedMsg.Identifier, pld.Utf8, parsedMsg.Payload)
 	case msg.MsgReplyUtf16:
-		clt.handleReply(parsedMsg.Identifier, parsedMsg.Payload)
+		clt.handleReply(parsedMsg.Identifier, pld.Utf16, parsedMsg.Payload)
 	case msg.MsgReplyShutdown:
 		clt.handleReplyShutdown(parsedMsg.Identifier)
 	case msg.MsgSessionNotFound:
@@ -106,6 +228,16 @@ func (clt *client) handleMessage(message []byte) error {
 		clt.handleMaxSessConnsReached(parsedMsg.Identifier)
 	case msg.MsgSessionsDisabled:
 		clt.handleSessionsDisabled(parsedMsg.Identifier)
+	case msg.MsgReplyUnauthenticated:
+		clt.handleUnauthenticated(parsedMsg.Identifier)
+	case msg.MsgReplyProtocolError:
+		clt.handleProtocolError(parsedMsg.Identifier)
+	case msg.MsgReplyPayloadTooLarge:
+		clt.handlePayloadTooLarge(parsedMsg.Identifier)
+	case msg.MsgReplyRateLimited:
+		clt.handleRateLimited(parsedMsg.Identifier)
+	case msg.MsgReplyObserver:
+		clt.handleObserver(parsedMsg.Identifier)
 	case msg.MsgErrorReply:
 		// The message name contains the error code in case of
 		// error reply messages, while the UTF8 encoded error message is
@@ -114,21 +246,58 @@ func (clt *client) handleMessage(message []byte) error {
 			parsedMsg.Identifier,
 			parsedMsg.Name,
 			string(parsedMsg.Payload.Data),
+			nil,
+		)
+	case msg.MsgErrorReplyPayload:
+		// The error message is contained in a separate field in case of
+		// error reply messages carrying a payload
+		clt.handleFailure(
+			parsedMsg.Identifier,
+			parsedMsg.Name,
+			parsedMsg.ErrorMessage,
+			&webwire.EncodedPayload{
+				Payload: parsedMsg.Payload,
+				Codec:   clt.codec,
+			},
 		)
 	case msg.MsgInternalError:
-		clt.handleInternalError(parsedMsg.Identifier)
+		// Internal error replies may optionally contain
+		// the UTF8 encoded error message in the payload
+		clt.handleInternalError(
+			parsedMsg.Identifier,
+			string(parsedMsg.Payload.Data),
+		)
 
 	case msg.MsgSignalBinary:
 		fallthrough
 	case msg.MsgSignalUtf8:
 		fallthrough
 	case msg.MsgSignalUtf16:
-		clt.impl.OnSignal(webwire.NewMessageWrapper(&parsedMsg))
+		clt.impl.OnSignal(
+			webwire.NewCodecMessageWrapper(parsedMsg, clt.codec),
+		)
+
+	case msg.MsgServerRequestBinary:
+		fallthrough
+	case msg.MsgServerRequestUtf8:
+		fallthrough
+	case msg.MsgServerRequestUtf16:
+		go clt.handleServerRequest(parsedMsg)
 
 	case msg.MsgSessionCreated:
 		clt.handleSessionCreated(parsedMsg.Payload)
 	case msg.MsgSessionClosed:
-		clt.handleSessionClosed()
+		// The payload contains the optional UTF8 encoded reason
+		clt.handleSessionClosed(string(parsedMsg.Payload.Data))
+	case msg.MsgSessionInfoUpdated:
+		clt.handleSessionInfoUpdated(parsedMsg.Payload)
+	case msg.MsgSessionUpdated:
+		clt.handleSessionUpdated(parsedMsg.Payload)
+	case msg.MsgHandshake:
+		// The message name contains the protocol version in case of
+		// handshake messages, while the flags are contained
+		// in the message payload
+		clt.handleHandshake(parsedMsg.Name, parsedMsg.Payload.Data)
 	default:
 		clt.warningLog.Printf(
 			"Strange message type received: '%d'\n",
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+
+	webwire "github.com/qbeon/webwire-go"
+	msg "github.com/qbeon/webwire-go/message"
+)
+
+// handleServerRequest invokes the OnServerRequest hook
+// and replies to the server with either the returned payload or error
+func (clt *client) handleServerRequest(message *msg.Message) {
+	replyPayload, err := clt.impl.OnServerRequest(
+		context.Background(),
+		webwire.NewCodecMessageWrapper(message, clt.codec),
+	)
+
+	var reply []byte
+	switch err := err.(type) {
+	case nil:
+		encoding := webwire.EncodingBinary
+		var data []byte
+		if replyPayload != nil {
+			encoding = replyPayload.Encoding()
+			data = replyPayload.Data()
+		}
+		reply = msg.NewClientReplyMessage(message.Identifier, encoding, data)
+	case webwire.ReqErr:
+		reply = clt.newServerRequestErrorReply(message.Identifier, &err)
+	case *webwire.ReqErr:
+		reply = clt.newServerRequestErrorReply(message.Identifier, err)
+	default:
+		// Don't expose internal errors to the server
+		clt.errorLog.Printf("Server request handler failed: %s", err)
+		reply = msg.NewClientInternalErrorReplyMessage(message.Identifier, "")
+	}
+
+	if err := clt.conn.Write(reply); err != nil {
+		clt.warningLog.Printf("Couldn't reply to server request: %s", err)
+	}
+}
+
+// newServerRequestErrorReply composes an error reply to a server request
+// falling back to an internal error reply if the given request error
+// is nil or has an invalid error code
+func (clt *client) newServerRequestErrorReply(
+	identifier [8]byte,
+	err *webwire.ReqErr,
+) []byte {
+	if err == nil || !msg.IsValidErrorCode(err.Code) {
+		clt.errorLog.Printf(
+			"Server request handler returned an invalid request error: %v",
+			err,
+		)
+		return msg.NewClientInternalErrorReplyMessage(identifier, "")
+	}
+	return msg.NewClientErrorReplyMessage(identifier, err.Code, err.Message)
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"fmt"
+	"time"
+)
+
+// startHeartbeat makes the pong handler extend the read deadline
+// and periodically sends ping frames to the server in a separate goroutine
+// until the stop channel is closed. Must be called before the connection
+// is read from
+func (clt *client) startHeartbeat(stop chan struct{}) error {
+	clt.conn.OnPong(func(string) error {
+		if err := clt.conn.SetReadDeadline(
+			time.Now().Add(clt.heartbeatTimeout),
+		); err != nil {
+			return fmt.Errorf(
+				"Couldn't set read deadline in Pong handler: %s",
+				err,
+			)
+		}
+		return nil
+	})
+	if err := clt.conn.SetReadDeadline(
+		time.Now().Add(clt.heartbeatTimeout),
+	); err != nil {
+		return fmt.Errorf("Couldn't set read deadline: %s", err)
+	}
+
+	go func() {
+		heartbeatTicker := time.NewTicker(clt.heartbeatInterval)
+		defer heartbeatTicker.Stop()
+		for {
+			select {
+			case <-heartbeatTicker.C:
+				if err := clt.conn.WritePing(
+					nil,
+					time.Now().Add(clt.heartbeatInterval),
+				); err != nil {
+					clt.warningLog.Printf(
+						"Couldn't write ping frame: %s",
+						err,
+					)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
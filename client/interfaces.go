@@ -9,16 +9,31 @@ import (
 // Client represents a webwire client instance
 type Client interface {
 	// Status returns the current client status
-	// which is either Disabled, Disconnected or Connected.
+	// which is either Disabled, Disconnected, Reconnecting or Connected.
 	// The client is considered disabled when it was manually closed
 	// through client.Close, while disconnected is considered
 	// a temporary connection loss.
+	// Reconnecting is reported during each automatic reconnection attempt.
 	// A disabled client won't autoconnect until enabled again
 	Status() Status
 
+	// StatusChanges returns a channel receiving the new client status
+	// whenever it changes, including connection losses and
+	// reconnections performed by autoconnect.
+	// Each reconnection attempt is reported as Reconnecting followed by
+	// either Connected or, if the attempt failed, Disconnected.
+	// Changes are buffered and the oldest buffered change is dropped
+	// if the channel isn't read fast enough, thus a slow consumer
+	// never blocks the client but always receives the latest status.
+	// There's only a single channel per client instance
+	StatusChanges() <-chan Status
+
 	// Connect connects the client to the configured server and
 	// returns an error in case of a connection failure.
 	// Automatically tries to restore the previous session.
+	// If the session couldn't be restored then the connection remains
+	// established as anonymous and a webwire.SessionRestoreErr error
+	// wrapping the cause (such as webwire.SessNotFoundErr) is returned.
 	// Enables autoconnect if it was previously disabled
 	Connect() error
 
@@ -27,17 +42,24 @@ type Client interface {
 	// It blocks until either a response is received
 	// or the request fails or times out.
 	// Request will respect cancelable and timed contexts,
-	// nil contexts are also supported
+	// nil contexts are also supported.
+	// The encoding of the returned reply payload always corresponds
+	// to the encoding of the reply sent by the server, binary replies
+	// are explicitly marked as webwire.EncodingBinary
 	Request(
 		ctx context.Context,
 		name string,
 		payload webwire.Payload,
 	) (webwire.Payload, error)
 
-	// Signal sends a signal containing the given payload to the server
+	// Signal sends a signal containing the given payload to the server.
+	// The signal is framed as either a binary, UTF8 or UTF16 signal message
+	// according to the encoding of the given payload,
+	// signals without a payload are framed as binary signals
 	Signal(name string, payload webwire.Payload) error
 
-	// Session returns an exact copy of the session object,
+	// Session returns an exact copy of the session object including
+	// its creation and last lookup time,
 	// otherwise returns nil if there's currently no session
 	Session() *webwire.Session
 
@@ -45,6 +67,29 @@ type Client interface {
 	// in the form of an empty interface to be casted to either concrete type
 	SessionInfo(fieldName string) interface{}
 
+	// SetSession assigns the given session to the client locally
+	// without contacting the server. It's meant for testing
+	// and offline scenarios. The session is verified by the server
+	// when the client connects the next time and is reset if the server
+	// fails to restore it. Fails if the client is currently connected
+	// to prevent the client from silently desynchronizing from the server
+	SetSession(session *webwire.Session) error
+
+	// ClearSession resets the current session of the client locally
+	// without contacting the server. Fails if the client is currently
+	// connected, use CloseSession to close the session on the server instead
+	ClearSession() error
+
+	// AutoconnectEnabled returns true if the client currently
+	// automatically (re)connects to the server
+	AutoconnectEnabled() bool
+
+	// SetAutoconnect enables or disables automatic (re)connection
+	// at runtime. Autoconnect disabled through SetAutoconnect remains disabled
+	// even after calling Connect until it's enabled again.
+	// Disabling autoconnect stops any ongoing reconnection attempts
+	SetAutoconnect(enabled bool)
+
 	// PendingRequests returns the number of currently pending requests
 	PendingRequests() int
 
@@ -52,6 +97,13 @@ type Client interface {
 	// Fails if a session is currently already active
 	RestoreSession(sessionKey []byte) error
 
+	// RestoreSessionWithProof tries to restore the previously opened session
+	// providing an additional proof (such as a signed token or a device
+	// fingerprint) to be verified by the server.
+	// The proof is reused during automatic session restorations.
+	// Fails if a session is currently already active
+	RestoreSessionWithProof(sessionKey, proof []byte) error
+
 	// CloseSession disables the currently active session
 	// and acknowledges the server if connected.
 	// The session will be destroyed if this is it's last connection remaining.
@@ -68,15 +120,48 @@ type Client interface {
 type Implementation interface {
 	// OnDisconnected is invoked when the client is disconnected
 	// from the server for any reason.
-	OnDisconnected()
+	// The error is a webwire.ConnClosedErr error carrying the close code
+	// and reason if the server closed the connection
+	// (for example through webwire.AbortConnection),
+	// otherwise it's a webwire.DisconnectedErr error
+	OnDisconnected(err error)
+
+	// OnReconnectionFailed is invoked when autoconnect gave up after
+	// reaching the configured maximum number of reconnection attempts.
+	// The client is disabled and won't autoconnect
+	// until it's connected manually again
+	OnReconnectionFailed()
 
 	// OnSignal is invoked when the client receives a signal from the server
 	OnSignal(message webwire.Message)
 
+	// OnServerRequest is invoked when the client receives a request
+	// from the server. The returned payload is sent back to the server
+	// as the reply. Returning a webwire.ReqErr error replies with
+	// the error code and message, any other error is reported
+	// to the server as an internal client error.
+	// It's invoked in a separate goroutine to not block the reception
+	// of other messages
+	OnServerRequest(
+		ctx context.Context,
+		message webwire.Message,
+	) (webwire.Payload, error)
+
 	// OnSessionCreated is invoked when the client was assigned a new session
 	OnSessionCreated(*webwire.Session)
 
+	// OnSessionCreationFailed is invoked when the client was assigned
+	// a new session by the server but failed decoding it,
+	// in which case the client remains without the new session
+	OnSessionCreationFailed(err error)
+
 	// OnSessionClosed is invoked when the client's session was closed
-	// either by the server or the client itself
-	OnSessionClosed()
+	// either by the server or the client itself.
+	// The reason is provided by the server and is empty if none was given
+	OnSessionClosed(reason string)
+
+	// OnSessionInfoChanged is invoked when the info of the client's session
+	// was updated by the server either through UpdateSessionInfo
+	// or UpdateSession
+	OnSessionInfoChanged(info webwire.SessionInfo)
 }
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"sync"
 
 	webwire "github.com/qbeon/webwire-go"
@@ -31,25 +32,48 @@ func NewClient(
 		autoconnect = autoconnectDisabled
 	}
 
+	// Queue requests while reconnecting only if enabled
+	var reconnQueue chan struct{}
+	if opts.QueueWhileReconnecting == webwire.Enabled {
+		reconnQueue = make(chan struct{}, opts.ReconnectionQueueSize)
+	}
+
 	// Initialize new client
 	newClt := &client{
 		serverAddr:        serverAddress,
 		impl:              implementation,
 		sessionInfoParser: opts.SessionInfoParser,
+		sessionCodec:      opts.SessionCodec,
+		codec:             opts.Codec,
 		status:            Disconnected,
+		statusChanges:     make(chan Status, statusChangesBufferSize),
 		defaultReqTimeout: opts.DefaultRequestTimeout,
-		reconnInterval:    opts.ReconnectionInterval,
+		reconnBackoff: reconnectionBackoff{
+			initial:    opts.ReconnectionInterval,
+			max:        opts.ReconnectionMaxInterval,
+			multiplier: opts.ReconnectionMultiplier,
+			jitter:     opts.ReconnectionJitter,
+		},
+		maxReconnAttempts: opts.MaxReconnectionAttempts,
+		heartbeat:         opts.Heartbeat,
+		heartbeatInterval: opts.HeartbeatInterval,
+		heartbeatTimeout:  opts.HeartbeatTimeout,
+		tlsConfig:         opts.TLSConfig,
+		handshakeTimeout:  opts.HandshakeTimeout,
+		proxy:             opts.Proxy,
 		autoconnect:       autoconnect,
 		sessionLock:       sync.RWMutex{},
-		session:           nil,
+		session:           initialSession(opts.RestoreSessionKey),
 		apiLock:           sync.RWMutex{},
 		backReconn:        newDam(),
 		connecting:        false,
 		connectingLock:    sync.RWMutex{},
+		reconnQueue:       reconnQueue,
 		connectLock:       sync.Mutex{},
-		conn:              webwire.NewSocket(),
+		conn:              newSocket(opts),
 		readerClosing:     make(chan bool, 1),
-		requestManager:    reqman.NewRequestManager(),
+		handshake:         make(chan error, 1),
+		requestManager:    reqman.NewRequestManager(opts.MaxPendingRequests),
 		warningLog:        opts.WarnLog,
 		errorLog:          opts.ErrorLog,
 	}
@@ -65,3 +89,35 @@ func NewClient(
 
 	return newClt
 }
+
+// initialSession returns the session to be restored on connection
+// identified by the given key or nil if the key is empty
+func initialSession(key string) *webwire.Session {
+	if key == "" {
+		return nil
+	}
+	return &webwire.Session{Key: key}
+}
+
+// newSocket creates the socket of the client dialing the server
+// according to the given options and transmitting
+// the client identifier and the observer mode to the server if any
+func newSocket(opts Options) webwire.Socket {
+	header := http.Header{}
+	if opts.ClientIdentifier != "" {
+		header.Set(webwire.ClientIdentifierHeader, opts.ClientIdentifier)
+	}
+	if opts.ObserverMode == webwire.Enabled {
+		header.Set(webwire.ObserverHeader, "true")
+	}
+	return webwire.NewDialingSocket(webwire.DialerOptions{
+		Header:           header,
+		TLSConfig:        opts.TLSConfig,
+		HandshakeTimeout: opts.HandshakeTimeout,
+		Proxy:            opts.Proxy,
+
+		Compression:          opts.Compression == webwire.Enabled,
+		CompressionLevel:     opts.CompressionLevel,
+		CompressionThreshold: opts.CompressionThreshold,
+	})
+}
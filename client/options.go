@@ -1,7 +1,11 @@
 package client
 
 import (
+	"compress/flate"
+	"crypto/tls"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
 	"time"
 
@@ -13,8 +17,15 @@ type Options struct {
 	// SessionInfoParser defines the optional session info parser function
 	SessionInfoParser webwire.SessionInfoParser
 
+	// SessionCodec defines the codec used to decode the session objects
+	// received from the server which must match the codec of the server.
+	// If undefined then webwire.JSONSessionCodec is applied
+	SessionCodec webwire.SessionCodec
+
 	// DefaultRequestTimeout defines the default request timeout duration
-	// used by client.Request and client.RestoreSession
+	// used by client.Request and client.RestoreSession.
+	// If undefined (zero or negative) then the default value
+	// of 60 seconds is applied
 	DefaultRequestTimeout time.Duration
 
 	// Autoconnect defines whether the autoconnect feature is to be enabled.
@@ -33,6 +44,136 @@ type Options struct {
 	// If undefined then the default value of 2 seconds is applied
 	ReconnectionInterval time.Duration
 
+	// ReconnectionMultiplier defines the factor the reconnection interval
+	// is multiplied by after each failed reconnection attempt.
+	// The interval is reset to ReconnectionInterval once the client
+	// is connected again.
+	// If undefined (1 or lower) then the interval remains fixed
+	ReconnectionMultiplier float64
+
+	// ReconnectionMaxInterval defines the maximum interval
+	// the reconnection interval can grow to.
+	// If undefined (zero) then the interval grows indefinitely
+	ReconnectionMaxInterval time.Duration
+
+	// ReconnectionJitter defines the fraction (between 0 and 1)
+	// by which each reconnection interval is randomly reduced
+	// to prevent many clients from reconnecting simultaneously.
+	// If undefined (zero) then no jitter is applied
+	ReconnectionJitter float64
+
+	// MaxReconnectionAttempts defines the maximum number of consecutive
+	// failed reconnection attempts after which autoconnect gives up.
+	// Once the limit is reached the client is disabled
+	// and Implementation.OnReconnectionFailed is invoked.
+	// If undefined (zero) then autoconnect retries indefinitely
+	MaxReconnectionAttempts uint
+
+	// QueueWhileReconnecting defines whether requests are to be queued
+	// while autoconnect is reestablishing a lost connection.
+	// Queued requests are sent once the connection is reestablished
+	// and requests whose transmission failed due to a connection loss
+	// are queued again instead of failing immediately.
+	// The default request timeout and the context of each request cover
+	// the time spent in the queue, requests exceeding the queue size
+	// fail immediately with a webwire.DisconnectedErr error.
+	//
+	// QueueWhileReconnecting is disabled by default
+	QueueWhileReconnecting webwire.OptionValue
+
+	// ReconnectionQueueSize defines the maximum number of requests queued
+	// while reconnecting if QueueWhileReconnecting is enabled.
+	// If undefined (zero) then the default value of 64 is applied
+	ReconnectionQueueSize uint
+
+	// MaxPendingRequests defines the maximum number of concurrently pending
+	// requests. Requests exceeding the limit are rejected
+	// with a webwire.TooManyPendingErr error.
+	// If undefined (zero) then the number of pending requests is unlimited
+	MaxPendingRequests uint
+
+	// Heartbeat defines whether the client is to periodically send
+	// ping frames to the server to keep the connection alive.
+	// The connection is considered lost and automatically reestablished
+	// (if autoconnect is enabled) when no pong frame is received
+	// within the heartbeat timeout.
+	//
+	// Heartbeat is disabled by default
+	Heartbeat webwire.OptionValue
+
+	// HeartbeatInterval defines the interval at which ping frames are sent.
+	// If undefined then the default value of 30 seconds is applied
+	HeartbeatInterval time.Duration
+
+	// HeartbeatTimeout defines the duration after which the connection
+	// is closed if no pong frame is received.
+	// If undefined then the default value of 60 seconds is applied
+	HeartbeatTimeout time.Duration
+
+	// Codec defines the optional payload codec used to decode payloads
+	// through webwire.Payload.DecodeInto which must match the codec
+	// of the server. Connecting to a server using another codec fails
+	// with a webwire.CodecMismatchErr error
+	Codec webwire.Codec
+
+	// ClientIdentifier defines the optional human-readable identifier
+	// of the client (such as the device name or the app version)
+	// sent to the server during the connection establishment
+	// and exposed through webwire.Connection.ClientIdentifier
+	ClientIdentifier string
+
+	// RestoreSessionKey defines the key of the session to be restored
+	// when the client connects. If the session can't be restored
+	// the client remains connected as anonymous and client.Connect returns
+	// a webwire.SessionRestoreErr error wrapping the cause,
+	// which is a webwire.SessNotFoundErr error if the session doesn't exist
+	RestoreSessionKey string
+
+	// ObserverMode defines whether the client is to connect
+	// as a read-only observer, which is useful for monitoring dashboards.
+	// The server rejects all requests of observers
+	// with a webwire.ObserverErr error, ignores their signals
+	// and doesn't allow them to create sessions,
+	// observers only receive signals from the server.
+	//
+	// ObserverMode is disabled by default
+	ObserverMode webwire.OptionValue
+
+	// TLSConfig defines the TLS configuration used when connecting
+	// to the server, for example to trust self-signed certificates
+	// or pin certificate authorities.
+	// The client connects over TLS (wss) if it's defined
+	TLSConfig *tls.Config
+
+	// HandshakeTimeout defines the duration for the connection
+	// establishment to complete.
+	// If undefined then the default value of 45 seconds is applied
+	HandshakeTimeout time.Duration
+
+	// Proxy defines the optional function returning the HTTP proxy
+	// to connect through for a given request.
+	// If undefined then the proxy is determined by the environment variables
+	// (see http.ProxyFromEnvironment)
+	Proxy func(*http.Request) (*url.URL, error)
+
+	// Compression defines whether permessage-deflate compression
+	// is to be negotiated with the server during the connection
+	// establishment. Messages are only compressed if the server
+	// has compression enabled as well.
+	//
+	// Compression is disabled by default
+	Compression webwire.OptionValue
+
+	// CompressionLevel defines the flate compression level
+	// of outgoing messages (see compress/flate).
+	// If undefined then flate.BestSpeed is applied
+	CompressionLevel int
+
+	// CompressionThreshold defines the minimum size in bytes of outgoing
+	// messages to be compressed to avoid wasting time on tiny messages.
+	// If undefined then the default value of 256 bytes is applied
+	CompressionThreshold int
+
 	// WarnLog defines the warn logging output target
 	WarnLog *log.Logger
 
@@ -46,6 +187,10 @@ func (opts *Options) SetDefaults() {
 		opts.SessionInfoParser = webwire.GenericSessionInfoParser
 	}
 
+	if opts.SessionCodec == nil {
+		opts.SessionCodec = webwire.JSONSessionCodec{}
+	}
+
 	if opts.DefaultRequestTimeout < 1 {
 		opts.DefaultRequestTimeout = 60 * time.Second
 	}
@@ -58,6 +203,54 @@ func (opts *Options) SetDefaults() {
 		opts.ReconnectionInterval = 2 * time.Second
 	}
 
+	if opts.ReconnectionJitter > 1 {
+		opts.ReconnectionJitter = 1
+	}
+
+	if opts.QueueWhileReconnecting == webwire.OptionUnset {
+		opts.QueueWhileReconnecting = webwire.Disabled
+	}
+
+	if opts.ReconnectionQueueSize < 1 {
+		opts.ReconnectionQueueSize = 64
+	}
+
+	if opts.Heartbeat == webwire.OptionUnset {
+		opts.Heartbeat = webwire.Disabled
+	}
+
+	if opts.HeartbeatInterval < 1 {
+		opts.HeartbeatInterval = 30 * time.Second
+	}
+
+	if opts.HeartbeatTimeout < 1 {
+		opts.HeartbeatTimeout = 60 * time.Second
+	}
+
+	if opts.HandshakeTimeout < 1 {
+		opts.HandshakeTimeout = 45 * time.Second
+	}
+
+	if opts.Proxy == nil {
+		opts.Proxy = http.ProxyFromEnvironment
+	}
+
+	if opts.ObserverMode == webwire.OptionUnset {
+		opts.ObserverMode = webwire.Disabled
+	}
+
+	if opts.Compression == webwire.OptionUnset {
+		opts.Compression = webwire.Disabled
+	}
+
+	if opts.CompressionLevel == 0 {
+		opts.CompressionLevel = flate.BestSpeed
+	}
+
+	if opts.CompressionThreshold < 1 {
+		opts.CompressionThreshold = 256
+	}
+
 	// Create default loggers to std-out/err when no loggers are specified
 	if opts.WarnLog == nil {
 		opts.WarnLog = log.New(
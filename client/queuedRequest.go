@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	webwire "github.com/qbeon/webwire-go"
+)
+
+// requeueDelay defines the duration a request whose transmission failed
+// waits for the reader to detect the connection loss before it's requeued
+const requeueDelay = 10 * time.Millisecond
+
+// queuedRequest sends a request queueing it while the client is reconnecting.
+// Requests whose transmission failed due to a connection loss are requeued
+// until either the reply is received or the request times out.
+// The default request timeout covers both the time spent in the queue
+// and the time spent awaiting the reply
+func (clt *client) queuedRequest(
+	ctx context.Context,
+	name string,
+	payload webwire.Payload,
+) (webwire.Payload, error) {
+	deadline := time.Now().Add(clt.defaultReqTimeout)
+
+	for {
+		if err := clt.awaitReconnection(ctx, deadline); err != nil {
+			return nil, err
+		}
+
+		reply, err := clt.sendRequest(
+			ctx,
+			scanPayloadEncoding(payload),
+			name,
+			payload,
+			time.Until(deadline),
+		)
+		if _, isTransErr := err.(webwire.ReqTransErr); !isTransErr ||
+			atomic.LoadInt32(&clt.autoconnect) != autoconnectEnabled {
+			return reply, err
+		}
+
+		// The connection was lost during the transmission,
+		// requeue the request once the loss is detected
+		select {
+		case <-ctx.Done():
+			return nil, webwire.TranslateContextError(ctx.Err())
+		case <-time.After(requeueDelay):
+		}
+	}
+}
+
+// awaitReconnection blocks until the client is connected occupying a slot
+// of the reconnection queue while autoconnect is reconnecting.
+// Returns a webwire.DisconnectedErr error if the queue is full
+func (clt *client) awaitReconnection(
+	ctx context.Context,
+	deadline time.Time,
+) error {
+	timeout := time.Until(deadline)
+	if timeout < 1 {
+		return webwire.NewTimeoutErr(fmt.Errorf("timed out"))
+	}
+	if atomic.LoadInt32(&clt.status) == Connected {
+		return nil
+	}
+
+	select {
+	case clt.reconnQueue <- struct{}{}:
+		defer func() { <-clt.reconnQueue }()
+	default:
+		return webwire.NewDisconnectedErr(fmt.Errorf(
+			"Reconnection queue is full (%d requests)",
+			cap(clt.reconnQueue),
+		))
+	}
+
+	return clt.tryAutoconnect(ctx, timeout)
+}
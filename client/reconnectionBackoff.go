@@ -0,0 +1,37 @@
+package client
+
+import (
+	"math/rand"
+	"time"
+)
+
+// reconnectionBackoff defines the delays between reconnection attempts
+type reconnectionBackoff struct {
+	initial    time.Duration
+	max        time.Duration
+	multiplier float64
+	jitter     float64
+}
+
+// next returns the delay to be applied after the given one.
+// The delay is multiplied by the multiplier and limited to the maximum
+// interval if any. The delay remains fixed if the multiplier is undefined
+func (bo reconnectionBackoff) next(delay time.Duration) time.Duration {
+	if bo.multiplier <= 1 {
+		return delay
+	}
+	next := time.Duration(float64(delay) * bo.multiplier)
+	if bo.max > 0 && next > bo.max {
+		return bo.max
+	}
+	return next
+}
+
+// jittered returns the given delay randomly reduced by up to
+// the jitter fraction of it to spread simultaneous reconnection attempts
+func (bo reconnectionBackoff) jittered(delay time.Duration) time.Duration {
+	if bo.jitter <= 0 {
+		return delay
+	}
+	return delay - time.Duration(rand.Float64()*bo.jitter*float64(delay))
+}
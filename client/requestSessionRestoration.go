@@ -2,7 +2,6 @@ package client
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	webwire "github.com/qbeon/webwire-go"
@@ -12,29 +11,41 @@ import (
 
 // requestSessionRestoration sends a session restoration request
 // and decodes the session object from the received reply.
+// A verified session restoration request is sent if a proof is provided.
 // Expects the client to be connected beforehand
-func (clt *client) requestSessionRestoration(sessionKey []byte) (
+func (clt *client) requestSessionRestoration(sessionKey, proof []byte) (
 	*webwire.Session,
 	error,
 ) {
-	reply, err := clt.sendNamelessRequest(
-		context.Background(),
-		msg.MsgRestoreSession,
-		pld.Payload{
-			Encoding: webwire.EncodingBinary,
-			Data:     sessionKey,
-		},
-		clt.defaultReqTimeout,
-	)
+	var reply webwire.Payload
+	var err error
+	if len(proof) > 0 {
+		reply, err = clt.sendVerifiedSessionRestoreRequest(
+			context.Background(),
+			string(sessionKey),
+			proof,
+			clt.defaultReqTimeout,
+		)
+	} else {
+		reply, err = clt.sendNamelessRequest(
+			context.Background(),
+			msg.MsgRestoreSession,
+			pld.Payload{
+				Encoding: webwire.EncodingBinary,
+				Data:     sessionKey,
+			},
+			clt.defaultReqTimeout,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// Unmarshal JSON encoded session object
-	var encodedSessionObj webwire.JSONEncodedSession
-	if err := json.Unmarshal(reply.Data(), &encodedSessionObj); err != nil {
+	// Decode the session object
+	encodedSessionObj, err := clt.sessionCodec.Decode(reply.Data())
+	if err != nil {
 		return nil, fmt.Errorf(
-			"Couldn't unmarshal restored session from reply('%s'): %s",
+			"Couldn't decode restored session from reply('%s'): %s",
 			string(reply.Data()),
 			err,
 		)
@@ -47,8 +58,10 @@ func (clt *client) requestSessionRestoration(sessionKey []byte) (
 	}
 
 	return &webwire.Session{
-		Key:      encodedSessionObj.Key,
-		Creation: encodedSessionObj.Creation,
-		Info:     decodedInfo,
+		Key:        encodedSessionObj.Key,
+		Creation:   encodedSessionObj.Creation,
+		LastLookup: encodedSessionObj.LastLookup,
+		Expiration: encodedSessionObj.ExpirationTime(),
+		Info:       decodedInfo,
 	}, nil
 }
@@ -15,7 +15,10 @@ func (clt *client) sendNamelessRequest(
 	payload pld.Payload,
 	timeout time.Duration,
 ) (webwire.Payload, error) {
-	request := clt.requestManager.Create(timeout)
+	request, err := clt.requestManager.Create(timeout)
+	if err != nil {
+		return nil, err
+	}
 	reqIdentifier := request.Identifier()
 
 	msg := msg.NewNamelessRequestMessage(
@@ -26,6 +29,7 @@ func (clt *client) sendNamelessRequest(
 
 	// Send request
 	if err := clt.conn.Write(msg); err != nil {
+		request.Discard()
 		return nil, webwire.NewReqTransErr(err)
 	}
 
@@ -17,7 +17,7 @@ func (clt *client) sendRequest(
 	timeout time.Duration,
 ) (webwire.Payload, error) {
 	// Require either a name or a payload or both
-	if len(name) < 1 && (payload == nil || len(payload.Data()) < 1) {
+	if len(name) < 1 && (payload == nil || payload.IsEmpty()) {
 		return nil, webwire.NewProtocolErr(
 			fmt.Errorf("Invalid request, request message requires " +
 				"either a name, a payload or both but is missing both",
@@ -41,7 +41,10 @@ func (clt *client) sendRequest(
 	}
 
 	// Compose a message and register it
-	request := clt.requestManager.Create(timeout)
+	request, err := clt.requestManager.Create(timeout)
+	if err != nil {
+		return nil, err
+	}
 	reqIdentifier := request.Identifier()
 	msg := msg.NewRequestMessage(
 		reqIdentifier,
@@ -52,9 +55,16 @@ func (clt *client) sendRequest(
 
 	// Send request
 	if err := clt.conn.Write(msg); err != nil {
+		request.Discard()
 		return nil, webwire.NewReqTransErr(err)
 	}
 
 	// Block until request either times out or a response is received
-	return request.AwaitReply(ctx)
+	reply, err := request.AwaitReply(ctx)
+
+	// Make the reply decodable by the configured codec
+	if encoded, ok := reply.(*webwire.EncodedPayload); ok {
+		encoded.Codec = clt.codec
+	}
+	return reply, err
 }
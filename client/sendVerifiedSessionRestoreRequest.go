@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	webwire "github.com/qbeon/webwire-go"
+	msg "github.com/qbeon/webwire-go/message"
+)
+
+func (clt *client) sendVerifiedSessionRestoreRequest(
+	ctx context.Context,
+	sessionKey string,
+	proof []byte,
+	timeout time.Duration,
+) (webwire.Payload, error) {
+	if len(sessionKey) < 1 || len(sessionKey) > 255 {
+		return nil, webwire.NewProtocolErr(fmt.Errorf(
+			"Invalid session key length (%d), "+
+				"must be between 1 and 255 bytes",
+			len(sessionKey),
+		))
+	}
+
+	request, err := clt.requestManager.Create(timeout)
+	if err != nil {
+		return nil, err
+	}
+	reqIdentifier := request.Identifier()
+
+	msg := msg.NewVerifiedRestoreSessionMessage(
+		reqIdentifier,
+		sessionKey,
+		proof,
+	)
+
+	// Send request
+	if err := clt.conn.Write(msg); err != nil {
+		request.Discard()
+		return nil, webwire.NewReqTransErr(err)
+	}
+
+	// Block until request either times out or a response is received
+	return request.AwaitReply(ctx)
+}
@@ -0,0 +1,53 @@
+package client
+
+import "sync/atomic"
+
+// statusChangesBufferSize defines the number of status changes
+// buffered for slow consumers of the status changes channel
+const statusChangesBufferSize = 16
+
+// setStatus sets the current client status and publishes
+// the change to the status changes channel if the status changed.
+// The oldest buffered change is dropped if the buffer is full
+// to never block on slow consumers while keeping the latest status
+func (clt *client) setStatus(status Status) {
+	clt.statusLock.Lock()
+	defer clt.statusLock.Unlock()
+
+	if atomic.SwapInt32(&clt.status, status) == status {
+		return
+	}
+	clt.publishStatus(status)
+}
+
+// setReconnecting sets the current client status to reconnecting and
+// publishes the change unless the client is already connected,
+// otherwise connect would no longer recognize the established connection
+func (clt *client) setReconnecting() {
+	clt.statusLock.Lock()
+	defer clt.statusLock.Unlock()
+
+	if !atomic.CompareAndSwapInt32(&clt.status, Disconnected, Reconnecting) {
+		return
+	}
+	clt.publishStatus(Reconnecting)
+}
+
+// publishStatus publishes the given status to the status changes channel.
+// The status lock must be held by the caller
+func (clt *client) publishStatus(status Status) {
+	select {
+	case clt.statusChanges <- status:
+	default:
+		select {
+		case <-clt.statusChanges:
+		default:
+		}
+		clt.statusChanges <- status
+	}
+}
+
+// StatusChanges implements the Client interface
+func (clt *client) StatusChanges() <-chan Status {
+	return clt.statusChanges
+}
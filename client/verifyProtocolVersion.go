@@ -12,14 +12,26 @@ import (
 
 // verifyProtocolVersion requests the endpoint metadata
 // to verify the server is running a supported protocol version
+// and uses the same payload codec as the client if any
 func (clt *client) verifyProtocolVersion() error {
 	// Initialize HTTP client
 	var httpClient = &http.Client{
 		Timeout: time.Second * 10,
+		Transport: &http.Transport{
+			Proxy:               clt.proxy,
+			TLSClientConfig:     clt.tlsConfig,
+			TLSHandshakeTimeout: clt.handshakeTimeout,
+			DisableKeepAlives:   true,
+		},
+	}
+
+	scheme := "http"
+	if clt.tlsConfig != nil {
+		scheme = "https"
 	}
 
 	request, err := http.NewRequest(
-		"WEBWIRE", "http://"+clt.serverAddr+"/", nil,
+		"WEBWIRE", scheme+"://"+clt.serverAddr+"/", nil,
 	)
 	if err != nil {
 		panic(fmt.Errorf("Couldn't create HTTP metadata request: %s", err))
@@ -51,6 +63,7 @@ func (clt *client) verifyProtocolVersion() error {
 	// Unmarshal response
 	var metadata struct {
 		ProtocolVersion string `json:"protocol-version"`
+		Codec           string `json:"codec"`
 	}
 	if err := json.Unmarshal(encodedData, &metadata); err != nil {
 		return webwire.NewProtocolErr(fmt.Errorf(
@@ -68,5 +81,13 @@ func (clt *client) verifyProtocolVersion() error {
 		)
 	}
 
+	// Verify the server uses the same payload codec if one is configured
+	if clt.codec != nil && metadata.Codec != clt.codec.Name() {
+		return webwire.CodecMismatchErr{
+			ServerCodec: metadata.Codec,
+			ClientCodec: clt.codec.Name(),
+		}
+	}
+
 	return nil
 }
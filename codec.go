@@ -0,0 +1,21 @@
+package webwire
+
+import "encoding/json"
+
+// JSONCodec represents a payload codec encoding values in JSON
+type JSONCodec struct{}
+
+// Name implements the Codec interface
+func (JSONCodec) Name() string {
+	return "json"
+}
+
+// Marshal implements the Codec interface
+func (JSONCodec) Marshal(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Unmarshal implements the Codec interface
+func (JSONCodec) Unmarshal(data []byte, value interface{}) error {
+	return json.Unmarshal(data, value)
+}
@@ -1,6 +1,7 @@
 package webwire
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -11,11 +12,21 @@ import (
 	"golang.org/x/sync/semaphore"
 )
 
+// ClientIdentifierHeader represents the HTTP header the client identifier
+// is transmitted in during the connection establishment
+const ClientIdentifierHeader = "Webwire-Client-Identifier"
+
+// ObserverHeader represents the HTTP header a client declares itself
+// as a read-only observer in during the connection establishment
+const ObserverHeader = "Webwire-Observer"
+
 // ClientInfo represents basic information about a client connection
 type ClientInfo struct {
-	ConnectionTime time.Time
-	UserAgent      string
-	RemoteAddr     net.Addr
+	ConnectionTime   time.Time
+	UserAgent        string
+	RemoteAddr       net.Addr
+	ClientIdentifier string
+	Observer         bool
 }
 
 // connection represents a connected client connected to the server
@@ -23,10 +34,19 @@ type connection struct {
 	// options represents the options defined during the connection upgrade
 	options ConnectionOptions
 
-	// stateLock protects both isActive and tasks from concurrent access
+	// stateLock protects isActive, writeFailed and tasks
+	// from concurrent access
 	stateLock sync.RWMutex
 	isActive  bool
 
+	// writeFailed is set when writing a reply failed
+	// and prevents any further replies from being written
+	writeFailed bool
+
+	// disconnected ensures the OnClientDisconnected hook
+	// is invoked only once
+	disconnected sync.Once
+
 	// tasks represents the number of currently performed tasks
 	tasks int32
 
@@ -39,14 +59,33 @@ type connection struct {
 	// sock references the connection's socket
 	sock Socket
 
-	// sessionLock protects the session field from concurrent access
+	// sessionLock protects the session and unlinked fields
+	// from concurrent access
 	sessionLock sync.RWMutex
 
 	// session references the currently assigned session, can be null
 	session *Session
 
+	// unlinked is set once the connection is deregistered
+	// from the session registry and prevents any further registrations
+	unlinked bool
+
 	// info represents overall connection information
 	info ClientInfo
+
+	// stateStoreLock protects the state store from concurrent access
+	stateStoreLock sync.RWMutex
+
+	// stateStore keeps the values stored through SetState
+	stateStore map[string]interface{}
+
+	// ctx is cancelled when the connection is closed
+	// to allow long running handlers to bail early
+	ctx       context.Context
+	cancelCtx context.CancelFunc
+
+	// requests keeps track of the pending requests sent to the client
+	requests *serverRequestManager
 }
 
 // newConnection creates and returns a new client connection instance
@@ -70,6 +109,8 @@ func newConnection(
 		concurrencyLimit = int64(options.ConcurrencyLimit())
 	}
 
+	ctx, cancelCtx := context.WithCancel(context.Background())
+
 	return &connection{
 		options:      options,
 		stateLock:    sync.RWMutex{},
@@ -81,10 +122,13 @@ func newConnection(
 		sessionLock:  sync.RWMutex{},
 		session:      nil,
 		info: ClientInfo{
-			time.Now(),
-			userAgent,
-			remoteAddr,
+			ConnectionTime: time.Now(),
+			UserAgent:      userAgent,
+			RemoteAddr:     remoteAddr,
 		},
+		ctx:       ctx,
+		cancelCtx: cancelCtx,
+		requests:  newServerRequestManager(),
 	}
 }
 
@@ -97,6 +141,21 @@ func (con *connection) IsActive() bool {
 
 }
 
+// hasWriteFailed returns true if writing a reply to this connection failed
+func (con *connection) hasWriteFailed() bool {
+	con.stateLock.RLock()
+	writeFailed := con.writeFailed
+	con.stateLock.RUnlock()
+	return writeFailed
+}
+
+// setWriteFailed marks the connection as failed to be written to
+func (con *connection) setWriteFailed() {
+	con.stateLock.Lock()
+	con.writeFailed = true
+	con.stateLock.Unlock()
+}
+
 // registerTask increments the number of currently executed tasks
 func (con *connection) registerTask() {
 	con.stateLock.Lock()
@@ -132,10 +191,12 @@ func (con *connection) setSession(newSess *Session) {
 // unlink resets the connection and marks it as disconnected
 // preparing it for garbage collection
 func (con *connection) unlink() {
-	// Deregister session from active sessions registry
-	con.srv.sessionRegistry.deregister(con)
-
+	// Deregister session from active sessions registry.
+	// The session lock is held to prevent a concurrent session creation
+	// from registering the connection again
 	con.sessionLock.Lock()
+	con.unlinked = true
+	con.srv.sessionRegistry.deregister(con)
 	con.session = nil
 	con.sessionLock.Unlock()
 
@@ -148,21 +209,119 @@ func (con *connection) Info() ClientInfo {
 	return con.info
 }
 
+// ClientIdentifier implements the Connection interface
+func (con *connection) ClientIdentifier() string {
+	return con.info.ClientIdentifier
+}
+
+// IsObserver implements the Connection interface
+func (con *connection) IsObserver() bool {
+	return con.info.Observer
+}
+
 // Signal implements the Connection interface
 func (con *connection) Signal(name string, payload Payload) error {
-	return con.sock.Write(msg.NewSignalMessage(
+	// Require either a name or a payload or both
+	if len(name) < 1 && (payload == nil || payload.IsEmpty()) {
+		return NewProtocolErr(
+			fmt.Errorf("Invalid signal, signal message requires " +
+				"either a name, a payload or both but is missing both",
+			),
+		)
+	}
+
+	// Fallback to binary encoding if no payload is given
+	encoding := EncodingBinary
+	var data []byte
+	if payload != nil {
+		encoding = payload.Encoding()
+		data = payload.Data()
+	}
+
+	return con.sock.Write(msg.NewSignalMessage(name, encoding, data))
+}
+
+// Request implements the Connection interface
+func (con *connection) Request(
+	ctx context.Context,
+	name string,
+	payload Payload,
+) (Payload, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Require either a name or a payload or both
+	if len(name) < 1 && (payload == nil || payload.IsEmpty()) {
+		return nil, NewProtocolErr(
+			fmt.Errorf("Invalid request, request message requires " +
+				"either a name, a payload or both but is missing both",
+			),
+		)
+	}
+
+	if !con.IsActive() {
+		return nil, NewDisconnectedErr(fmt.Errorf(
+			"Can't send a request to a closed connection",
+		))
+	}
+
+	payloadEncoding := EncodingBinary
+	var payloadData []byte
+	if payload != nil {
+		payloadEncoding = payload.Encoding()
+		payloadData = payload.Data()
+	}
+
+	identifier, reply := con.requests.create()
+	if err := con.sock.Write(msg.NewServerRequestMessage(
+		identifier,
 		name,
-		payload.Encoding(),
-		payload.Data(),
-	))
+		payloadEncoding,
+		payloadData,
+	)); err != nil {
+		con.requests.deregister(identifier)
+		return nil, NewReqTransErr(err)
+	}
+
+	timeout := time.NewTimer(con.srv.options.ServerRequestTimeout)
+	defer timeout.Stop()
+
+	// Block until either the reply is received, the request timed out,
+	// the context is done or the connection is closed
+	select {
+	case reply := <-reply:
+		if reply.err != nil {
+			return nil, reply.err
+		}
+		return reply.payload, nil
+	case <-ctx.Done():
+		con.requests.deregister(identifier)
+		return nil, TranslateContextError(ctx.Err())
+	case <-timeout.C:
+		con.requests.deregister(identifier)
+		return nil, NewTimeoutErr(fmt.Errorf("timed out"))
+	case <-con.ctx.Done():
+		con.requests.deregister(identifier)
+		return nil, NewDisconnectedErr(fmt.Errorf(
+			"Connection closed before the reply was received",
+		))
+	}
 }
 
 // CreateSession implements the Connection interface
 func (con *connection) CreateSession(attachment SessionInfo) error {
 	if !con.srv.sessionsEnabled {
+		con.srv.warnLog.Println(
+			"Couldn't create session, sessions are disabled",
+		)
 		return SessionsDisabledErr{}
 	}
 
+	if con.info.Observer {
+		return ObserverErr{}
+	}
+
 	if !con.sock.IsConnected() {
 		return DisconnectedErr{
 			Cause: fmt.Errorf(
@@ -173,20 +332,57 @@ func (con *connection) CreateSession(attachment SessionInfo) error {
 
 	con.sessionLock.Lock()
 
-	// Abort if there's already another active session
-	if con.session != nil {
+	// Don't register sessions for connections that are already unlinked
+	if con.unlinked {
+		con.sessionLock.Unlock()
+		return DisconnectedErr{
+			Cause: fmt.Errorf(
+				"Can't create session on disconnected connection",
+			),
+		}
+	}
+
+	// Abort if there's another active session
+	// and replacing sessions is disabled
+	replacedSession := con.session
+	if replacedSession != nil &&
+		con.srv.options.ReplaceSessions != Enabled {
 		con.sessionLock.Unlock()
 		return fmt.Errorf(
 			"Another session (%s) on this client is already active",
-			con.session.Key,
+			replacedSession.Key,
 		)
 	}
 
-	// Create a new session
-	newSession := NewSession(attachment, con.srv.sessionKeyGen.Generate)
+	// Create a new session with a key unique among the active sessions
+	// registering the connection with it
+	newSession, remainingConns, err := con.srv.newSession(con, attachment)
+	if err != nil {
+		con.sessionLock.Unlock()
+		return err
+	}
 
-	// Try to notify about session creation
+	// Expiring sessions carry their expiration time for the session manager
+	// to persist it. Sliding expirations are refreshed and persisted again
+	// on each restoration of the session
+	if con.srv.options.SessionTTL > 0 {
+		newSession.Expiration = newSession.Creation.Add(
+			con.srv.options.SessionTTL,
+		)
+	}
+
+	// Notify the client about the closure of the replaced session
+	// and the creation of the new one before switching the sessions
+	// and keep the replaced session in case of a failure
+	if replacedSession != nil {
+		if err := con.notifySessionClosed(""); err != nil {
+			con.revertSessionCreation(replacedSession)
+			con.sessionLock.Unlock()
+			return err
+		}
+	}
 	if err := con.notifySessionCreated(&newSession); err != nil {
+		con.revertSessionCreation(replacedSession)
 		con.sessionLock.Unlock()
 		return fmt.Errorf(
 			"Couldn't notify client about the session creation: %s",
@@ -194,53 +390,119 @@ func (con *connection) CreateSession(attachment SessionInfo) error {
 		)
 	}
 
-	// Register the session
+	// Switch to the new session
 	con.session = &newSession
-
-	con.srv.sessionRegistry.register(con)
+	snapshot := newSession.Clone()
 	con.sessionLock.Unlock()
 
+	// Destroy the replaced session if this connection was its last one
+	if remainingConns == 0 {
+		con.closeReplacedSession(replacedSession.Key)
+	}
+
 	// Call session creation hook
-	if err := con.srv.sessionManager.OnSessionCreated(con); err != nil {
-		con.srv.errorLog.Printf("OnSessionCreated hook failed: %s", err)
+	if con.srv.options.AsyncSessionPersistence == Enabled {
+		con.srv.persistSessionAsync(con, snapshot)
+	} else if err := con.srv.onSessionCreated(con); err != nil {
+		con.srv.logSessionCreationError(con, snapshot.Key, err)
 	}
 
 	return nil
 }
 
+// revertSessionCreation moves the connection out of the newly created
+// session back to the given replaced session if any
+// after the session creation failed.
+// Expects the session lock to be held by the caller
+func (con *connection) revertSessionCreation(replacedSession *Session) {
+	if replacedSession != nil &&
+		con.srv.sessionRegistry.register(con) == nil {
+		return
+	}
+
+	// Either there was no replaced session or it concurrently
+	// reached its connection limit
+	con.srv.sessionRegistry.deregister(con)
+	con.session = nil
+}
+
+// closeReplacedSession destroys the session identified by the given key
+// through the OnSessionClosed session manager hook after it was replaced
+// on its last remaining connection
+func (con *connection) closeReplacedSession(replacedKey string) {
+	con.srv.sessionRegistry.dropMaxConns(replacedKey)
+	if err := con.srv.onSessionClosed(replacedKey); err != nil {
+		errCtx := newErrorContext(ErrOpSessionClosure, con)
+		errCtx.SessionKey = replacedKey
+		con.srv.logError(
+			errCtx,
+			err,
+			"OnSessionClosed hook failed: %s",
+			err,
+		)
+	}
+}
+
 func (con *connection) notifySessionCreated(newSession *Session) error {
+	encoded, err := con.encodeSession(newSession)
+	if err != nil {
+		return err
+	}
+
+	// Notify client about the session
+	return con.sock.Write(msg.NewSessionCreatedMessage(encoded))
+}
+
+func (con *connection) notifySessionUpdated(session *Session) error {
+	encoded, err := con.encodeSession(session)
+	if err != nil {
+		return err
+	}
+
+	// Notify client about the session update
+	return con.sock.Write(msg.NewSessionUpdatedMessage(encoded))
+}
+
+// encodeSession encodes the given session using the session codec
+// to be sent to the client
+func (con *connection) encodeSession(session *Session) ([]byte, error) {
 	// Serialize session info
 	var sessionInfo map[string]interface{}
-	if newSession.Info != nil {
+	if session.Info != nil {
 		sessionInfo = make(map[string]interface{})
-		for _, field := range newSession.Info.Fields() {
-			sessionInfo[field] = newSession.Info.Value(field)
+		for _, field := range session.Info.Fields() {
+			sessionInfo[field] = session.Info.Value(field)
 		}
 	}
 
-	encoded, err := json.Marshal(JSONEncodedSession{
-		newSession.Key,
-		newSession.Creation,
-		newSession.LastLookup,
-		sessionInfo,
+	encoded, err := con.srv.options.SessionCodec.Encode(&JSONEncodedSession{
+		Key:        session.Key,
+		Creation:   session.Creation,
+		LastLookup: session.LastLookup,
+		Expiration: expirationField(session.Expiration),
+		Info:       sessionInfo,
 	})
 	if err != nil {
-		return fmt.Errorf("Couldn't marshal session object: %s", err)
+		return nil, fmt.Errorf("Couldn't encode session object: %s", err)
 	}
+	return encoded, nil
+}
 
-	// Notify client about the session creation
-	message := make([]byte, 1+len(encoded))
-	message[0] = msg.MsgSessionCreated
-
-	for i := 0; i < len(encoded); i++ {
-		message[1+i] = encoded[i]
+func (con *connection) notifySessionInfoUpdated(info SessionInfo) error {
+	encoded, err := json.Marshal(SessionInfoToVarMap(info))
+	if err != nil {
+		return fmt.Errorf("Couldn't marshal session info object: %s", err)
 	}
-	return con.sock.Write(message)
+
+	// Notify client about the session info update
+	return con.sock.Write(msg.NewSessionInfoUpdatedMessage(encoded))
 }
 
-func (con *connection) notifySessionClosed() error {
+func (con *connection) notifySessionClosed(reason string) error {
 	// Notify client about the session destruction
-	if err := con.sock.Write([]byte{msg.MsgSessionClosed}); err != nil {
+	// attaching the optional reason
+	err := con.sock.Write(msg.NewSessionClosedMessage(reason))
+	if err != nil {
 		return fmt.Errorf(
 			"Couldn't notify client about the session destruction: %s",
 			err,
@@ -249,8 +511,115 @@ func (con *connection) notifySessionClosed() error {
 	return nil
 }
 
+// UpdateSessionInfo implements the Connection interface
+func (con *connection) UpdateSessionInfo(info SessionInfo) error {
+	if !con.srv.sessionsEnabled {
+		return SessionsDisabledErr{}
+	}
+
+	con.sessionLock.Lock()
+	if con.session == nil {
+		con.sessionLock.Unlock()
+		return fmt.Errorf("Can't update session info, no session active")
+	}
+	con.session.Info = info
+	con.sessionLock.Unlock()
+
+	if err := con.notifySessionInfoUpdated(info); err != nil {
+		return fmt.Errorf(
+			"Couldn't notify client about the session info update: %s",
+			err,
+		)
+	}
+	return nil
+}
+
+// UpdateSession implements the Connection interface
+func (con *connection) UpdateSession(info SessionInfo) error {
+	if !con.srv.sessionsEnabled {
+		return SessionsDisabledErr{}
+	}
+
+	con.sessionLock.RLock()
+	if con.session == nil {
+		con.sessionLock.RUnlock()
+		return fmt.Errorf("Can't update session, no session active")
+	}
+	sessionKey := con.session.Key
+	con.sessionLock.RUnlock()
+
+	// Update the session on all of its connections
+	lastLookup := time.Now().UTC()
+	errNum := 0
+	for connection := range con.srv.sessionRegistry.sessionConnections(
+		sessionKey,
+	) {
+		// Provide each connection with its own copy of the session info
+		var connInfo SessionInfo
+		if info != nil {
+			connInfo = info.Copy()
+		}
+
+		if err := connection.applySessionUpdate(
+			sessionKey,
+			connInfo,
+			lastLookup,
+		); err != nil {
+			con.srv.warnLog.Printf(
+				"Couldn't notify client about the session update: %s",
+				err,
+			)
+			errNum++
+		}
+	}
+
+	// Call session update hook
+	if err := con.srv.onSessionUpdated(con); err != nil {
+		errCtx := newErrorContext(ErrOpSessionUpdate, con)
+		errCtx.SessionKey = con.SessionKey()
+		con.srv.logError(
+			errCtx,
+			err,
+			"OnSessionUpdated hook failed: %s",
+			err,
+		)
+	}
+
+	if errNum > 0 {
+		return fmt.Errorf(
+			"%d errors during the synchronization of a session update",
+			errNum,
+		)
+	}
+	return nil
+}
+
+// applySessionUpdate replaces the info and the last lookup time
+// of the currently active session and synchronizes the updated session
+// to the remote client. Does nothing if the session was replaced
+// by another one in the meantime
+func (con *connection) applySessionUpdate(
+	sessionKey string,
+	info SessionInfo,
+	lastLookup time.Time,
+) error {
+	con.sessionLock.Lock()
+	defer con.sessionLock.Unlock()
+	if con.session == nil || con.session.Key != sessionKey {
+		return nil
+	}
+	con.session.Info = info
+	con.session.LastLookup = lastLookup
+	return con.notifySessionUpdated(con.session)
+}
+
 // CloseSession implements the Connection interface
 func (con *connection) CloseSession() error {
+	return con.CloseSessionWithReason("")
+}
+
+// CloseSessionWithReason implements the Connection interface
+func (con *connection) CloseSessionWithReason(reason string) error {
 	if !con.srv.sessionsEnabled {
 		return SessionsDisabledErr{}
 	}
@@ -262,11 +631,13 @@ func (con *connection) CloseSession() error {
 	}
 
 	// Deregister session from active sessions registry
+	// and drop its connection limit override
 	con.srv.sessionRegistry.deregister(con)
+	con.srv.sessionRegistry.dropMaxConns(con.session.Key)
 	con.session = nil
 	con.sessionLock.Unlock()
 
-	return con.notifySessionClosed()
+	return con.notifySessionClosed(reason)
 }
 
 // HasSession implements the Connection interface
@@ -321,6 +692,43 @@ func (con *connection) SessionInfo(name string) interface{} {
 	return val
 }
 
+// SessionConnections implements the Connection interface
+func (con *connection) SessionConnections() []Connection {
+	sessionKey := con.SessionKey()
+	if sessionKey == "" {
+		return nil
+	}
+	connections := con.srv.sessionRegistry.sessionConnections(sessionKey)
+	list := make([]Connection, 0, len(connections))
+	for connection := range connections {
+		if connection != con {
+			list = append(list, connection)
+		}
+	}
+	return list
+}
+
+// SetState implements the Connection interface
+func (con *connection) SetState(key string, value interface{}) {
+	con.stateStoreLock.Lock()
+	defer con.stateStoreLock.Unlock()
+	if value == nil {
+		delete(con.stateStore, key)
+		return
+	}
+	if con.stateStore == nil {
+		con.stateStore = make(map[string]interface{})
+	}
+	con.stateStore[key] = value
+}
+
+// State implements the Connection interface
+func (con *connection) State(key string) interface{} {
+	con.stateStoreLock.RLock()
+	defer con.stateStoreLock.RUnlock()
+	return con.stateStore[key]
+}
+
 // Close implements the Connection interface
 func (con *connection) Close() {
 	unlink := false
@@ -336,6 +744,9 @@ func (con *connection) Close() {
 	}
 	con.stateLock.Unlock()
 
+	// Cancel the context of currently executed handlers
+	con.cancelCtx()
+
 	if unlink {
 		con.unlink()
 	}
@@ -0,0 +1,12 @@
+package webwire
+
+// ConnectionDetails represents a connection along with its metadata
+// captured at the time the connection was established
+type ConnectionDetails struct {
+	// Connection is the connection the details refer to
+	Connection Connection
+
+	// ClientInfo contains the connection time, the user agent
+	// and the remote address of the connection
+	ClientInfo
+}
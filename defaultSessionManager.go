@@ -1,23 +1,78 @@
 package webwire
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"time"
 )
 
-// sessionFile represents the serialization structure of a default session file
+// sessionFileFormatVersion represents the current version
+// of the session file format. Session files without a version
+// were written before the format was versioned and are equivalent to version 1
+const sessionFileFormatVersion = uint8(1)
+
+// sessionFile represents the serialization structure of a default session file.
+// It shares the field names of the JSONEncodedSession wire format
+// except for the key which is represented by the file name
 type sessionFile struct {
+	Version    uint8                  `json:"v"`
 	Creation   time.Time              `json:"c"`
 	LastLookup time.Time              `json:"l"`
-	Info       map[string]interface{} `json:"i"`
+	Expiration *time.Time             `json:"e,omitempty"`
+	Info       map[string]interface{} `json:"i,omitempty"`
+}
+
+// encodedSession converts the session file into the wire format
+// of the session identified by the given key
+func (sessf *sessionFile) encodedSession(key string) JSONEncodedSession {
+	return JSONEncodedSession{
+		Key:        key,
+		Creation:   sessf.Creation,
+		LastLookup: sessf.LastLookup,
+		Expiration: sessf.Expiration,
+		Info:       sessf.Info,
+	}
+}
+
+// expirationTime returns the expiration time of the stored session
+// or the zero time if the session doesn't expire
+func (sessf *sessionFile) expirationTime() time.Time {
+	if sessf.Expiration == nil {
+		return time.Time{}
+	}
+	return *sessf.Expiration
+}
+
+// isExpired returns true if the session file has an expiration time
+// which has passed by the given time
+func (sessf *sessionFile) isExpired(now time.Time) bool {
+	return sessf.Expiration != nil && !now.Before(*sessf.Expiration)
 }
 
-// Parse parses the session file from a file
-func (sessf *sessionFile) Parse(filePath string) error {
+// gzipMagic represents the magic bytes header of gzip compressed data
+// which is used to detect compressed session files
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// sessionFileEncryptionV1 represents the version byte header
+// of session files encrypted with AES-GCM. The header is followed by
+// the nonce and the sealed session file contents
+const sessionFileEncryptionV1 = byte(1)
+
+// Parse parses the session file from a file.
+// Encrypted session files are detected by their version byte header
+// and decrypted using the given AEAD cipher.
+// Gzip compressed session files are detected by their magic bytes header
+// and transparently decompressed
+func (sessf *sessionFile) Parse(filePath string, aead cipher.AEAD) error {
 	contents, err := ioutil.ReadFile(filePath)
 	if err != nil {
 		return fmt.Errorf(
@@ -25,31 +80,177 @@ func (sessf *sessionFile) Parse(filePath string) error {
 			err,
 		)
 	}
-	return json.Unmarshal(contents, sessf)
+
+	if len(contents) > 0 && contents[0] == sessionFileEncryptionV1 {
+		if aead == nil {
+			return fmt.Errorf(
+				"Couldn't parse session file, file is encrypted " +
+					"but no encryption key is set",
+			)
+		}
+		nonceSize := aead.NonceSize()
+		if len(contents) < 1+nonceSize {
+			return fmt.Errorf(
+				"Couldn't parse session file, encrypted file too short",
+			)
+		}
+		contents, err = aead.Open(
+			nil,
+			contents[1:1+nonceSize],
+			contents[1+nonceSize:],
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"Couldn't parse session file, failed decrypting: %s",
+				err,
+			)
+		}
+	} else if aead != nil {
+		return fmt.Errorf(
+			"Couldn't parse session file, file is not encrypted",
+		)
+	}
+
+	if bytes.HasPrefix(contents, gzipMagic) {
+		reader, err := gzip.NewReader(bytes.NewReader(contents))
+		if err != nil {
+			return fmt.Errorf(
+				"Couldn't parse session file, invalid compressed data: %s",
+				err,
+			)
+		}
+		defer reader.Close()
+		contents, err = ioutil.ReadAll(reader)
+		if err != nil {
+			return fmt.Errorf(
+				"Couldn't parse session file, failed decompressing: %s",
+				err,
+			)
+		}
+	}
+
+	return sessf.unmarshal(contents)
 }
 
-// Save writes the session file to a file on the filesystem
-func (sessf *sessionFile) Save(filePath string) error {
-	encoded, err := json.Marshal(sessf)
+// marshal encodes the session file into its JSON representation
+func (sessf *sessionFile) marshal() ([]byte, error) {
+	sessf.Version = sessionFileFormatVersion
+	return json.Marshal(sessf)
+}
+
+// unmarshal decodes the session file from its JSON representation
+// verifying the format version
+func (sessf *sessionFile) unmarshal(encoded []byte) error {
+	if err := json.Unmarshal(encoded, sessf); err != nil {
+		return err
+	}
+	if sessf.Version > sessionFileFormatVersion {
+		return fmt.Errorf(
+			"Couldn't parse session file, unsupported format version: %d",
+			sessf.Version,
+		)
+	}
+	return nil
+}
+
+// Save writes the session file to a file on the filesystem.
+// The file contents are gzip compressed if compress is true
+// and encrypted if the given AEAD cipher isn't nil
+func (sessf *sessionFile) Save(
+	filePath string,
+	compress bool,
+	aead cipher.AEAD,
+	mode os.FileMode,
+) error {
+	encoded, err := sessf.marshal()
 	if err != nil {
 		return fmt.Errorf("Couldn't marshal session file: %s", err)
 	}
-	if err := ioutil.WriteFile(filePath, encoded, 0640); err != nil {
+	if compress {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(encoded); err != nil {
+			return fmt.Errorf("Couldn't compress session file: %s", err)
+		}
+		if err := writer.Close(); err != nil {
+			return fmt.Errorf("Couldn't compress session file: %s", err)
+		}
+		encoded = buf.Bytes()
+	}
+	if aead != nil {
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return fmt.Errorf("Couldn't generate session file nonce: %s", err)
+		}
+		sealed := make([]byte, 1, 1+len(nonce)+len(encoded)+aead.Overhead())
+		sealed[0] = sessionFileEncryptionV1
+		sealed = append(sealed, nonce...)
+		encoded = aead.Seal(sealed, nonce, encoded, nil)
+	}
+	if err := ioutil.WriteFile(filePath, encoded, mode); err != nil {
 		return fmt.Errorf("Couldn't write session file: %s", err)
 	}
 	return nil
 }
 
+const (
+	// DefaultSessionFileMode represents the default permissions
+	// of the session files written by the default session manager
+	DefaultSessionFileMode = os.FileMode(0600)
+
+	// DefaultSessionDirMode represents the default permissions
+	// of the session directory created by the default session manager
+	DefaultSessionDirMode = os.FileMode(0700)
+)
+
 // DefaultSessionManager represents a default session manager implementation.
 // It uses files as a persistent storage
 type DefaultSessionManager struct {
-	path string
+	path     string
+	compress bool
+	aead     cipher.AEAD
+	fileMode os.FileMode
+	dirMode  os.FileMode
+}
+
+// DefaultSessionManagerOption represents an option
+// of the default session manager
+type DefaultSessionManagerOption func(mng *DefaultSessionManager)
+
+// SessionFileMode sets the permissions of the session files
+// written by the default session manager.
+// Defaults to DefaultSessionFileMode
+func SessionFileMode(mode os.FileMode) DefaultSessionManagerOption {
+	return func(mng *DefaultSessionManager) {
+		mng.fileMode = mode
+	}
+}
+
+// SessionDirMode sets the permissions of the session directory
+// created by the default session manager if it doesn't exist yet.
+// Defaults to DefaultSessionDirMode
+func SessionDirMode(mode os.FileMode) DefaultSessionManagerOption {
+	return func(mng *DefaultSessionManager) {
+		mng.dirMode = mode
+	}
 }
 
 // NewDefaultSessionManager constructs a new default session manager instance.
 // Verifies the existence of the given session directory
 // and creates it if it doesn't exist yet
-func NewDefaultSessionManager(sessFilesPath string) *DefaultSessionManager {
+func NewDefaultSessionManager(
+	sessFilesPath string,
+	options ...DefaultSessionManagerOption,
+) *DefaultSessionManager {
+	mng := &DefaultSessionManager{
+		fileMode: DefaultSessionFileMode,
+		dirMode:  DefaultSessionDirMode,
+	}
+	for _, option := range options {
+		option(mng)
+	}
+
 	if len(sessFilesPath) < 1 {
 		// Use the current directory as parent of the session directory
 		// by default
@@ -69,7 +270,7 @@ func NewDefaultSessionManager(sessFilesPath string) *DefaultSessionManager {
 	_, err := os.Stat(sessFilesPath)
 	if os.IsNotExist(err) {
 		// Create the directory if it doesn't exist yet
-		if err := os.MkdirAll(sessFilesPath, 0640); err != nil {
+		if err := os.MkdirAll(sessFilesPath, mng.dirMode); err != nil {
 			panic(fmt.Errorf(
 				"Couldn't create default session directory ('%s'): %s",
 				sessFilesPath,
@@ -85,9 +286,45 @@ func NewDefaultSessionManager(sessFilesPath string) *DefaultSessionManager {
 		))
 	}
 
-	return &DefaultSessionManager{
-		path: sessFilesPath,
+	mng.path = sessFilesPath
+	return mng
+}
+
+// NewEncryptedDefaultSessionManager constructs a new default session manager
+// instance encrypting the session files at rest using AES-GCM.
+// The key must be either 16, 24 or 32 bytes long
+// to select AES-128, AES-192 or AES-256 respectively
+func NewEncryptedDefaultSessionManager(
+	sessFilesPath string,
+	key []byte,
+	options ...DefaultSessionManagerOption,
+) *DefaultSessionManager {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		panic(fmt.Errorf(
+			"Invalid default session manager encryption key: %s",
+			err,
+		))
 	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(fmt.Errorf(
+			"Couldn't initialize default session manager encryption: %s",
+			err,
+		))
+	}
+
+	mng := NewDefaultSessionManager(sessFilesPath, options...)
+	mng.aead = aead
+	return mng
+}
+
+// SetFileCompression enables or disables gzip compression of session files
+// written by the session manager. Previously written session files
+// are still read regardless of whether they're compressed or not.
+// Must be called before the session manager is used by a server
+func (mng *DefaultSessionManager) SetFileCompression(enabled bool) {
+	mng.compress = enabled
 }
 
 // filePath generates an absolute session file path given the session key
@@ -102,14 +339,21 @@ func (mng *DefaultSessionManager) OnSessionCreated(conn Connection) error {
 	sessFile := sessionFile{
 		Creation:   sess.Creation,
 		LastLookup: sess.LastLookup,
+		Expiration: expirationField(sess.Expiration),
 		Info:       SessionInfoToVarMap(sess.Info),
 	}
-	return sessFile.Save(mng.filePath(conn.SessionKey()))
+	return sessFile.Save(
+		mng.filePath(conn.SessionKey()),
+		mng.compress,
+		mng.aead,
+		mng.fileMode,
+	)
 }
 
 // OnSessionLookup implements the session manager interface.
 // It searches the session file directory for the session file and loads it.
 // It also updates the file by updating the last lookup session field.
+// Session files of expired sessions are deleted and reported as not found
 func (mng *DefaultSessionManager) OnSessionLookup(key string) (
 	SessionLookupResult,
 	error,
@@ -129,29 +373,49 @@ func (mng *DefaultSessionManager) OnSessionLookup(key string) (
 
 	// Parse session file
 	var file sessionFile
-	if err := file.Parse(path); err != nil {
+	if err := file.Parse(path, mng.aead); err != nil {
 		return nil, fmt.Errorf(
 			"Couldn't parse session file: %s",
 			err,
 		)
 	}
 
+	// Lazily delete the session file if the session has expired
+	now := time.Now().UTC()
+	if file.isExpired(now) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf(
+				"Couldn't remove expired session file: %s",
+				err,
+			)
+		}
+		return nil, nil
+	}
+
 	// Update last lookup
 	newSessionFile := sessionFile{
 		Creation:   file.Creation,
-		LastLookup: time.Now().UTC(),
+		LastLookup: now,
+		Expiration: file.Expiration,
 		Info:       file.Info,
 	}
-	if err := newSessionFile.Save(mng.filePath(key)); err != nil {
+	err = newSessionFile.Save(
+		path,
+		mng.compress,
+		mng.aead,
+		mng.fileMode,
+	)
+	if err != nil {
 		return nil, fmt.Errorf(
 			"Couldn't update last lookup field, failed writing file: %s",
 			err,
 		)
 	}
 
-	return NewSessionLookupResult(
+	return NewExpiringSessionLookupResult(
 		file.Creation,
 		file.LastLookup,
+		file.expirationTime(),
 		file.Info,
 	), nil
 }
@@ -167,3 +431,59 @@ func (mng *DefaultSessionManager) OnSessionClosed(sessionKey string) error {
 	}
 	return nil
 }
+
+// OnSessionUpdated implements the session manager interface.
+// It overwrites the session file with the updated session
+func (mng *DefaultSessionManager) OnSessionUpdated(conn Connection) error {
+	return mng.OnSessionCreated(conn)
+}
+
+// GC deletes all session files of sessions that were neither created
+// nor looked up within the given duration. It's meant for cleaning up
+// session files of sessions that were never properly closed
+// and of sessions that expired without being looked up again.
+// Session files that can't be parsed are skipped,
+// the first encountered error is returned after all files are processed
+func (mng *DefaultSessionManager) GC(olderThan time.Duration) error {
+	filePaths, err := filepath.Glob(filepath.Join(mng.path, "*.wwrsess"))
+	if err != nil {
+		return fmt.Errorf("Couldn't list session files: %s", err)
+	}
+
+	threshold := time.Now().UTC().Add(-olderThan)
+	var firstErr error
+	for _, filePath := range filePaths {
+		var file sessionFile
+		if err := file.Parse(filePath, mng.aead); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf(
+					"Couldn't parse session file (%s): %s",
+					filePath,
+					err,
+				)
+			}
+			continue
+		}
+
+		// Determine the time of the last activity of the session
+		lastActivity := file.Creation
+		if file.LastLookup.After(lastActivity) {
+			lastActivity = file.LastLookup
+		}
+		if !lastActivity.Before(threshold) && !file.isExpired(time.Now()) {
+			continue
+		}
+
+		if err := os.Remove(filePath); err != nil &&
+			!os.IsNotExist(err) &&
+			firstErr == nil {
+			firstErr = fmt.Errorf(
+				"Couldn't remove session file (%s): %s",
+				filePath,
+				err,
+			)
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,62 @@
+package webwire
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestDefaultSessionManagerExpiration tests whether the default session
+// manager preserves the expiration time of sessions and lazily deletes
+// the session files of expired sessions during lookup
+func TestDefaultSessionManagerExpiration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wwrsess")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	mng := NewDefaultSessionManager(dir)
+	now := time.Now().UTC()
+
+	save := func(key string, expiration time.Time) {
+		file := sessionFile{
+			Creation:   now,
+			LastLookup: now,
+			Expiration: expirationField(expiration),
+		}
+		require.NoError(t, file.Save(mng.filePath(key), false, nil, 0600))
+	}
+	exists := func(key string) bool {
+		_, err := os.Stat(mng.filePath(key))
+		return err == nil
+	}
+
+	save("expired", now.Add(-1*time.Minute))
+	save("valid", now.Add(1*time.Hour))
+	save("eternal", time.Time{})
+
+	// Expect the expired session to be reported as not found and deleted
+	result, err := mng.OnSessionLookup("expired")
+	require.NoError(t, err)
+	require.Nil(t, result)
+	require.False(t, exists("expired"))
+
+	// Expect the expiration to be preserved across lookups
+	for i := 0; i < 2; i++ {
+		result, err = mng.OnSessionLookup("valid")
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		require.Implements(t, (*ExpiringSessionLookupResult)(nil), result)
+		require.True(t, now.Add(1*time.Hour).Equal(
+			result.(ExpiringSessionLookupResult).Expiration(),
+		))
+	}
+
+	// Expect sessions without expiration to never expire
+	result, err = mng.OnSessionLookup("eternal")
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.True(t, lookupResultExpiration(result).IsZero())
+}
@@ -0,0 +1,49 @@
+package webwire
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestDefaultSessionManagerGC tests the garbage collection of outdated
+// session files of the default session manager
+func TestDefaultSessionManagerGC(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wwrsess")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	mng := NewDefaultSessionManager(dir)
+	now := time.Now().UTC()
+
+	save := func(key string, creation, lastLookup time.Time) {
+		file := sessionFile{
+			Creation:   creation,
+			LastLookup: lastLookup,
+		}
+		require.NoError(t, file.Save(mng.filePath(key), false, nil, 0600))
+	}
+
+	// Old session, never looked up
+	save("old", now.Add(-2*time.Hour), time.Time{})
+	// Old session, last looked up long ago
+	save("oldlookup", now.Add(-3*time.Hour), now.Add(-2*time.Hour))
+	// Old session, recently looked up
+	save("oldrecent", now.Add(-3*time.Hour), now.Add(-1*time.Minute))
+	// New session
+	save("new", now, now)
+
+	require.NoError(t, mng.GC(1*time.Hour))
+
+	exists := func(key string) bool {
+		_, err := os.Stat(mng.filePath(key))
+		return err == nil
+	}
+	require.False(t, exists("old"))
+	require.False(t, exists("oldlookup"))
+	require.True(t, exists("oldrecent"))
+	require.True(t, exists("new"))
+}
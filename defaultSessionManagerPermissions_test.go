@@ -0,0 +1,49 @@
+package webwire
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestDefaultSessionManagerPermissions tests the permissions of the session
+// directory and files created by the default session manager
+func TestDefaultSessionManagerPermissions(t *testing.T) {
+	parent, err := ioutil.TempDir("", "wwrsess")
+	require.NoError(t, err)
+	defer os.RemoveAll(parent)
+
+	save := func(mng *DefaultSessionManager) os.FileMode {
+		sess := NewSession(nil, func() string { return "testkey" })
+		conn := newConnection(&testSignalSocket{}, "", nil, nil)
+		conn.session = &sess
+		require.NoError(t, mng.OnSessionCreated(conn))
+		stat, err := os.Stat(mng.filePath("testkey"))
+		require.NoError(t, err)
+		return stat.Mode().Perm()
+	}
+	dirMode := func(path string) os.FileMode {
+		stat, err := os.Stat(path)
+		require.NoError(t, err)
+		return stat.Mode().Perm()
+	}
+
+	// Default permissions
+	defaultDir := filepath.Join(parent, "default", "sessions")
+	mng := NewDefaultSessionManager(defaultDir)
+	require.Equal(t, DefaultSessionDirMode, dirMode(defaultDir))
+	require.Equal(t, DefaultSessionFileMode, save(mng))
+
+	// Custom permissions
+	customDir := filepath.Join(parent, "custom")
+	mng = NewDefaultSessionManager(
+		customDir,
+		SessionDirMode(0750),
+		SessionFileMode(0640),
+	)
+	require.Equal(t, os.FileMode(0750), dirMode(customDir))
+	require.Equal(t, os.FileMode(0640), save(mng))
+}
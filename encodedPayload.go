@@ -1,6 +1,11 @@
 package webwire
 
-import pld "github.com/qbeon/webwire-go/payload"
+import (
+	"encoding/json"
+	"fmt"
+
+	pld "github.com/qbeon/webwire-go/payload"
+)
 
 // PayloadEncoding represents the type of encoding of the message payload
 type PayloadEncoding = pld.Encoding
@@ -20,6 +25,9 @@ const (
 // and implements the WebWire payload interface
 type EncodedPayload struct {
 	Payload pld.Payload
+
+	// Codec represents the optional payload codec used by DecodeInto
+	Codec Codec
 }
 
 // Encoding implements the WebWire payload interface
@@ -32,11 +40,50 @@ func (pld *EncodedPayload) Data() []byte {
 	return pld.Payload.Data
 }
 
+// Len implements the WebWire payload interface
+func (pld *EncodedPayload) Len() int {
+	if pld == nil {
+		return 0
+	}
+	return len(pld.Payload.Data)
+}
+
+// IsEmpty implements the WebWire payload interface
+func (pld *EncodedPayload) IsEmpty() bool {
+	return pld.Len() < 1
+}
+
 // Utf8 implements the WebWire payload interface
 func (pld *EncodedPayload) Utf8() (string, error) {
 	return pld.Payload.Utf8()
 }
 
+// DecodeInto implements the WebWire payload interface
+func (pld *EncodedPayload) DecodeInto(value interface{}) error {
+	if pld.Codec == nil {
+		return NoCodecErr{}
+	}
+	return pld.Codec.Unmarshal(pld.Payload.Data, value)
+}
+
+// DecodeJSON implements the WebWire payload interface
+func (pld *EncodedPayload) DecodeJSON(value interface{}) error {
+	if pld.Payload.Encoding == EncodingBinary {
+		return fmt.Errorf(
+			"Can't decode JSON from a binary encoded payload",
+		)
+	}
+	data := pld.Payload.Data
+	if pld.Payload.Encoding == EncodingUtf16 {
+		utf8, err := pld.Payload.Utf8()
+		if err != nil {
+			return err
+		}
+		data = []byte(utf8)
+	}
+	return json.Unmarshal(data, value)
+}
+
 // NewPayload creates a new WebWire message payload
 func NewPayload(encoding PayloadEncoding, data []byte) Payload {
 	return &EncodedPayload{
@@ -46,3 +93,29 @@ func NewPayload(encoding PayloadEncoding, data []byte) Payload {
 		},
 	}
 }
+
+// NewCodecPayload creates a new binary encoded WebWire message payload
+// containing the given value encoded by the given codec
+func NewCodecPayload(codec Codec, value interface{}) (Payload, error) {
+	data, err := codec.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return &EncodedPayload{
+		Payload: pld.Payload{
+			Encoding: EncodingBinary,
+			Data:     data,
+		},
+		Codec: codec,
+	}, nil
+}
+
+// NewJSONPayload creates a new UTF8 encoded WebWire message payload
+// containing the given value encoded in JSON
+func NewJSONPayload(value interface{}) (Payload, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return NewPayload(EncodingUtf8, data), nil
+}
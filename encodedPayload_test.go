@@ -0,0 +1,79 @@
+package webwire_test
+
+import (
+	"testing"
+
+	wwr "github.com/qbeon/webwire-go"
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// testJSONValue represents a structured value
+// exchanged through JSON encoded payloads
+type testJSONValue struct {
+	Text   string `json:"text"`
+	Number int    `json:"number"`
+}
+
+// TestJSONPayload tests encoding and decoding JSON payloads
+func TestJSONPayload(t *testing.T) {
+	original := testJSONValue{Text: "sample text", Number: 42}
+
+	payload, err := wwr.NewJSONPayload(original)
+	require.NoError(t, err)
+	require.Equal(t, wwr.EncodingUtf8, payload.Encoding())
+	require.Equal(t,
+		`{"text":"sample text","number":42}`,
+		string(payload.Data()),
+	)
+
+	var decoded testJSONValue
+	require.NoError(t, payload.DecodeJSON(&decoded))
+	require.Equal(t, original, decoded)
+}
+
+// TestJSONPayloadUtf16 tests decoding UTF16 encoded JSON payloads
+func TestJSONPayloadUtf16(t *testing.T) {
+	payload := wwr.NewPayload(
+		wwr.EncodingUtf16,
+		pld.EncodeUtf16(`{"text":"sample text"}`, pld.Utf16ByteOrder),
+	)
+
+	var decoded testJSONValue
+	require.NoError(t, payload.DecodeJSON(&decoded))
+	require.Equal(t, testJSONValue{Text: "sample text"}, decoded)
+}
+
+// TestJSONPayloadBinary tests decoding JSON from a binary payload
+func TestJSONPayloadBinary(t *testing.T) {
+	payload := wwr.NewPayload(wwr.EncodingBinary, []byte(`{}`))
+
+	var decoded testJSONValue
+	require.Error(t, payload.DecodeJSON(&decoded))
+}
+
+// TestJSONPayloadUnsupportedValue tests encoding unsupported values
+func TestJSONPayloadUnsupportedValue(t *testing.T) {
+	payload, err := wwr.NewJSONPayload(make(chan int))
+	require.Error(t, err)
+	require.Nil(t, payload)
+}
+
+// TestPayloadIsEmpty tests whether nil and empty payload data
+// are treated equally by IsEmpty and Len
+func TestPayloadIsEmpty(t *testing.T) {
+	for _, payload := range []wwr.Payload{
+		&wwr.EncodedPayload{},
+		(*wwr.EncodedPayload)(nil),
+		wwr.NewPayload(wwr.EncodingBinary, nil),
+		wwr.NewPayload(wwr.EncodingUtf8, []byte{}),
+		wwr.NewPayload(wwr.EncodingUtf16, nil),
+	} {
+		require.True(t, payload.IsEmpty())
+		require.Equal(t, 0, payload.Len())
+	}
+
+	payload := wwr.NewPayload(wwr.EncodingUtf8, []byte("text"))
+	require.False(t, payload.IsEmpty())
+	require.Equal(t, 4, payload.Len())
+}
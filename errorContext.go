@@ -0,0 +1,82 @@
+package webwire
+
+import "fmt"
+
+// ErrorOperation represents the operation an internal server error
+// occurred during
+type ErrorOperation string
+
+const (
+	// ErrOpUpgrade represents the upgrade of an incoming HTTP connection
+	// to a WebSocket connection
+	ErrOpUpgrade ErrorOperation = "upgrade"
+
+	// ErrOpHandshake represents the handshake
+	// of a newly established connection
+	ErrOpHandshake ErrorOperation = "handshake"
+
+	// ErrOpHeartbeat represents the heartbeat of a connection
+	ErrOpHeartbeat ErrorOperation = "heartbeat"
+
+	// ErrOpRequest represents the handling of a request
+	ErrOpRequest ErrorOperation = "request"
+
+	// ErrOpSessionCreation represents the creation of a session
+	// including the OnSessionCreated session manager hook
+	ErrOpSessionCreation ErrorOperation = "session creation"
+
+	// ErrOpSessionRestoration represents the restoration of a session
+	// including the OnSessionLookup session manager hook
+	ErrOpSessionRestoration ErrorOperation = "session restoration"
+
+	// ErrOpSessionUpdate represents the update of a session
+	// including the OnSessionUpdated session manager hook
+	ErrOpSessionUpdate ErrorOperation = "session update"
+
+	// ErrOpSessionClosure represents the closure of a session
+	// including the OnSessionClosed session manager hook
+	ErrOpSessionClosure ErrorOperation = "session closure"
+)
+
+// ErrorContext describes the circumstances of an internal server error
+// reported to the ServerOptions.OnError hook
+type ErrorContext struct {
+	// Operation is the operation the error occurred during
+	Operation ErrorOperation
+
+	// Connection is the connection the error occurred on
+	// or nil if the error isn't related to an established connection
+	Connection Connection
+
+	// SessionKey is the key of the session affected by the error
+	// or an empty string if no session is affected
+	SessionKey string
+
+	// MessageName is the name of the handled request if any
+	MessageName string
+}
+
+// newErrorContext creates a new error context
+// for the given operation on the given connection
+func newErrorContext(op ErrorOperation, con *connection) ErrorContext {
+	errCtx := ErrorContext{Operation: op}
+	if con != nil {
+		errCtx.Connection = con
+	}
+	return errCtx
+}
+
+// logError logs the given internal error using the given format
+// and reports it to the OnError hook if it's defined
+func (srv *server) logError(
+	errCtx ErrorContext,
+	err error,
+	format string,
+	args ...interface{},
+) {
+	// Report the caller of logError as the origin of the log entry
+	srv.errorLog.Output(2, fmt.Sprintf(format, args...))
+	if srv.options.OnError != nil {
+		srv.options.OnError(err, errCtx)
+	}
+}
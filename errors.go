@@ -1,7 +1,9 @@
 package webwire
 
 import (
+	"errors"
 	"fmt"
+	"syscall"
 )
 
 // ConnIncompErr represents a connection error type indicating that the server
@@ -29,6 +31,30 @@ func NewConnIncompErr(requiredVersion, supportedVersion string) ConnIncompErr {
 	}
 }
 
+// NoCodecErr represents an error type indicating that a payload couldn't be
+// decoded because no payload codec is configured
+type NoCodecErr struct{}
+
+func (err NoCodecErr) Error() string {
+	return "No payload codec configured"
+}
+
+// CodecMismatchErr represents a connection error type indicating that
+// the server uses another payload codec than the client
+// and can't therefore be connected to
+type CodecMismatchErr struct {
+	ServerCodec string
+	ClientCodec string
+}
+
+func (err CodecMismatchErr) Error() string {
+	return fmt.Sprintf(
+		"Payload codec mismatch: server uses '%s' while client uses '%s'",
+		err.ServerCodec,
+		err.ClientCodec,
+	)
+}
+
 // ReqTransErr represents a connection error type
 // indicating that the dialing failed.
 type ReqTransErr struct {
@@ -57,11 +83,30 @@ func (err ReqSrvShutdownErr) Error() string {
 }
 
 // ReqInternalErr represents a request error type
-// indicating that the request failed due to an internal server-side error
-type ReqInternalErr struct{}
+// indicating that the request failed due to an internal server-side error.
+// Message is only set if the server exposes internal errors
+type ReqInternalErr struct {
+	Message string
+}
 
 func (err ReqInternalErr) Error() string {
-	return "Internal server error"
+	if len(err.Message) < 1 {
+		return "Internal server error"
+	}
+	return fmt.Sprintf("Internal server error: %s", err.Message)
+}
+
+// ClientInternalErr represents an error returned by Connection.Request
+// indicating that the request failed due to an internal client-side error
+type ClientInternalErr struct {
+	Message string
+}
+
+func (err ClientInternalErr) Error() string {
+	if len(err.Message) < 1 {
+		return "Internal client error"
+	}
+	return fmt.Sprintf("Internal client error: %s", err.Message)
 }
 
 // TimeoutErr represents a failure due to a timeout
@@ -96,10 +141,13 @@ func (err DeadlineExceededErr) Error() string {
 }
 
 // ReqErr represents an error returned in case of
-// a request that couldn't be processed
+// a request that couldn't be processed.
+// The optional payload is delivered to the client alongside the error
+// and may carry structured error details
 type ReqErr struct {
 	Code    string
 	Message string
+	Payload Payload
 }
 
 func (err ReqErr) Error() string {
@@ -122,6 +170,42 @@ func (err SessNotFoundErr) Error() string {
 	return "Session not found"
 }
 
+// UnauthenticatedErr represents a request error type indicating that
+// the request was rejected because the connection has no session
+// while the server requires a session for requests
+type UnauthenticatedErr struct{}
+
+func (err UnauthenticatedErr) Error() string {
+	return "Requests require an active session"
+}
+
+// PayloadTooLargeErr represents a request error type indicating that
+// the request was rejected because its payload exceeds
+// the maximum request payload size of the server
+type PayloadTooLargeErr struct{}
+
+func (err PayloadTooLargeErr) Error() string {
+	return "Request payload too large"
+}
+
+// RateLimitedErr represents a request error type indicating that
+// the request was rejected because the client exceeded a rate limit.
+// It can be returned by request filters and request middleware
+type RateLimitedErr struct{}
+
+func (err RateLimitedErr) Error() string {
+	return "Request rate limit exceeded"
+}
+
+// ObserverErr represents a request error type indicating that
+// the request was rejected because it was sent by a read-only
+// observer connection
+type ObserverErr struct{}
+
+func (err ObserverErr) Error() string {
+	return "Observer connections can't send requests"
+}
+
 // MaxSessConnsReachedErr represents an authentication error type
 // indicating that the given session already reached the maximum number
 // of concurrent connections
@@ -131,6 +215,15 @@ func (err MaxSessConnsReachedErr) Error() string {
 	return "Reached maximum number of concurrent session connections"
 }
 
+// TooManyPendingErr represents a request error type indicating
+// that the request was rejected because the client already reached
+// the maximum number of concurrently pending requests
+type TooManyPendingErr struct{}
+
+func (err TooManyPendingErr) Error() string {
+	return "Reached maximum number of pending requests"
+}
+
 // DisconnectedErr represents an error type
 // indicating that the targeted client is disconnected
 type DisconnectedErr struct {
@@ -152,6 +245,96 @@ func (err DisconnectedErr) Error() string {
 	return err.Cause.Error()
 }
 
+// SessionKeyCollisionErr represents an error type indicating that
+// the session key generator failed to generate a key that doesn't collide
+// with any currently active session within the given number of attempts
+type SessionKeyCollisionErr struct {
+	Attempts int
+}
+
+func (err SessionKeyCollisionErr) Error() string {
+	return fmt.Sprintf(
+		"Couldn't generate a unique session key after %d attempts",
+		err.Attempts,
+	)
+}
+
+// InvalidSessionKeyErr represents an error type indicating that
+// the session key generator returned either an empty key or a key containing
+// characters other than URL-safe base64 characters
+type InvalidSessionKeyErr struct {
+	Key string
+}
+
+func (err InvalidSessionKeyErr) Error() string {
+	if len(err.Key) < 1 {
+		return "Invalid session key returned by the session key generator " +
+			"(empty)"
+	}
+	return fmt.Sprintf(
+		"Invalid session key returned by the session key generator "+
+			"(contains invalid characters): %q",
+		err.Key,
+	)
+}
+
+// SessionRestoreErr represents an error type indicating that the connection
+// was established successfully but the session couldn't be restored
+// and the connection remains anonymous. The cause is a SessNotFoundErr error
+// if the session doesn't exist
+type SessionRestoreErr struct {
+	Cause error
+}
+
+func (err SessionRestoreErr) Error() string {
+	return fmt.Sprintf(
+		"Connected, but couldn't restore the session: %s",
+		err.Cause,
+	)
+}
+
+// Unwrap returns the cause of the failed session restoration
+func (err SessionRestoreErr) Unwrap() error {
+	return err.Cause
+}
+
+// AbortConnectionErr represents an error type which, when returned by
+// a request handler, a request filter, request middleware
+// or the OnClientConnected hook, closes the connection
+// with the given WebSocket close code and reason
+type AbortConnectionErr struct {
+	Code   int
+	Reason string
+}
+
+// AbortConnection constructs a new AbortConnectionErr error closing
+// the connection with the given close code and reason.
+// Application-specific close codes should be within the range 4000-4999
+// (for example 4001 "auth expired"), codes that can't be sent
+// in a close frame (such as 0, 1005 or 1006) are replaced
+// by 1008 (policy violation)
+func AbortConnection(code int, reason string) error {
+	return AbortConnectionErr{
+		Code:   code,
+		Reason: reason,
+	}
+}
+
+func (err AbortConnectionErr) Error() string {
+	return fmt.Sprintf("Connection aborted (%d): %s", err.Code, err.Reason)
+}
+
+// ConnClosedErr represents an error type indicating that the connection
+// was closed by the other side providing a close code and reason
+type ConnClosedErr struct {
+	Code   int
+	Reason string
+}
+
+func (err ConnClosedErr) Error() string {
+	return fmt.Sprintf("Connection closed (%d): %s", err.Code, err.Reason)
+}
+
 // ProtocolErr represents an error type
 // indicating an error in the protocol implementation
 type ProtocolErr struct {
@@ -169,6 +352,39 @@ func (err ProtocolErr) Error() string {
 	return err.cause.Error()
 }
 
+// ListenErr represents an error type indicating that the server
+// failed setting up the TCP/IP listener on the configured address
+type ListenErr struct {
+	Address string
+	Cause   error
+}
+
+// NewListenErr constructs a new ListenErr error
+// based on the address and the actual error
+func NewListenErr(address string, err error) ListenErr {
+	return ListenErr{
+		Address: address,
+		Cause:   err,
+	}
+}
+
+// IsAddrInUse returns true if the listener failed
+// because the address is already in use
+func (err ListenErr) IsAddrInUse() bool {
+	return errors.Is(err.Cause, syscall.EADDRINUSE)
+}
+
+func (err ListenErr) Error() string {
+	if err.IsAddrInUse() {
+		return fmt.Sprintf("Address %s is already in use", err.Address)
+	}
+	return fmt.Sprintf(
+		"Failed setting up TCP/IP listener on %s: %s",
+		err.Address,
+		err.Cause,
+	)
+}
+
 // CanceledErr represents a failure due to cancelation
 type CanceledErr struct {
 	cause error
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -16,6 +17,15 @@ func (clt *ChatroomClient) OnSessionCreated(newSession *webwire.Session) {
 	log.Printf("Authenticated as %s", username)
 }
 
+// OnServerRequest implements the webwireClient.Implementation interface.
+// The chatroom server doesn't send any requests to its clients
+func (clt *ChatroomClient) OnServerRequest(
+	_ context.Context,
+	_ webwire.Message,
+) (webwire.Payload, error) {
+	return nil, nil
+}
+
 // OnSignal implements the webwireClient.Implementation interface.
 // it's invoked when the client receives a signal from the server
 // containing a chatroom message
@@ -36,7 +46,16 @@ func (clt *ChatroomClient) OnSignal(message webwire.Message) {
 }
 
 // OnDisconnected implements the wwrclt.Implementation interface
-func (clt *ChatroomClient) OnDisconnected() {}
+func (clt *ChatroomClient) OnDisconnected(_ error) {}
+
+// OnReconnectionFailed implements the wwrclt.Implementation interface
+func (clt *ChatroomClient) OnReconnectionFailed() {}
+
+// OnSessionCreationFailed implements the wwrclt.Implementation interface
+func (clt *ChatroomClient) OnSessionCreationFailed(_ error) {}
 
 // OnSessionClosed implements the wwrclt.Implementation interface
-func (clt *ChatroomClient) OnSessionClosed() {}
+func (clt *ChatroomClient) OnSessionClosed(_ string) {}
+
+// OnSessionInfoChanged implements the wwrclt.Implementation interface
+func (clt *ChatroomClient) OnSessionInfoChanged(_ webwire.SessionInfo) {}
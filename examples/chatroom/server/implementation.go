@@ -222,7 +222,7 @@ func (srv *ChatRoomServer) OnRequest(
 
 // OnClientConnected implements the webwire.ServerImplementation interface.
 // Registers new connected clients
-func (srv *ChatRoomServer) OnClientConnected(newClient wwr.Connection) {
+func (srv *ChatRoomServer) OnClientConnected(newClient wwr.Connection) error {
 	info := newClient.Info()
 	log.Printf(
 		"New client connected: %s | %s",
@@ -232,6 +232,7 @@ func (srv *ChatRoomServer) OnClientConnected(newClient wwr.Connection) {
 	srv.lock.Lock()
 	defer srv.lock.Unlock()
 	srv.connected[newClient] = true
+	return nil
 }
 
 // OnClientDisconnected implements the webwire.ServerImplementation interface.
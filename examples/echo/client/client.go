@@ -36,10 +36,19 @@ func NewEchoClient(serverAddr string) *EchoClient {
 }
 
 // OnDisconnected implements the wwrclt.Implementation interface
-func (clt *EchoClient) OnDisconnected() {}
+func (clt *EchoClient) OnDisconnected(_ error) {}
+
+// OnReconnectionFailed implements the wwrclt.Implementation interface
+func (clt *EchoClient) OnReconnectionFailed() {}
+
+// OnSessionCreationFailed implements the wwrclt.Implementation interface
+func (clt *EchoClient) OnSessionCreationFailed(_ error) {}
 
 // OnSessionClosed implements the wwrclt.Implementation interface
-func (clt *EchoClient) OnSessionClosed() {}
+func (clt *EchoClient) OnSessionClosed(_ string) {}
+
+// OnSessionInfoChanged implements the wwrclt.Implementation interface
+func (clt *EchoClient) OnSessionInfoChanged(_ wwr.SessionInfo) {}
 
 // OnSessionCreated implements the wwrclt.Implementation interface
 func (clt *EchoClient) OnSessionCreated(_ *wwr.Session) {}
@@ -47,6 +56,14 @@ func (clt *EchoClient) OnSessionCreated(_ *wwr.Session) {}
 // OnSignal implements the wwrclt.Implementation interface
 func (clt *EchoClient) OnSignal(_ wwr.Message) {}
 
+// OnServerRequest implements the wwrclt.Implementation interface
+func (clt *EchoClient) OnServerRequest(
+	_ context.Context,
+	_ wwr.Message,
+) (wwr.Payload, error) {
+	return nil, nil
+}
+
 // Request sends a message to the server and returns the reply.
 // panics if the request fails for whatever reason
 func (clt *EchoClient) Request(
@@ -34,7 +34,9 @@ func (srv *EchoServer) OnSignal(
 
 // OnClientConnected implements the webwire.ServerImplementation interface.
 // Does nothing, not needed in this example
-func (srv *EchoServer) OnClientConnected(client wwr.Connection) {}
+func (srv *EchoServer) OnClientConnected(client wwr.Connection) error {
+	return nil
+}
 
 // OnClientDisconnected implements the webwire.ServerImplementation interface
 // Does nothing, not needed in this example
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"sync"
@@ -47,14 +48,31 @@ func NewPubSubClient(serverAddr string, counterTarget uint) *PubSubClient {
 }
 
 // OnDisconnected implements the wwrclt.Implementation interface
-func (clt *PubSubClient) OnDisconnected() {}
+func (clt *PubSubClient) OnDisconnected(_ error) {}
+
+// OnReconnectionFailed implements the wwrclt.Implementation interface
+func (clt *PubSubClient) OnReconnectionFailed() {}
+
+// OnSessionCreationFailed implements the wwrclt.Implementation interface
+func (clt *PubSubClient) OnSessionCreationFailed(_ error) {}
 
 // OnSessionClosed implements the wwrclt.Implementation interface
-func (clt *PubSubClient) OnSessionClosed() {}
+func (clt *PubSubClient) OnSessionClosed(_ string) {}
+
+// OnSessionInfoChanged implements the wwrclt.Implementation interface
+func (clt *PubSubClient) OnSessionInfoChanged(_ wwr.SessionInfo) {}
 
 // OnSessionCreated implements the wwrclt.Implementation interface
 func (clt *PubSubClient) OnSessionCreated(_ *wwr.Session) {}
 
+// OnServerRequest implements the wwrclt.Implementation interface
+func (clt *PubSubClient) OnServerRequest(
+	_ context.Context,
+	_ wwr.Message,
+) (wwr.Payload, error) {
+	return nil, nil
+}
+
 // OnSignal implements the wwrclt.Implementation interface
 func (clt *PubSubClient) OnSignal(message wwr.Message) {
 	clt.counter++
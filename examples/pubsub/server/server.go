@@ -72,10 +72,11 @@ func (srv *PubSubServer) BeforeUpgrade(
 
 // OnClientConnected implements the webwire.ServerImplementation interface.
 // Registers a new connected client
-func (srv *PubSubServer) OnClientConnected(client wwr.Connection) {
+func (srv *PubSubServer) OnClientConnected(client wwr.Connection) error {
 	srv.mapLock.Lock()
 	srv.connectedClients[client] = true
 	srv.mapLock.Unlock()
+	return nil
 }
 
 // OnClientDisconnected implements the webwire.ServerImplementation interface
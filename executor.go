@@ -0,0 +1,10 @@
+package webwire
+
+// goroutineExecutor represents the default executor
+// spawning a new goroutine for each submitted task
+type goroutineExecutor struct{}
+
+// Submit implements the Executor interface
+func (exec goroutineExecutor) Submit(task func()) {
+	go task()
+}
@@ -0,0 +1,42 @@
+package webwire
+
+import msg "github.com/qbeon/webwire-go/message"
+
+// handleClientReply resolves the pending server request the given
+// client reply message refers to. Returns false if the message
+// isn't a reply to a server request.
+// Replies to requests that are no longer awaited are dropped
+func (srv *server) handleClientReply(
+	con *connection,
+	message *msg.Message,
+) bool {
+	var reply serverReply
+	switch message.Type {
+	case msg.MsgClientReplyBinary:
+		fallthrough
+	case msg.MsgClientReplyUtf8:
+		fallthrough
+	case msg.MsgClientReplyUtf16:
+		reply.payload = &EncodedPayload{
+			Payload: message.Payload,
+			Codec:   srv.options.Codec,
+		}
+	case msg.MsgClientErrorReply:
+		// The message name contains the error code in case of
+		// error reply messages, while the UTF8 encoded error message is
+		// contained in the message payload
+		reply.err = ReqErr{
+			Code:    message.Name,
+			Message: string(message.Payload.Data),
+		}
+	case msg.MsgClientInternalError:
+		reply.err = ClientInternalErr{
+			Message: string(message.Payload.Data),
+		}
+	default:
+		return false
+	}
+
+	con.requests.resolve(message.Identifier, reply)
+	return true
+}
@@ -6,48 +6,100 @@ import (
 	msg "github.com/qbeon/webwire-go/message"
 )
 
-// handleMessage handles incoming messages
-func (srv *server) handleMessage(con *connection, message []byte) {
-	// Parse message
-	var parsedMessage msg.Message
-	msgTypeParsed, parserErr := parsedMessage.Parse(message)
-	if !msgTypeParsed {
-		// Couldn't determine message type, drop message
+// dispatchMessage parses the given incoming message and resolves replies
+// to requests sent by the server right away while the handling
+// of all other messages is submitted to the executor
+func (srv *server) dispatchMessage(con *connection, message []byte) {
+	parsedMessage, ok := srv.parseMessage(con, message)
+	if !ok {
+		return
+	}
+
+	// Replies to requests sent by the server aren't handled as operations
+	// since they're awaited by the requesting handler.
+	// They must never queue behind the handlers awaiting them
+	if srv.handleClientReply(con, parsedMessage) {
 		return
-	} else if parserErr != nil {
+	}
+
+	srv.options.Executor.Submit(func() {
+		srv.handleMessage(con, parsedMessage)
+	})
+}
+
+// parseMessage parses the given incoming message.
+// Returns false if the message couldn't be parsed
+func (srv *server) parseMessage(
+	con *connection,
+	message []byte,
+) (*msg.Message, bool) {
+	parsedMessage, parserErr := msg.Parse(message)
+	switch parserErr.(type) {
+	case nil:
+		return parsedMessage, true
+	case msg.UnknownTypeErr:
+		// Couldn't determine message type, drop message
+		srv.onMessageParseError(con, message, parserErr)
+	default:
 		// Couldn't parse message, protocol error
 		srv.warnLog.Println("Parser error:", parserErr)
+		srv.onMessageParseError(con, message, parserErr)
 
 		// Respond with an error but don't break the connection
 		// because protocol errors are not critical errors
-		srv.failMsg(con, &parsedMessage, ProtocolErr{})
-		return
+		srv.failMsg(con, parsedMessage, ProtocolErr{})
 	}
+	return nil, false
+}
 
-	// Deregister the handler only if a handler was registered
-	if srv.registerHandler(con, &parsedMessage) {
-		defer srv.deregisterHandler(con)
+// handleMessage handles incoming signals, requests and session messages
+func (srv *server) handleMessage(con *connection, parsedMessage *msg.Message) {
+	// Let the hooks observe every inbound signal before registering
+	// the handler to include signals ignored during the shutdown
+	switch parsedMessage.Type {
+	case msg.MsgSignalBinary, msg.MsgSignalUtf8, msg.MsgSignalUtf16:
+		srv.onSignalReceived(con, parsedMessage)
 	}
 
+	// Don't handle the message if the handler couldn't be registered
+	// due to either the server or the connection shutting down
+	if !srv.registerHandler(con, parsedMessage) {
+		return
+	}
+	defer srv.deregisterHandler(con)
+
 	switch parsedMessage.Type {
 	case msg.MsgSignalBinary:
 		fallthrough
 	case msg.MsgSignalUtf8:
 		fallthrough
 	case msg.MsgSignalUtf16:
-		srv.handleSignal(con, &parsedMessage)
+		srv.handleSignal(con, parsedMessage)
 
 	case msg.MsgRequestBinary:
 		fallthrough
 	case msg.MsgRequestUtf8:
 		fallthrough
 	case msg.MsgRequestUtf16:
-		srv.handleRequest(con, &parsedMessage)
+		srv.handleRequest(con, parsedMessage)
 
 	case msg.MsgRestoreSession:
-		srv.handleSessionRestore(con, &parsedMessage)
+		fallthrough
+	case msg.MsgRestoreSessionVerified:
+		srv.handleSessionRestore(con, parsedMessage)
 	case msg.MsgCloseSession:
-		srv.handleSessionClosure(con, &parsedMessage)
+		srv.handleSessionClosure(con, parsedMessage)
+	}
+}
+
+// onMessageParseError invokes the parse error handler if any
+func (srv *server) onMessageParseError(
+	con *connection,
+	message []byte,
+	err error,
+) {
+	if srv.options.ParseErrorHandler != nil {
+		srv.options.ParseErrorHandler.OnMessageParseError(con, message, err)
 	}
 }
 
@@ -80,9 +132,16 @@ func (srv *server) registerHandler(
 	}
 	srv.opsLock.Unlock()
 
-	if failMsg && message.RequiresReply() {
-		// Don't process the message, fail it
-		srv.failMsgShutdown(con, message)
+	if failMsg {
+		// Release the acquired handler slot
+		if con.options.ConcurrencyLimit() > 0 {
+			con.handlerSlots.Release(1)
+		}
+
+		// Don't process the message, fail it if it requires a reply
+		if message.RequiresReply() {
+			srv.failMsgShutdown(con, message)
+		}
 		return false
 	}
 
@@ -116,17 +175,52 @@ func (srv *server) fulfillMsg(
 	replyPayloadData []byte,
 ) {
 	// Send reply
-	if err := con.sock.Write(
-		msg.NewReplyMessage(
-			message.Identifier,
-			replyPayloadEncoding,
-			replyPayloadData,
-		),
-	); err != nil {
-		srv.errorLog.Println("Writing failed:", err)
+	srv.writeReply(con, msg.NewReplyMessage(
+		message.Identifier,
+		replyPayloadEncoding,
+		replyPayloadData,
+	))
+}
+
+// writeReply writes the given reply message to the connection.
+// A failed write is considered a disconnection of the client,
+// in which case the connection is closed, the OnClientDisconnected hook
+// is invoked and no further replies are written to the connection
+func (srv *server) writeReply(con *connection, reply []byte) {
+	if con.hasWriteFailed() {
+		return
+	}
+	if err := con.sock.Write(reply); err != nil {
+		srv.warnLog.Printf("Writing reply failed, closing connection: %s", err)
+		con.setWriteFailed()
+		con.Close()
+		srv.notifyClientDisconnected(con)
 	}
 }
 
+// notifyClientDisconnected invokes the OnClientDisconnected hook
+// ensuring it's invoked only once per connection
+func (srv *server) notifyClientDisconnected(con *connection) {
+	con.disconnected.Do(func() {
+		srv.impl.OnClientDisconnected(con)
+	})
+}
+
+// newErrorReplyMessage composes an error reply message for the given
+// request error, the payload of the request error is attached if any
+func newErrorReplyMessage(identifier [8]byte, err ReqErr) []byte {
+	if err.Payload == nil {
+		return msg.NewErrorReplyMessage(identifier, err.Code, err.Message)
+	}
+	return msg.NewErrorReplyPayloadMessage(
+		identifier,
+		err.Code,
+		err.Message,
+		err.Payload.Encoding(),
+		err.Payload.Data(),
+	)
+}
+
 // failMsg fails the message returning an error reply
 func (srv *server) failMsg(
 	con *connection,
@@ -142,17 +236,9 @@ func (srv *server) failMsg(
 	var replyMsg []byte
 	switch err := reqErr.(type) {
 	case ReqErr:
-		replyMsg = msg.NewErrorReplyMessage(
-			message.Identifier,
-			err.Code,
-			err.Message,
-		)
+		replyMsg = newErrorReplyMessage(message.Identifier, err)
 	case *ReqErr:
-		replyMsg = msg.NewErrorReplyMessage(
-			message.Identifier,
-			err.Code,
-			err.Message,
-		)
+		replyMsg = newErrorReplyMessage(message.Identifier, *err)
 	case MaxSessConnsReachedErr:
 		replyMsg = msg.NewSpecialRequestReplyMessage(
 			msg.MsgMaxSessConnsReached,
@@ -173,7 +259,35 @@ func (srv *server) failMsg(
 			msg.MsgReplyProtocolError,
 			message.Identifier,
 		)
+	case UnauthenticatedErr:
+		replyMsg = msg.NewSpecialRequestReplyMessage(
+			msg.MsgReplyUnauthenticated,
+			message.Identifier,
+		)
+	case PayloadTooLargeErr:
+		replyMsg = msg.NewSpecialRequestReplyMessage(
+			msg.MsgReplyPayloadTooLarge,
+			message.Identifier,
+		)
+	case RateLimitedErr:
+		replyMsg = msg.NewSpecialRequestReplyMessage(
+			msg.MsgReplyRateLimited,
+			message.Identifier,
+		)
+	case ObserverErr:
+		replyMsg = msg.NewSpecialRequestReplyMessage(
+			msg.MsgReplyObserver,
+			message.Identifier,
+		)
 	default:
+		if reqErr != nil && srv.options.ExposeInternalErrors == Enabled {
+			// Expose the internal error message to the client for debugging
+			replyMsg = msg.NewInternalErrorReplyMessage(
+				message.Identifier,
+				reqErr.Error(),
+			)
+			break
+		}
 		replyMsg = msg.NewSpecialRequestReplyMessage(
 			msg.MsgInternalError,
 			message.Identifier,
@@ -181,17 +295,13 @@ func (srv *server) failMsg(
 	}
 
 	// Send request failure notification
-	if err := con.sock.Write(replyMsg); err != nil {
-		srv.errorLog.Println("Writing failed:", err)
-	}
+	srv.writeReply(con, replyMsg)
 }
 
 // failMsgShutdown sends request failure reply due to current server shutdown
 func (srv *server) failMsgShutdown(con *connection, message *msg.Message) {
-	if err := con.sock.Write(msg.NewSpecialRequestReplyMessage(
+	srv.writeReply(con, msg.NewSpecialRequestReplyMessage(
 		msg.MsgReplyShutdown,
 		message.Identifier,
-	)); err != nil {
-		srv.errorLog.Println("Writing failed:", err)
-	}
+	))
 }
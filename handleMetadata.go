@@ -9,9 +9,15 @@ import (
 func (srv *server) handleMetadata(resp http.ResponseWriter) {
 	resp.Header().Set("Content-Type", "application/json")
 	resp.Header().Set("Access-Control-Allow-Origin", "*")
+	var codec string
+	if srv.options.Codec != nil {
+		codec = srv.options.Codec.Name()
+	}
 	json.NewEncoder(resp).Encode(struct {
 		ProtocolVersion string `json:"protocol-version"`
+		Codec           string `json:"codec,omitempty"`
 	}{
 		protocolVersion,
+		codec,
 	})
 }
@@ -9,36 +9,178 @@ import (
 // handleRequest handles incoming requests
 // and returns an error if the ongoing connection cannot be proceeded
 func (srv *server) handleRequest(conn *connection, message *msg.Message) {
-	replyPayload, returnedErr := srv.impl.OnRequest(
-		context.Background(),
+	// Reject requests from read-only observer connections
+	if conn.info.Observer {
+		srv.failMsg(conn, message, ObserverErr{})
+		return
+	}
+
+	// Reject requests with names exceeding the configured limit
+	if srv.options.MaxRequestNameLength > 0 &&
+		uint(len(message.Name)) > srv.options.MaxRequestNameLength {
+		srv.failMsg(conn, message, ProtocolErr{})
+		return
+	}
+
+	// Reject requests with payloads exceeding the configured limit
+	if srv.options.MaxRequestPayloadSize > 0 &&
+		uint(len(message.Payload.Data)) > srv.options.MaxRequestPayloadSize {
+		srv.failMsg(conn, message, PayloadTooLargeErr{})
+		return
+	}
+
+	// Let the request filter reject the request before it's dispatched
+	if srv.options.RequestFilter != nil {
+		if err := srv.options.RequestFilter.FilterRequest(
+			conn,
+			NewCodecMessageWrapper(message, srv.options.Codec),
+		); err != nil {
+			srv.failRequest(conn, message, err, nil)
+			return
+		}
+	}
+
+	// Reject requests from connections without a session
+	// if the server requires a session for requests
+	if srv.options.RequireSessionForRequests == Enabled && !conn.HasSession() {
+		srv.failMsg(conn, message, UnauthenticatedErr{})
+		return
+	}
+
+	ctx := context.Background()
+	wrappedMessage := NewCodecMessageWrapper(message, srv.options.Codec)
+
+	if srv.options.Hooks != nil {
+		srv.options.Hooks.BeforeMessage(ctx, wrappedMessage)
+	}
+
+	replyPayload, returnedErr := srv.callRequestHandler(
+		ctx,
 		conn,
-		NewMessageWrapper(message),
+		message.Name,
+		wrappedMessage,
 	)
-	switch returnedErr.(type) {
-	case nil:
-		// Initialize payload encoding & data
-		var encoding PayloadEncoding
-		var data []byte
-		if replyPayload != nil {
-			encoding = replyPayload.Encoding()
-			data = replyPayload.Data()
-		}
 
-		srv.fulfillMsg(
-			conn,
-			message,
-			encoding,
-			data,
-		)
+	if srv.options.Hooks != nil {
+		srv.options.Hooks.AfterMessage(ctx, wrappedMessage, returnedErr)
+	}
+
+	if returnedErr != nil {
+		srv.failRequest(conn, message, returnedErr, replyPayload)
+		return
+	}
+
+	// Initialize payload encoding & data
+	var encoding PayloadEncoding
+	var data []byte
+	if replyPayload != nil {
+		encoding = replyPayload.Encoding()
+		data = replyPayload.Data()
+	}
+
+	srv.fulfillMsg(
+		conn,
+		message,
+		encoding,
+		data,
+	)
+}
+
+// callRequestHandler calls the request handler registering the request
+// as pending until the handler returns if pending requests are reported
+func (srv *server) callRequestHandler(
+	ctx context.Context,
+	conn *connection,
+	name string,
+	message Message,
+) (Payload, error) {
+	if srv.options.ReportPendingRequests == Enabled {
+		srv.pendingRequests.register(name)
+		defer srv.pendingRequests.deregister(name)
+	}
+	return srv.requestHandler(ctx, conn, message)
+}
+
+// failRequest fails the request with the given error
+// attaching the reply payload to request errors.
+// Errors of non-webwire types are logged as internal errors
+func (srv *server) failRequest(
+	conn *connection,
+	message *msg.Message,
+	returnedErr error,
+	replyPayload Payload,
+) {
+	switch err := returnedErr.(type) {
 	case ReqErr:
-		srv.failMsg(conn, message, returnedErr)
+		srv.failMsg(conn, message, attachErrorPayload(err, replyPayload))
 	case *ReqErr:
-		srv.failMsg(conn, message, returnedErr)
+		srv.failMsg(conn, message, attachErrorPayload(*err, replyPayload))
+	case UnauthenticatedErr, PayloadTooLargeErr, RateLimitedErr, ObserverErr:
+		// Expected rejections aren't internal errors
+		srv.failMsg(conn, message, err)
+	case AbortConnectionErr, *AbortConnectionErr:
+		// Close the connection instead of replying
+		if err := closeWithError(conn.sock, err); err != nil {
+			srv.warnLog.Printf("Couldn't abort connection: %s", err)
+		}
 	default:
-		srv.errorLog.Printf(
+		errCtx := newErrorContext(ErrOpRequest, conn)
+		errCtx.SessionKey = conn.SessionKey()
+		errCtx.MessageName = message.Name
+		srv.logError(
+			errCtx,
+			returnedErr,
 			"Internal error during request handling: %s",
 			returnedErr,
 		)
 		srv.failMsg(conn, message, returnedErr)
 	}
 }
+
+// closePolicyViolation is the WebSocket close code sent to connections
+// aborted with a close code that can't be sent in a close frame
+const closePolicyViolation = 1008
+
+// closeWithError closes the given socket notifying the client
+// about the error. AbortConnectionErr errors close the socket
+// with their close code and reason, any other error is used as the reason
+// of a policy violation closure
+func closeWithError(sock Socket, err error) error {
+	switch abortErr := err.(type) {
+	case AbortConnectionErr:
+		return closeAborted(sock, abortErr)
+	case *AbortConnectionErr:
+		return closeAborted(sock, *abortErr)
+	}
+	return sock.CloseWithReason(err.Error())
+}
+
+// closeAborted closes the given socket with the close code and reason
+// of the given abort error
+func closeAborted(sock Socket, err AbortConnectionErr) error {
+	return sock.CloseWithCode(abortCloseCode(err.Code), err.Reason)
+}
+
+// abortCloseCode returns the given close code if it's allowed to be sent
+// in a close frame, otherwise it falls back to a policy violation.
+// Reserved codes such as 1005 (no status), 1006 (abnormal closure)
+// and 1015 (TLS handshake failure) as well as unassigned codes
+// are rejected by the receiving side
+func abortCloseCode(code int) int {
+	switch {
+	case code >= 1000 && code <= 1003,
+		code >= 1007 && code <= 1013,
+		code >= 3000 && code <= 4999:
+		return code
+	}
+	return closePolicyViolation
+}
+
+// attachErrorPayload attaches the reply payload returned alongside
+// a request error to the error unless the error already carries a payload
+func attachErrorPayload(err ReqErr, replyPayload Payload) ReqErr {
+	if err.Payload == nil {
+		err.Payload = replyPayload
+	}
+	return err
+}
@@ -22,12 +22,16 @@ func (srv *server) handleSessionClosure(
 	}
 
 	// Deregister session from active sessions registry
+	// and drop its connection limit override
 	srv.sessionRegistry.deregister(conn)
+	srv.sessionRegistry.dropMaxConns(conn.SessionKey())
 
 	// Synchronize session destruction to the client
-	if err := conn.notifySessionClosed(); err != nil {
+	if err := conn.notifySessionClosed(""); err != nil {
 		srv.failMsg(conn, message, nil)
-		srv.errorLog.Printf("CRITICAL: Internal server error, "+
+		errCtx := newErrorContext(ErrOpSessionClosure, conn)
+		errCtx.SessionKey = conn.SessionKey()
+		srv.logError(errCtx, err, "CRITICAL: Internal server error, "+
 			"couldn't notify client about the session destruction: %s",
 			err,
 		)
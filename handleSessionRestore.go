@@ -1,14 +1,16 @@
 package webwire
 
 import (
-	"encoding/json"
-	"fmt"
+	"time"
 
 	msg "github.com/qbeon/webwire-go/message"
 )
 
 // handleSessionRestore handles session restoration (by session key) requests
-// and returns an error if the ongoing connection cannot be proceeded
+// and returns an error if the ongoing connection cannot be proceeded.
+// If the connection already has another session then it's deregistered from
+// the previous session and registered to the restored one. The previous
+// session is not closed and remains available to its other connections
 func (srv *server) handleSessionRestore(
 	con *connection,
 	message *msg.Message,
@@ -18,22 +20,37 @@ func (srv *server) handleSessionRestore(
 		return
 	}
 
+	// Verified session restoration requests carry the key in the name
+	// and the proof in the payload
 	key := string(message.Payload.Data)
+	var proof []byte
+	if message.Type == msg.MsgRestoreSessionVerified {
+		key = message.Name
+		proof = message.Payload.Data
+	}
 
-	sessConsNum := srv.sessionRegistry.sessionConnectionsNum(key)
-	if sessConsNum >= 0 && srv.sessionRegistry.maxConns > 0 &&
-		uint(sessConsNum+1) > srv.sessionRegistry.maxConns {
-		srv.failMsg(con, message, MaxSessConnsReachedErr{})
+	// Keys that couldn't have been generated can't identify a session
+	// and must not reach the session manager
+	if !isValidSessionKey(key) {
+		srv.failMsg(con, message, SessNotFoundErr{})
 		return
 	}
 
+	restorationErrCtx := newErrorContext(ErrOpSessionRestoration, con)
+	restorationErrCtx.SessionKey = key
+
 	// Call session manager lookup hook
-	result, err := srv.sessionManager.OnSessionLookup(key)
+	result, err := srv.onSessionLookup(key)
 
 	if err != nil {
 		// Fail message with internal error and log it in case the handler fails
 		srv.failMsg(con, message, nil)
-		srv.errorLog.Printf("CRITICAL: Session search handler failed: %s", err)
+		srv.logError(
+			restorationErrCtx,
+			err,
+			"CRITICAL: Session search handler failed: %s",
+			err,
+		)
 		return
 	}
 
@@ -43,21 +60,76 @@ func (srv *server) handleSessionRestore(
 		return
 	}
 
-	sessionCreation := result.Creation()
-	sessionLastLookup := result.LastLookup()
+	// Treat expired sessions as not found
+	restoredSession := &Session{
+		Key:        key,
+		Creation:   result.Creation(),
+		LastLookup: result.LastLookup(),
+		Expiration: lookupResultExpiration(result),
+	}
+	now := time.Now()
+	slidingTTL := srv.options.SessionTTL > 0 &&
+		srv.options.SlidingSessionTTL == Enabled
+	if slidingTTL {
+		// Sliding sessions expire when they were neither created
+		// nor looked up within the session TTL
+		lastActivity := restoredSession.Creation
+		if restoredSession.LastLookup.After(lastActivity) {
+			lastActivity = restoredSession.LastLookup
+		}
+		restoredSession.Expiration = lastActivity.Add(srv.options.SessionTTL)
+		if !restoredSession.IsExpired(now) {
+			// Extend the expiration from this lookup on
+			// to be persisted alongside the lookup time
+			restoredSession.LastLookup = now.UTC()
+			restoredSession.Expiration = now.Add(srv.options.SessionTTL)
+		}
+	}
+	if restoredSession.IsExpired(now) {
+		srv.failMsg(con, message, SessNotFoundErr{})
+		return
+	}
+
+	// Verify the restoration if a verifier is defined
+	if srv.sessionRestoreVerifier != nil {
+		if err := srv.sessionRestoreVerifier.OnSessionRestoreVerify(
+			con,
+			key,
+			result,
+			proof,
+		); err != nil {
+			switch err.(type) {
+			case ReqErr:
+			case *ReqErr:
+			default:
+				srv.logError(
+					restorationErrCtx,
+					err,
+					"Session restoration verification failed: %s",
+					err,
+				)
+			}
+			srv.failMsg(con, message, err)
+			return
+		}
+	}
+
 	sessionInfo := result.Info()
 
-	// JSON encode the session
+	// Encode the session
 	encodedSessionObj := JSONEncodedSession{
 		Key:        key,
-		Creation:   sessionCreation,
-		LastLookup: sessionLastLookup,
+		Creation:   restoredSession.Creation,
+		LastLookup: restoredSession.LastLookup,
+		Expiration: expirationField(restoredSession.Expiration),
 		Info:       sessionInfo,
 	}
-	encodedSession, err := json.Marshal(&encodedSessionObj)
+	encodedSession, err := srv.options.SessionCodec.Encode(&encodedSessionObj)
 	if err != nil {
 		srv.failMsg(con, message, nil)
-		srv.errorLog.Printf(
+		srv.logError(
+			restorationErrCtx,
+			err,
 			"Couldn't encode session object (%v): %s",
 			encodedSessionObj,
 			err,
@@ -66,22 +138,44 @@ func (srv *server) handleSessionRestore(
 	}
 
 	// Parse attached session info
-	var parsedSessInfo SessionInfo
 	if sessionInfo != nil && srv.sessionInfoParser != nil {
-		parsedSessInfo = srv.sessionInfoParser(sessionInfo)
+		restoredSession.Info = srv.sessionInfoParser(sessionInfo)
 	}
 
-	con.setSession(&Session{
-		Key:        key,
-		Creation:   sessionCreation,
-		LastLookup: sessionLastLookup,
-		Info:       parsedSessInfo,
-	})
+	// Switch the connection to the restored session,
+	// registering it moves it out of its current session if any.
+	// The registration atomically enforces the connection limit
+	// of the session and keeps the current session if it's reached
+	con.sessionLock.Lock()
+	previousSession := con.session
+	con.session = restoredSession
 	if err := srv.sessionRegistry.register(con); err != nil {
-		panic(fmt.Errorf("The number of concurrent session connections was " +
-			"unexpectedly exceeded",
-		))
+		con.session = previousSession
+		con.sessionLock.Unlock()
+		srv.failMsg(con, message, err)
+		return
+	}
+	con.sessionLock.Unlock()
+
+	// Persist the refreshed sliding expiration to let the session manager
+	// delete the session once it expires
+	if slidingTTL {
+		if err := srv.onSessionUpdated(con); err != nil {
+			errCtx := newErrorContext(ErrOpSessionUpdate, con)
+			errCtx.SessionKey = key
+			srv.logError(
+				errCtx,
+				err,
+				"OnSessionUpdated hook failed: %s",
+				err,
+			)
+		}
 	}
 
-	srv.fulfillMsg(con, message, EncodingUtf8, encodedSession)
+	srv.fulfillMsg(
+		con,
+		message,
+		srv.options.SessionCodec.Encoding(),
+		encodedSession,
+	)
 }
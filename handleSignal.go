@@ -6,9 +6,28 @@ import (
 	msg "github.com/qbeon/webwire-go/message"
 )
 
+// onSignalReceived lets the hooks observe the given inbound signal.
+// Signals of read-only observer connections are ignored
+func (srv *server) onSignalReceived(con *connection, message *msg.Message) {
+	if srv.options.Hooks == nil || con.info.Observer {
+		return
+	}
+	srv.options.Hooks.OnSignalReceived(
+		con,
+		NewCodecMessageWrapper(message, srv.options.Codec),
+	)
+}
+
 // handleSignal handles incoming signals
 // and returns an error if the ongoing connection cannot be proceeded
 func (srv *server) handleSignal(con *connection, message *msg.Message) {
+	// Ignore signals from read-only observer connections
+	if con.info.Observer {
+		return
+	}
+
+	wrappedMessage := NewCodecMessageWrapper(message, srv.options.Codec)
+
 	srv.opsLock.Lock()
 	// Ignore incoming signals during shutdown
 	if srv.shutdown {
@@ -18,11 +37,20 @@ func (srv *server) handleSignal(con *connection, message *msg.Message) {
 	srv.currentOps++
 	srv.opsLock.Unlock()
 
-	srv.impl.OnSignal(
-		context.Background(),
-		con,
-		NewMessageWrapper(message),
-	)
+	// The context is cancelled when the connection is closed
+	// during the handling of the signal
+	ctx, cancel := context.WithCancel(con.ctx)
+	defer cancel()
+
+	if srv.options.Hooks != nil {
+		srv.options.Hooks.BeforeMessage(ctx, wrappedMessage)
+	}
+
+	srv.impl.OnSignal(ctx, con, wrappedMessage)
+
+	if srv.options.Hooks != nil {
+		srv.options.Hooks.AfterMessage(ctx, wrappedMessage, nil)
+	}
 
 	// Mark signal as done and shutdown the server
 	// if scheduled and no ops are left
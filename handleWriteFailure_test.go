@@ -0,0 +1,111 @@
+package webwire
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	msg "github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// testWriteFailureImpl implements the ServerImplementation interface
+// counting the invocations of the request and disconnection hooks
+type testWriteFailureImpl struct {
+	requests      int32
+	disconnected  int32
+	beforeRequest func()
+}
+
+func (impl *testWriteFailureImpl) OnOptions(_ http.ResponseWriter) {}
+
+func (impl *testWriteFailureImpl) BeforeUpgrade(
+	_ http.ResponseWriter,
+	_ *http.Request,
+) ConnectionOptions {
+	return AcceptConnection(UnlimitedConcurrency)
+}
+
+func (impl *testWriteFailureImpl) OnClientConnected(_ Connection) error {
+	return nil
+}
+
+func (impl *testWriteFailureImpl) OnClientDisconnected(_ Connection) {
+	atomic.AddInt32(&impl.disconnected, 1)
+}
+
+func (impl *testWriteFailureImpl) OnSignal(
+	_ context.Context,
+	_ Connection,
+	_ Message,
+) {
+}
+
+func (impl *testWriteFailureImpl) OnRequest(
+	_ context.Context,
+	_ Connection,
+	_ Message,
+) (Payload, error) {
+	atomic.AddInt32(&impl.requests, 1)
+	if impl.beforeRequest != nil {
+		impl.beforeRequest()
+	}
+	return NewPayload(EncodingBinary, []byte("reply")), nil
+}
+
+// TestHandleWriteFailure tests the client disconnecting right before
+// the reply is written expecting the connection to be closed,
+// the disconnection hook to be invoked once and the operation to be done
+func TestHandleWriteFailure(t *testing.T) {
+	sock := &testSignalSocket{}
+	impl := &testWriteFailureImpl{
+		// Simulate the client disconnecting during the request handling
+		beforeRequest: func() { sock.dead = true },
+	}
+	instance, err := NewHeadlessServer(impl, ServerOptions{
+		Sessions: Disabled,
+		WarnLog:  log.New(ioutil.Discard, "", 0),
+		ErrorLog: log.New(ioutil.Discard, "", 0),
+	})
+	require.NoError(t, err)
+	srv := instance.(*server)
+
+	conn := newConnection(
+		sock,
+		"",
+		srv,
+		AcceptConnection(UnlimitedConcurrency),
+	)
+	request, err := msg.Parse(msg.NewRequestMessage(
+		[8]byte{1},
+		"sample",
+		pld.Binary,
+		[]byte("sample"),
+	))
+	require.NoError(t, err)
+
+	srv.handleMessage(conn, request)
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&impl.requests))
+	require.False(t, conn.IsActive())
+	require.Equal(t, int32(1), atomic.LoadInt32(&impl.disconnected))
+	require.Equal(t, uint32(0), srv.currentOps)
+
+	// Expect no more replies to be written after the failure
+	sock.dead = false
+	srv.fulfillMsg(conn, &msg.Message{Identifier: [8]byte{2}}, 0, nil)
+	require.Len(t, sock.written, 0)
+
+	// Expect the disconnection hook to not be invoked again
+	// when the read loop detects the disconnection
+	srv.notifyClientDisconnected(conn)
+	require.Equal(t, int32(1), atomic.LoadInt32(&impl.disconnected))
+
+	// Expect further messages to be ignored
+	srv.handleMessage(conn, request)
+	require.Equal(t, int32(1), atomic.LoadInt32(&impl.requests))
+}
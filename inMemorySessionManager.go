@@ -0,0 +1,89 @@
+package webwire
+
+import (
+	"sync"
+	"time"
+)
+
+// InMemorySessionManager represents a session manager implementation
+// keeping sessions in memory. It's meant for tests and ephemeral single-node
+// deployments since all sessions are lost when the process exits.
+// It's safe for concurrent use
+type InMemorySessionManager struct {
+	lock     sync.Mutex
+	sessions map[string]*Session
+}
+
+// NewInMemorySessionManager constructs a new empty in-memory
+// session manager instance
+func NewInMemorySessionManager() *InMemorySessionManager {
+	return &InMemorySessionManager{
+		sessions: make(map[string]*Session),
+	}
+}
+
+// Len returns the number of currently stored sessions
+func (mng *InMemorySessionManager) Len() int {
+	mng.lock.Lock()
+	defer mng.lock.Unlock()
+	return len(mng.sessions)
+}
+
+// OnSessionCreated implements the session manager interface.
+// It stores a copy of the created session
+func (mng *InMemorySessionManager) OnSessionCreated(conn Connection) error {
+	sess := conn.Session()
+	mng.lock.Lock()
+	mng.sessions[sess.Key] = sess
+	mng.lock.Unlock()
+	return nil
+}
+
+// OnSessionLookup implements the session manager interface.
+// It returns a copy of the stored session and updates its last lookup field.
+// Expired sessions are deleted and reported as not found
+func (mng *InMemorySessionManager) OnSessionLookup(key string) (
+	SessionLookupResult,
+	error,
+) {
+	mng.lock.Lock()
+	defer mng.lock.Unlock()
+
+	sess, exists := mng.sessions[key]
+	if !exists {
+		return nil, nil
+	}
+
+	now := time.Now().UTC()
+	if sess.IsExpired(now) {
+		delete(mng.sessions, key)
+		return nil, nil
+	}
+
+	// Update last lookup
+	updated := sess.Clone()
+	updated.LastLookup = now
+	mng.sessions[key] = updated
+
+	return NewExpiringSessionLookupResult(
+		sess.Creation,
+		sess.LastLookup,
+		sess.Expiration,
+		SessionInfoToVarMap(sess.Clone().Info),
+	), nil
+}
+
+// OnSessionClosed implements the session manager interface.
+// It deletes the stored session
+func (mng *InMemorySessionManager) OnSessionClosed(sessionKey string) error {
+	mng.lock.Lock()
+	delete(mng.sessions, sessionKey)
+	mng.lock.Unlock()
+	return nil
+}
+
+// OnSessionUpdated implements the session manager interface.
+// It replaces the stored session with a copy of the updated one
+func (mng *InMemorySessionManager) OnSessionUpdated(conn Connection) error {
+	return mng.OnSessionCreated(conn)
+}
@@ -0,0 +1,99 @@
+package webwire
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSessionConnection creates a connection with the given session
+func newTestSessionConnection(sess Session) *connection {
+	conn := newConnection(&testSignalSocket{}, "", nil, nil)
+	conn.session = &sess
+	return conn
+}
+
+// TestInMemorySessionManager tests creating, looking up and closing sessions
+// using the in-memory session manager
+func TestInMemorySessionManager(t *testing.T) {
+	mng := NewInMemorySessionManager()
+	require.Equal(t, 0, mng.Len())
+
+	sess := NewSession(
+		GenericSessionInfoParser(map[string]interface{}{"field": "value"}),
+		func() string { return "testkey" },
+	)
+	require.NoError(t, mng.OnSessionCreated(newTestSessionConnection(sess)))
+	require.Equal(t, 1, mng.Len())
+
+	// Expect mutations of the lookup result to not affect the stored session
+	result, err := mng.OnSessionLookup("testkey")
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.True(t, sess.Creation.Equal(result.Creation()))
+	require.Equal(t, "value", result.Info()["field"])
+	result.Info()["field"] = "mutated"
+
+	result, err = mng.OnSessionLookup("testkey")
+	require.NoError(t, err)
+	require.Equal(t, "value", result.Info()["field"])
+	require.False(t, result.LastLookup().Before(sess.LastLookup))
+
+	// Expect missing sessions to be reported as not found
+	result, err = mng.OnSessionLookup("inexistent")
+	require.NoError(t, err)
+	require.Nil(t, result)
+
+	require.NoError(t, mng.OnSessionClosed("testkey"))
+	require.Equal(t, 0, mng.Len())
+	result, err = mng.OnSessionLookup("testkey")
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
+
+// TestInMemorySessionManagerExpiration tests whether the in-memory
+// session manager deletes expired sessions during lookup
+func TestInMemorySessionManagerExpiration(t *testing.T) {
+	mng := NewInMemorySessionManager()
+
+	sess := NewSession(nil, func() string { return "expired" })
+	sess.Expiration = time.Now().Add(-1 * time.Minute)
+	require.NoError(t, mng.OnSessionCreated(newTestSessionConnection(sess)))
+
+	result, err := mng.OnSessionLookup("expired")
+	require.NoError(t, err)
+	require.Nil(t, result)
+	require.Equal(t, 0, mng.Len())
+}
+
+// TestInMemorySessionManagerConcurrency tests concurrent use
+// of the in-memory session manager
+func TestInMemorySessionManagerConcurrency(t *testing.T) {
+	mng := NewInMemorySessionManager()
+	concurrency := 16
+
+	wg := sync.WaitGroup{}
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		key := fmt.Sprintf("key%d", i)
+		go func() {
+			defer wg.Done()
+			sess := NewSession(nil, func() string { return key })
+			conn := newTestSessionConnection(sess)
+			for j := 0; j < 10; j++ {
+				assert.NoError(t, mng.OnSessionCreated(conn))
+				_, err := mng.OnSessionLookup(key)
+				assert.NoError(t, err)
+				_ = mng.Len()
+				assert.NoError(t, mng.OnSessionClosed(key))
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 0, mng.Len())
+}
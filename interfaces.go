@@ -14,30 +14,129 @@ type Server interface {
 
 	// Run will launch the webwire server blocking the calling goroutine
 	// until the server is either gracefully shut down
-	// or crashes returning an error
+	// or crashes returning an error.
+	// Run returns nil after a clean shutdown through Shutdown
 	Run() error
 
 	// Addr returns the address the webwire server is listening on
 	Addr() net.Addr
 
+	// Scheme returns the URL scheme clients must use to connect
+	// to the built-in HTTP server: "wss" if it serves TLS, otherwise "ws".
+	// Headless servers always report "ws" since TLS is up to the hosting
+	// HTTP server
+	Scheme() string
+
 	// Shutdown appoints a server shutdown and blocks the calling goroutine
 	// until the server is gracefully stopped awaiting all currently processed
 	// signal and request handlers to return.
 	// During the shutdown incoming connections are rejected
 	// with 503 service unavailable.
 	// Incoming requests are rejected with an error while incoming signals
-	// are just ignored
+	// are just ignored.
+	// The shutdown is performed in the following order:
+	//  1. the server is marked as shutting down
+	//     and rejects all new connections, requests and signals from now on
+	//  2. all currently processed handlers are awaited
+	//  3. the HTTP server is closed
+	// If ServerOptions.ReportPendingRequests is enabled then the names
+	// of the requests still pending when the shutdown began are logged
+	// to help identify slow handlers blocking the graceful shutdown
 	Shutdown() error
 
+	// SetSessionManager replaces the session manager of the server.
+	// The new session manager is used by all subsequent session manager hook
+	// invocations while currently ongoing invocations are not affected
+	SetSessionManager(sessionManager SessionManager)
+
+	// Connections returns all currently active connections
+	// including anonymous connections without a session
+	Connections() []Connection
+
 	// ActiveSessionsNum returns the number of currently active sessions
 	ActiveSessionsNum() int
 
+	// AnonymousConnectionsNum returns the number of currently active
+	// connections that aren't registered for any session
+	AnonymousConnectionsNum() int
+
+	// AwaitConnections blocks until exactly num connections are active.
+	// Returns a DeadlineExceededErr or CanceledErr error if the given
+	// context is done before. Meant to deterministically synchronize tests
+	// with the connection establishment and loss
+	AwaitConnections(ctx context.Context, num int) error
+
+	// Stats returns an aggregated snapshot of the server state
+	// acquiring each involved lock only once
+	Stats() ServerStats
+
+	// WatchSessions returns a channel receiving the changes of the session
+	// registry such as sessions becoming active or inactive and connections
+	// being added to or removed from sessions. Each watcher buffers
+	// up to 256 events, the oldest events are dropped when the buffer
+	// is full to never block the server. The returned cancel function
+	// unsubscribes the watcher and must be called once the changes
+	// are no longer needed. The channel is closed when the watcher
+	// is cancelled or when the server is shut down
+	WatchSessions() (events <-chan SessionEvent, cancel func())
+
 	// SessionConnectionsNum implements the SessionRegistry interface
 	SessionConnectionsNum(sessionKey string) int
 
 	// SessionConnections implements the SessionRegistry interface
 	SessionConnections(sessionKey string) []Connection
 
+	// SetMaxSessionConnections overrides the maximum number of concurrent
+	// connections of the session identified by the given key while zero
+	// stands for unlimited. The new limit applies to all subsequent session
+	// restorations, existing connections exceeding a lowered limit
+	// remain connected. The override is dropped when the session is closed.
+	// Returns a SessNotFoundErr error if the session isn't currently active
+	SetMaxSessionConnections(sessionKey string, max uint) error
+
+	// SessionConnectionDetails returns the details of all connections
+	// of the session identified by the given key including the remote
+	// address, the connection time and the user agent of each connection.
+	// Returns nil if the session has no connections
+	SessionConnectionDetails(sessionKey string) []ConnectionDetails
+
+	// SignalSession sends a signal to all connections of the session
+	// identified by the given key. It returns the result of the signal
+	// attempt for each connection and a general error which is not nil
+	// if the signal couldn't be sent to at least one of the connections.
+	// If the session has no connections then (nil, nil) is returned.
+	SignalSession(sessionKey, name string, payload Payload) (
+		results []SignalResult,
+		err error,
+	)
+
+	// SignalWhere sends a signal to all connections of all currently active
+	// sessions matching the given predicate. It returns the result
+	// of the signal attempt for each connection and a general error
+	// which is not nil if the signal couldn't be sent to at least one
+	// of the connections. The predicate is invoked once per session
+	// with a copy of the session
+	SignalWhere(
+		predicate func(*Session) bool,
+		name string,
+		payload Payload,
+	) (
+		results []SignalResult,
+		err error,
+	)
+
+	// UpdateSessionInfo replaces the info of the session identified by the
+	// given key on all of its connections synchronizing the update to the
+	// remote clients. It returns the affected connections, a list of errors
+	// for each session info update attempt and a general error which is not
+	// nil if at least one of the updateErrors errors is not nil.
+	// If no session was updated then (nil, nil, nil) is returned.
+	UpdateSessionInfo(sessionKey string, info SessionInfo) (
+		affectedConnections []Connection,
+		updateErrors []error,
+		err error,
+	)
+
 	// CloseSession closes the session identified by the given key and returns
 	// the affected connections, a list of errors for each session session
 	// closure attempt and a general error which is not nil if at least
@@ -48,6 +147,15 @@ type Server interface {
 		closeErrors []error,
 		err error,
 	)
+
+	// CloseSessionWithReason is equivalent to CloseSession
+	// but additionally notifies the affected clients about the reason
+	// of the closure
+	CloseSessionWithReason(sessionKey, reason string) (
+		affectedConnections []Connection,
+		closeErrors []error,
+		err error,
+	)
 }
 
 // ConnectionOptions represents the connection upgrade options
@@ -89,18 +197,30 @@ type ServerImplementation interface {
 	// will block the initialization process, detaining the client from
 	// starting to listen for incoming messages.
 	// To prevent blocking the initialization process it is advised to move
-	// any time consuming work to a separate goroutine
-	OnClientConnected(client Connection)
+	// any time consuming work to a separate goroutine.
+	//
+	// Returning an error rejects the connection closing it with the error
+	// message as the close reason. This is useful when the authorization of
+	// a client depends on the connection object rather than the raw HTTP
+	// request available in BeforeUpgrade.
+	// Returning an error created by AbortConnection closes the connection
+	// with the given close code and reason instead.
+	// OnClientDisconnected is not invoked for rejected connections
+	OnClientConnected(client Connection) error
 
 	// OnClientDisconnected is invoked when a client closes the connection
-	// to the server.
+	// to the server or when writing a reply to the client failed.
+	// It's invoked only once per connection.
 	//
 	// This hook will be invoked by the goroutine serving
-	// the calling client before it's suspended
+	// the calling client before it's suspended,
+	// or by the goroutine of the handler that failed writing its reply
 	OnClientDisconnected(client Connection)
 
 	// OnSignal is invoked when the webwire server receives
 	// a signal from a client.
+	// The context is cancelled when the connection is closed
+	// while the signal is being handled.
 	//
 	// This hook will be invoked by the goroutine serving the calling client
 	// and will block any other interactions with this client while executing
@@ -112,12 +232,19 @@ type ServerImplementation interface {
 	// A webwire.ReqErr error can be returned to reply with an error code
 	// and an error message, this is useful when the clients user code needs
 	// to be able to understand the error and react accordingly.
+	// A payload returned alongside a webwire.ReqErr error is delivered
+	// to the client together with the error, unless the error already
+	// carries a payload, which is useful for structured error details.
 	// If a non-webwire error type is returned such as an error created by
 	// fmt.Errorf() then a special kind of error (internal server error)
 	// is returned to the client as a reply, in this case the error will be
 	// logged and the error message will not be sent to the client
 	// for security reasons as this might accidentally leak
 	// sensitive information to the client.
+	// The error message can be exposed to the client for debugging purposes
+	// by enabling the ExposeInternalErrors server option.
+	// Returning an error created by AbortConnection closes the connection
+	// with the given close code and reason without replying.
 	//
 	// This hook will be invoked by the goroutine serving the calling client
 	// and will block any other interactions with this client while executing
@@ -138,22 +265,82 @@ type Connection interface {
 	// client agent string, the remote address and the time of creation
 	Info() ClientInfo
 
+	// ClientIdentifier returns the human-readable identifier
+	// (such as the device name or the app version) the client set
+	// through its options or an empty string if it didn't set any
+	ClientIdentifier() string
+
+	// IsObserver returns true if the client declared this connection
+	// a read-only observer through its options. Requests of observers
+	// are rejected with an ObserverErr error, their signals are ignored
+	// and observers can't create sessions, they can only receive signals.
+	// Observers restoring a session count towards
+	// ServerOptions.MaxSessionConnections unless
+	// ServerOptions.UnlimitedObservers is enabled.
+	// The observer status is declared by the client and must not be trusted
+	IsObserver() bool
+
 	// Signal sends a named signal containing the given payload to the client
 	Signal(name string, payload Payload) error
 
+	// Request sends a named request containing the given payload
+	// to the client and blocks until either the client replies,
+	// the request times out or the context is done.
+	// The request times out after ServerOptions.ServerRequestTimeout
+	// unless the context defines an earlier deadline.
+	// Error replies of the client are returned as ReqErr errors.
+	// Returns a DisconnectedErr error if the connection is closed
+	// before the reply is received
+	Request(ctx context.Context, name string, payload Payload) (Payload, error)
+
 	// CreateSession creates a new session for this connection and
 	// automatically synchronizes the new session to the remote client.
 	// The synchronization happens asynchronously using a signal
 	// and doesn't block the calling goroutine.
-	// Returns an error if there's already another session active
+	// The session is persisted by the OnSessionCreated session manager hook
+	// before CreateSession returns unless AsyncSessionPersistence is enabled
+	// in which case the session is persisted in the background and lost
+	// if the server crashes before the hook succeeds.
+	// If there's already another session active then it's closed
+	// before the new session is created, as if CloseSession was called,
+	// and destroyed by the OnSessionClosed session manager hook
+	// if this connection was its last one. If ServerOptions.ReplaceSessions
+	// is disabled then an error is returned instead.
+	// Returns a SessionsDisabledErr error if sessions are disabled,
+	// returning it from the request handler fails the request
+	// with the same error on the client.
+	// Returns an ObserverErr error if this is an observer connection
 	CreateSession(attachment SessionInfo) error
 
+	// UpdateSessionInfo replaces the info of the currently active session
+	// of this connection and synchronizes the update to the remote client.
+	// The session manager is not notified about the update,
+	// use UpdateSession to update and persist the session
+	// on all of its connections instead.
+	// Returns an error if there's currently no active session
+	UpdateSessionInfo(info SessionInfo) error
+
+	// UpdateSession replaces the info of the currently active session
+	// on all connections of the session and bumps its last lookup time.
+	// The updated session is synchronized to the remote clients
+	// and persisted by the OnSessionUpdated session manager hook.
+	// Returns an error if there's currently no active session
+	// or if the update couldn't be synchronized to at least one
+	// of the connections
+	UpdateSession(info SessionInfo) error
+
 	// CloseSession disables the currently active session for this connection
 	// and synchronize the closure to the remote client.
 	// The session will be destroyed if this is it's last connection remaining.
 	// Does nothing if there's no active session
 	CloseSession() error
 
+	// CloseSessionWithReason is equivalent to CloseSession
+	// but additionally notifies the remote client about the reason
+	// of the closure such as "expired" or "closed by admin".
+	// An empty reason is equivalent to no reason
+	CloseSessionWithReason(reason string) error
+
 	// HasSession returns true if this connection currently has
 	// a session assigned, otherwise returns false
 	HasSession() bool
@@ -177,6 +364,23 @@ type Connection interface {
 	// in the form of an empty interface to be casted to either concrete type
 	SessionInfo(name string) interface{}
 
+	// SessionConnections returns the other connections sharing the session
+	// of this connection, such as the other devices of the same user,
+	// excluding this connection itself.
+	// Returns nil if there's no session assigned to this connection
+	SessionConnections() []Connection
+
+	// SetState stores the given value under the given key on this connection
+	// independently of the session. The state persists across all requests
+	// and signals of the connection until it's closed, which allows hooks
+	// to derive per-connection information (such as parsed authentication
+	// claims) only once. A nil value removes the key
+	SetState(key string, value interface{})
+
+	// State returns the value stored under the given key on this connection
+	// or nil if there's none
+	State(key string) interface{}
+
 	// Close marks this connection for shutdown.
 	// It defers closing the connection until all work on it is done
 	// and removes it from the session registry.
@@ -197,7 +401,21 @@ type SessionLookupResult interface {
 	Info() map[string]interface{}
 }
 
-// SessionManager defines the interface of a webwire server's session manager
+// ExpiringSessionLookupResult represents the result of a lookup
+// of a session that may expire. Lookup results are optionally implementing
+// this interface, sessions of lookup results not implementing it
+// are considered to not expire
+type ExpiringSessionLookupResult interface {
+	SessionLookupResult
+
+	// Expiration returns the retrieved expiration time
+	// which is the zero time if the session doesn't expire
+	Expiration() time.Time
+}
+
+// SessionManager defines the interface of a webwire server's session manager.
+// Panics in any of the hooks are recovered and treated as if the hook
+// returned an error
 type SessionManager interface {
 	// OnSessionCreated is invoked after the synchronization of the new session
 	// to the remote client.
@@ -239,6 +457,64 @@ type SessionManager interface {
 	// in the case of which it will block any other interactions with
 	// this client while executing
 	OnSessionClosed(sessionKey string) error
+
+	// OnSessionUpdated is invoked after the info of an active session
+	// was updated through Connection.UpdateSession and the update
+	// was synchronized to the remote clients.
+	// The actual updated session can be retrieved from the provided
+	// connection and must replace the persisted one.
+	// If an error is returned then it is logged
+	// and the update remains active in memory only.
+	//
+	// This hook will be invoked by the goroutine calling the
+	// client.UpdateSession connection method
+	OnSessionUpdated(client Connection) error
+}
+
+// SessionRestoreVerifier defines the interface of a webwire server's
+// session restoration verifier which is used to verify the proof
+// provided by the client alongside the session key before accepting
+// the restoration of a session
+type SessionRestoreVerifier interface {
+	// OnSessionRestoreVerify is invoked after the session associated with the
+	// given key was found by the session manager but before the session
+	// is restored. The proof is nil if the client didn't provide any.
+	// If nil is returned then the restoration is accepted.
+	//
+	// A webwire.ReqErr error can be returned to reject the restoration
+	// replying with an error code and an error message.
+	// Any other error type will be logged and the restoration will fail
+	// with an internal server error.
+	//
+	// This hook will be invoked by the goroutine serving the associated client
+	// and will block any other interactions with this client while executing
+	OnSessionRestoreVerify(
+		client Connection,
+		key string,
+		session SessionLookupResult,
+		proof []byte,
+	) error
+}
+
+// MetricsRecorder defines the interface of a webwire server's
+// metrics recorder which is used to observe the performance of the server
+type MetricsRecorder interface {
+	// RecordSessionLookup is invoked after each invocation
+	// of the OnSessionLookup session manager hook
+	// with the time it took the hook to return.
+	//
+	// This hook will be invoked by the goroutine serving the associated client
+	// and will block any other interactions with this client while executing
+	RecordSessionLookup(duration time.Duration)
+
+	// RecordUpgrade is invoked after each successful upgrade
+	// of an incoming HTTP connection to a WebSocket connection
+	// with the time the upgrade took and the remote address of the client.
+	// It's invoked before OnClientConnected.
+	//
+	// This hook will be invoked by the goroutine serving the associated client
+	// and will block any other interactions with this client while executing
+	RecordUpgrade(remoteAddr net.Addr, duration time.Duration)
 }
 
 // SessionKeyGenerator defines the interface of a webwire server's
@@ -249,7 +525,18 @@ type SessionKeyGenerator interface {
 	// Generate is invoked when the webwire server creates a new session
 	// and requires a new session key to be generated.
 	// This hook must not be used except the user knows exactly what he/she does
-	// as it would compromise security if implemented improperly
+	// as it would compromise security if implemented improperly.
+	// The returned key must not be empty and must consist of URL-safe base64
+	// characters (A-Z, a-z, 0-9, '-', '_' and '=') only,
+	// the session creation panics otherwise.
+	// Session keys are bearer credentials, thus keys must be generated
+	// by a cryptographically secure random source with at least 128 bits
+	// of entropy (the default generator uses 384 bits) to be unguessable.
+	// Keys colliding with a currently active session are discarded
+	// and regenerated, CreateSession fails with a SessionKeyCollisionErr
+	// error if the generator repeatedly returns colliding keys.
+	// Collisions with inactive sessions kept by the session manager
+	// aren't detected
 	Generate() string
 }
 
@@ -285,6 +572,20 @@ type SessionInfo interface {
 // from the data given
 type SessionInfoParser func(map[string]interface{}) SessionInfo
 
+// RequestHandler represents the type of a request handler function
+// such as ServerImplementation.OnRequest
+type RequestHandler func(
+	ctx context.Context,
+	client Connection,
+	message Message,
+) (response Payload, err error)
+
+// RequestMiddleware represents the type of a request middleware function
+// wrapping the next request handler in the chain for cross-cutting concerns
+// such as authorization, logging or rate limiting. A middleware can
+// short-circuit the request by returning without calling the next handler
+type RequestMiddleware func(next RequestHandler) RequestHandler
+
 // Payload represents a WebWire message payload
 type Payload interface {
 	// Encoding returns the payload encoding type
@@ -293,8 +594,25 @@ type Payload interface {
 	// Data returns the raw payload data
 	Data() []byte
 
+	// Len returns the length of the payload data in bytes
+	Len() int
+
+	// IsEmpty returns true if the payload carries no data.
+	// Nil and empty payload data are treated equally
+	IsEmpty() bool
+
 	// Utf8 returns a UTF8 representation of the payload data
 	Utf8() (string, error)
+
+	// DecodeInto decodes the payload data into the given value
+	// using the codec agreed on by the server and the client.
+	// Returns a NoCodecErr error if no codec is configured
+	DecodeInto(value interface{}) error
+
+	// DecodeJSON decodes the JSON encoded payload data
+	// into the given value. UTF16 encoded payloads are transcoded to UTF8
+	// before decoding. Fails if the payload is binary encoded
+	DecodeJSON(value interface{}) error
 }
 
 // Message represents a WebWire protocol message
@@ -311,3 +629,103 @@ type Message interface {
 	// Payload returns the message payload
 	Payload() Payload
 }
+
+// MessageHooks defines the interface of a webwire server's message hooks
+// which are invoked around the dispatch of both requests and signals.
+// They're a lightweight alternative to middleware for observability purposes
+// such as tracing and metrics
+type MessageHooks interface {
+	// BeforeMessage is invoked before a request or signal is dispatched
+	// to the server implementation
+	BeforeMessage(ctx context.Context, message Message)
+
+	// AfterMessage is invoked after a request or signal was handled
+	// by the server implementation. err is the error returned by the request
+	// handler and is always nil for signals
+	AfterMessage(ctx context.Context, message Message, err error)
+
+	// OnSignalReceived is invoked for every inbound signal before it's
+	// dispatched to the server implementation, even if it's ignored due to
+	// the server shutting down, which allows auditing all signals
+	// alongside the identity of the client. It isn't accounted for
+	// as a pending operation during the server shutdown
+	OnSignalReceived(client Connection, message Message)
+}
+
+// Executor defines the interface of a webwire server's message dispatch
+// executor which executes the handling of incoming signals, requests
+// and session messages. Replies to requests sent by the server
+// are resolved by the goroutine serving the client instead.
+// By default a new goroutine is spawned for each incoming message,
+// a custom executor such as a fixed size worker pool can be used to bound
+// the concurrency globally.
+// Beware that the handling of a message may block on the concurrency limit
+// of its connection, thus a small worker pool may get exhausted by
+// a single connection
+type Executor interface {
+	// Submit schedules the given task for execution.
+	//
+	// This method will be invoked by the goroutine serving the client
+	// the message originates from and will block any further reading
+	// from this client while executing
+	Submit(task func())
+}
+
+// RequestFilter defines the interface of a webwire server's request filter
+// which is used for admission control such as rate limiting or logging
+type RequestFilter interface {
+	// FilterRequest is invoked for every incoming request right after
+	// it was parsed and before it's dispatched to the server implementation,
+	// regardless of the request name.
+	// Returning an error rejects the request without invoking
+	// the OnRequest hook, the error is then returned to the client
+	// the same way errors returned by OnRequest are.
+	//
+	// This hook will be invoked by the goroutine serving the calling client
+	// and will block any other interactions with this client while executing
+	FilterRequest(client Connection, message Message) error
+}
+
+// ParseErrorHandler defines the interface of a webwire server's
+// message parse error handler which is used to debug misbehaving clients
+type ParseErrorHandler interface {
+	// OnMessageParseError is invoked when an incoming message couldn't be
+	// parsed with the raw message and the parser error.
+	// Messages of an undeterminable type are dropped while messages
+	// violating the protocol are answered with a ProtocolErr error reply.
+	//
+	// This hook will be invoked by the goroutine handling the message
+	// before the protocol error reply is sent
+	OnMessageParseError(client Connection, raw []byte, err error)
+}
+
+// SessionCodec defines the interface of a codec encoding the session objects
+// synchronized to the clients during session creation and restoration.
+// The server and its clients must use the same codec
+type SessionCodec interface {
+	// Encoding returns the payload encoding of encoded session objects
+	Encoding() PayloadEncoding
+
+	// Encode encodes the given session object
+	Encode(session *JSONEncodedSession) ([]byte, error)
+
+	// Decode decodes a session object encoded by Encode
+	Decode(data []byte) (*JSONEncodedSession, error)
+}
+
+// Codec defines the interface of a payload codec used to exchange
+// structured values such as Protobuf or MessagePack messages
+// through payloads, see NewCodecPayload and Payload.DecodeInto.
+// The server and its clients must use the same codec
+// which is verified during the connection establishment
+type Codec interface {
+	// Name returns the unique name of the codec
+	// used to verify the server and the client agree on the codec
+	Name() string
+
+	// Marshal encodes the given value
+	Marshal(value interface{}) ([]byte, error)
+
+	// Unmarshal decodes the given data into the given value
+	Unmarshal(data []byte, value interface{}) error
+}
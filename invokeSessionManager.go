@@ -0,0 +1,163 @@
+package webwire
+
+import (
+	"fmt"
+	"time"
+)
+
+// sessionManagerPanicErr converts a recovered session manager hook panic
+// into a regular error
+func sessionManagerPanicErr(hook string, recovered interface{}) error {
+	return fmt.Errorf("%s hook panicked: %v", hook, recovered)
+}
+
+// onSessionCreated invokes the OnSessionCreated hook of the session manager
+// recovering from and converting panics to errors
+func (srv *server) onSessionCreated(con Connection) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = sessionManagerPanicErr("OnSessionCreated", recovered)
+		}
+	}()
+	return srv.getSessionManager().OnSessionCreated(con)
+}
+
+// sessionSnapshot represents a connection frozen to the session
+// it had at the time the snapshot was taken.
+// It's passed to the asynchronously invoked OnSessionCreated hook
+// which would otherwise observe the session of the connection
+// at the time of the invocation rather than the created one
+type sessionSnapshot struct {
+	*connection
+	session *Session
+}
+
+// Session implements the Connection interface
+func (snap *sessionSnapshot) Session() *Session {
+	return snap.session.Clone()
+}
+
+// SessionKey implements the Connection interface
+func (snap *sessionSnapshot) SessionKey() string {
+	return snap.session.Key
+}
+
+// SessionCreation implements the Connection interface
+func (snap *sessionSnapshot) SessionCreation() time.Time {
+	return snap.session.Creation
+}
+
+// SessionInfo implements the Connection interface
+func (snap *sessionSnapshot) SessionInfo(name string) interface{} {
+	if snap.session.Info == nil {
+		return nil
+	}
+	return snap.session.Info.Value(name)
+}
+
+// persistSessionAsync invokes the OnSessionCreated hook of the session manager
+// in a separate goroutine logging eventual errors.
+// The hook is passed a snapshot of the given created session
+// and is skipped if the connection doesn't hold the created session anymore
+// by the time the goroutine is executed.
+// The invocation is registered as an ongoing operation to be awaited
+// during the server shutdown. The hook is invoked synchronously
+// if the server is already shutting down
+func (srv *server) persistSessionAsync(con *connection, created *Session) {
+	snapshot := &sessionSnapshot{connection: con, session: created}
+
+	srv.opsLock.Lock()
+	if srv.shutdown {
+		srv.opsLock.Unlock()
+		if err := srv.onSessionCreated(snapshot); err != nil {
+			srv.logSessionCreationError(con, created.Key, err)
+		}
+		return
+	}
+	srv.currentOps++
+	srv.opsLock.Unlock()
+
+	go func() {
+		// Don't persist sessions that were closed or replaced meanwhile
+		if con.SessionKey() == created.Key {
+			if err := srv.onSessionCreated(snapshot); err != nil {
+				srv.logSessionCreationError(con, created.Key, err)
+			}
+		}
+
+		// Mark the operation as done and shutdown the server
+		// if scheduled and no ops are left
+		srv.opsLock.Lock()
+		srv.currentOps--
+		if srv.shutdown && srv.currentOps < 1 {
+			close(srv.shutdownRdy)
+		}
+		srv.opsLock.Unlock()
+	}()
+}
+
+// logSessionCreationError logs the failure
+// of the OnSessionCreated session manager hook
+func (srv *server) logSessionCreationError(
+	con *connection,
+	sessionKey string,
+	err error,
+) {
+	errCtx := newErrorContext(ErrOpSessionCreation, con)
+	errCtx.SessionKey = sessionKey
+	srv.logError(errCtx, err, "OnSessionCreated hook failed: %s", err)
+}
+
+// onSessionClosed invokes the OnSessionClosed hook of the session manager
+// recovering from and converting panics to errors
+func (srv *server) onSessionClosed(sessionKey string) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = sessionManagerPanicErr("OnSessionClosed", recovered)
+		}
+	}()
+	return srv.getSessionManager().OnSessionClosed(sessionKey)
+}
+
+// onSessionUpdated invokes the OnSessionUpdated hook of the session manager
+// recovering from and converting panics to errors
+func (srv *server) onSessionUpdated(con *connection) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = sessionManagerPanicErr("OnSessionUpdated", recovered)
+		}
+	}()
+	return srv.getSessionManager().OnSessionUpdated(con)
+}
+
+// onSessionLookup invokes the OnSessionLookup hook of the session manager
+// recovering from and converting panics to errors
+func (srv *server) onSessionLookup(key string) (
+	result SessionLookupResult,
+	err error,
+) {
+	start := time.Now()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result = nil
+			err = sessionManagerPanicErr("OnSessionLookup", recovered)
+		}
+		srv.recordSessionLookup(time.Since(start))
+	}()
+	return srv.getSessionManager().OnSessionLookup(key)
+}
+
+// recordSessionLookup records the duration of a session lookup
+// and logs a warning if the lookup was slow
+func (srv *server) recordSessionLookup(duration time.Duration) {
+	if duration > srv.options.SlowSessionLookupThreshold {
+		srv.warnLog.Printf(
+			"Slow session lookup: OnSessionLookup took %s (threshold: %s)",
+			duration,
+			srv.options.SlowSessionLookupThreshold,
+		)
+	}
+	if srv.options.Metrics != nil {
+		srv.options.Metrics.RecordSessionLookup(duration)
+	}
+}
@@ -12,10 +12,23 @@ func NewMessageWrapper(message *msg.Message) *MessageWrapper {
 	}
 }
 
+// NewCodecMessageWrapper creates a new Message interface compliant
+// message object with a payload decodable by the given codec
+func NewCodecMessageWrapper(
+	message *msg.Message,
+	codec Codec,
+) *MessageWrapper {
+	return &MessageWrapper{
+		actual: message,
+		codec:  codec,
+	}
+}
+
 // MessageWrapper wraps a msg.Message pointer
 // to make it implement the Message interface
 type MessageWrapper struct {
 	actual *msg.Message
+	codec  Codec
 }
 
 // MessageType implements the Message interface
@@ -40,5 +53,6 @@ func (wrp *MessageWrapper) Payload() Payload {
 			Encoding: wrp.actual.Payload.Encoding,
 			Data:     wrp.actual.Payload.Data,
 		},
+		Codec: wrp.codec,
 	}
 }
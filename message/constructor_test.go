@@ -372,3 +372,61 @@ func TestMsgNewSigMsgUtf16OddNameLen(t *testing.T) {
 
 	require.Equal(t, expected, actual)
 }
+
+// TestMsgNewInternalErrorReplyMsg tests NewInternalErrorReplyMessage
+func TestMsgNewInternalErrorReplyMsg(t *testing.T) {
+	id := genRndMsgIdentifier()
+	errMessage := "sample internal error message"
+
+	// Compose encoded message
+	// Add type flag
+	expected := []byte{MsgInternalError}
+	// Add identifier
+	expected = append(expected, id[:]...)
+	// Add error message
+	expected = append(expected, []byte(errMessage)...)
+
+	actual := NewInternalErrorReplyMessage(id, errMessage)
+
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgNewHandshakeMsg tests NewHandshakeMessage
+func TestMsgNewHandshakeMsg(t *testing.T) {
+	protocolVersion := "1.5"
+
+	// Compose encoded message
+	// Add type flag
+	expected := []byte{MsgHandshake}
+	// Add sessions enabled flag
+	expected = append(expected, 1)
+	// Add protocol version
+	expected = append(expected, []byte(protocolVersion)...)
+
+	actual := NewHandshakeMessage(protocolVersion, true)
+
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgNewVerifiedRestoreSessionMsg tests NewVerifiedRestoreSessionMessage
+func TestMsgNewVerifiedRestoreSessionMsg(t *testing.T) {
+	id := genRndMsgIdentifier()
+	sessionKey := "somesamplesessionkey"
+	proof := []byte("somesampleproof")
+
+	// Compose encoded message
+	// Add type flag
+	expected := []byte{MsgRestoreSessionVerified}
+	// Add identifier
+	expected = append(expected, id[:]...)
+	// Add session key length flag
+	expected = append(expected, byte(len(sessionKey)))
+	// Add session key
+	expected = append(expected, []byte(sessionKey)...)
+	// Add proof
+	expected = append(expected, proof...)
+
+	actual := NewVerifiedRestoreSessionMessage(id, sessionKey, proof)
+
+	require.Equal(t, expected, actual)
+}
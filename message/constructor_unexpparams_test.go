@@ -1,6 +1,7 @@
 package message
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -255,3 +256,13 @@ func TestMsgNewErrorReplyMessageCodeCharsetAboveAscii126(t *testing.T) {
 			" containing a character above the 126th ASCII 7bit char",
 	)
 }
+
+// TestMsgIsValidErrorCode tests error code validation
+func TestMsgIsValidErrorCode(t *testing.T) {
+	require.True(t, IsValidErrorCode("SOME_ERROR"))
+	require.True(t, IsValidErrorCode(strings.Repeat("a", 255)))
+	require.False(t, IsValidErrorCode(""))
+	require.False(t, IsValidErrorCode(strings.Repeat("a", 256)))
+	require.False(t, IsValidErrorCode("invalid\ncode"))
+	require.False(t, IsValidErrorCode("invalid\x7fcode"))
+}
@@ -0,0 +1,56 @@
+package message
+
+import pld "github.com/qbeon/webwire-go/payload"
+
+// UnknownTypeErr is returned by Parse
+// if the type of the message couldn't be determined
+type UnknownTypeErr struct{}
+
+func (err UnknownTypeErr) Error() string {
+	return "Couldn't determine the message type"
+}
+
+// Parse parses the given binary message.
+// Returns an UnknownTypeErr error and a nil message if the type
+// of the message couldn't be determined. If the message is malformed
+// the partially parsed message is returned along with the parser error
+// to allow replying to the identified request
+func Parse(message []byte) (*Message, error) {
+	parsed := &Message{}
+	typeDetermined, err := parsed.Parse(message)
+	if !typeDetermined {
+		return nil, UnknownTypeErr{}
+	}
+	return parsed, err
+}
+
+// NewRequest composes a new named request message carrying the given payload
+// and returns its binary representation
+func NewRequest(identifier [8]byte, name string, payload pld.Payload) []byte {
+	return NewRequestMessage(identifier, name, payload.Encoding, payload.Data)
+}
+
+// readIdentifier reads the identifier from the header of the given message
+func readIdentifier(message []byte) (identifier [8]byte) {
+	copy(identifier[:], message[IdentifierOffset:IdentifierOffset+8])
+	return identifier
+}
+
+// writeIdentifier writes the given identifier to the header of the message
+func writeIdentifier(message []byte, identifier [8]byte) {
+	copy(message[IdentifierOffset:IdentifierOffset+8], identifier[:])
+}
+
+// newNotificationMessage composes a new session notification message
+// of the given type carrying the given payload
+func newNotificationMessage(msgType byte, payload []byte) (msg []byte) {
+	msg = make([]byte, NotificationPayloadOffset+len(payload))
+
+	// Write message type flag
+	msg[0] = msgType
+
+	// Write payload
+	copy(msg[NotificationPayloadOffset:], payload)
+
+	return msg
+}
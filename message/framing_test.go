@@ -0,0 +1,47 @@
+package message
+
+import (
+	"testing"
+
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseRequest tests parsing a request composed by NewRequest
+func TestParseRequest(t *testing.T) {
+	identifier := genRndMsgIdentifier()
+	payload := pld.Payload{Encoding: pld.Utf8, Data: []byte("sample data")}
+
+	parsed, err := Parse(NewRequest(identifier, "name", payload))
+	require.NoError(t, err)
+	require.Equal(t, &Message{
+		Type:       MsgRequestUtf8,
+		Identifier: identifier,
+		Name:       "name",
+		Payload:    payload,
+	}, parsed)
+}
+
+// TestParseUnknownType tests parsing messages of undefined type
+func TestParseUnknownType(t *testing.T) {
+	for _, message := range [][]byte{nil, {200}} {
+		parsed, err := Parse(message)
+		require.IsType(t, UnknownTypeErr{}, err)
+		require.Nil(t, parsed)
+	}
+}
+
+// TestParseMalformed tests whether parsing a malformed request
+// returns the request identifier along with the parser error
+func TestParseMalformed(t *testing.T) {
+	identifier := genRndMsgIdentifier()
+	message := NewRequest(identifier, "A", pld.Payload{})
+
+	// Corrupt the name length flag
+	message[NameLenOffset] = 3
+
+	parsed, err := Parse(message)
+	require.Error(t, err)
+	require.NotNil(t, parsed)
+	require.Equal(t, identifier, parsed.Identifier)
+}
@@ -9,7 +9,7 @@ const (
 	//  1. message type (1 byte)
 	//  2. name length flag (1 byte)
 	//  3. name (n bytes, optional if name length flag is 0)
-	//  4. payload (n bytes, at least 1 byte)
+	//  4. payload (n bytes, at least 1 byte or optional if name len > 0)
 	MsgMinLenSignal = int(3)
 
 	// MsgMinLenSignalUtf16 represents the minimum length
@@ -19,7 +19,7 @@ const (
 	//  2. name length flag (1 byte)
 	//  3. name (n bytes, optional if name length flag is 0)
 	//  4. header padding (1 byte, required if name length flag is odd)
-	//  5. payload (n bytes, at least 2 bytes)
+	//  5. payload (n bytes, at least 2 bytes or optional if name len > 0)
 	MsgMinLenSignalUtf16 = int(4)
 
 	// MsgMinLenRequest represents the minimum length
@@ -73,6 +73,24 @@ const (
 	//  5. error message (n bytes, UTF8 encoded, optional)
 	MsgMinLenErrorReply = int(11)
 
+	// MsgMinLenErrorReplyPayload represents the minimum length
+	// of error reply messages carrying an additional payload.
+	// Error reply with payload message structure:
+	//  1. message type (1 byte)
+	//  2. message id (8 bytes)
+	//  3. payload encoding flag (1 byte, 0 binary, 1 UTF8, 2 UTF16)
+	//  4. error code length flag (1 byte, cannot be 0)
+	//  5. error code (
+	//    from 1 to 255 bytes,
+	//    length must correspond to the length flag
+	//  )
+	//  6. error message length (4 bytes, big endian)
+	//  7. error message (n bytes, UTF8 encoded, optional)
+	//  8. header padding (1 byte, required if the payload is UTF16 encoded
+	//    and the header length is odd)
+	//  9. payload (n bytes, optional)
+	MsgMinLenErrorReplyPayload = int(16)
+
 	// MsgMinLenRestoreSession represents the minimum length
 	// of session restoration request messages.
 	// Session restoration request message structure:
@@ -81,6 +99,19 @@ const (
 	//  3. session key (n bytes, 7-bit ASCII encoded, at least 1 byte)
 	MsgMinLenRestoreSession = int(10)
 
+	// MsgMinLenRestoreSessionVerified represents the minimum length
+	// of verified session restoration request messages.
+	// Verified session restoration request message structure:
+	//  1. message type (1 byte)
+	//  2. message id (8 bytes)
+	//  3. session key length flag (1 byte, cannot be 0)
+	//  4. session key (
+	//    from 1 to 255 bytes, 7-bit ASCII encoded,
+	//    length must correspond to the length flag
+	//  )
+	//  5. proof (n bytes, optional)
+	MsgMinLenRestoreSessionVerified = int(11)
+
 	// MsgMinLenCloseSession represents the minimum length
 	// of session destruction request messages.
 	// Session destruction request message structure:
@@ -99,7 +130,108 @@ const (
 	// of session creation notification messages.
 	// Session destruction notification message structure:
 	//  1. message type (1 byte)
+	//  2. reason (n bytes, UTF8 encoded, optional)
 	MsgMinLenSessionClosed = int(1)
+
+	// MsgMinLenHandshake represents the minimum length
+	// of connection handshake messages.
+	// Connection handshake message structure:
+	//  1. message type (1 byte)
+	//  2. sessions enabled flag (1 byte, 0 if disabled, 1 if enabled)
+	//  3. protocol version (n bytes, 7-bit ASCII encoded, at least 1 byte)
+	MsgMinLenHandshake = int(3)
+
+	// MsgMinLenSessionInfoUpdated represents the minimum length
+	// of session info update notification messages.
+	// Session info update notification message structure:
+	//  1. message type (1 byte)
+	//  2. JSON encoded session info (n bytes, at least 1 byte)
+	MsgMinLenSessionInfoUpdated = int(2)
+
+	// MsgMinLenSessionUpdated represents the minimum length
+	// of session update notification messages.
+	// Session update notification message structure:
+	//  1. message type (1 byte)
+	//  2. encoded session (n bytes, at least 1 byte)
+	MsgMinLenSessionUpdated = int(2)
+)
+
+const (
+	// IdentifierOffset represents the offset of the 8 byte identifier
+	// in the header of request, reply and session restoration messages
+	IdentifierOffset = 1
+
+	// IdentifiedPayloadOffset represents the offset of the payload
+	// of messages with a header consisting of just the message type
+	// and the identifier such as session restoration requests,
+	// nameless requests and internal error replies
+	IdentifiedPayloadOffset = IdentifierOffset + 8
+
+	// NameLenOffset represents the offset of the name length flag
+	// in the header of request messages
+	NameLenOffset = IdentifierOffset + 8
+
+	// NameOffset represents the offset of the name
+	// in the header of request messages
+	NameOffset = NameLenOffset + 1
+
+	// ReplyPayloadOffset represents the offset of the payload
+	// of binary/UTF8 encoded reply messages.
+	// UTF16 encoded reply payloads are preceded by a header padding byte
+	ReplyPayloadOffset = IdentifiedPayloadOffset
+
+	// SignalNameLenOffset represents the offset of the name length flag
+	// in the header of signal messages
+	SignalNameLenOffset = 1
+
+	// SignalNameOffset represents the offset of the name
+	// in the header of signal messages
+	SignalNameOffset = SignalNameLenOffset + 1
+
+	// ErrorCodeLenOffset represents the offset of the error code length flag
+	// in the header of error reply messages
+	ErrorCodeLenOffset = IdentifierOffset + 8
+
+	// ErrorCodeOffset represents the offset of the error code
+	// in the header of error reply messages
+	ErrorCodeOffset = ErrorCodeLenOffset + 1
+
+	// ErrorPayloadEncodingOffset represents the offset of the payload
+	// encoding flag in the header of error reply messages carrying a payload
+	ErrorPayloadEncodingOffset = IdentifierOffset + 8
+
+	// ErrorPayloadCodeLenOffset represents the offset of the error code length
+	// flag in the header of error reply messages carrying a payload
+	ErrorPayloadCodeLenOffset = ErrorPayloadEncodingOffset + 1
+
+	// ErrorPayloadCodeOffset represents the offset of the error code
+	// in the header of error reply messages carrying a payload
+	ErrorPayloadCodeOffset = ErrorPayloadCodeLenOffset + 1
+
+	// ErrorMessageLenSize represents the size of the big endian encoded
+	// error message length following the error code
+	// in the header of error reply messages carrying a payload
+	ErrorMessageLenSize = 4
+
+	// SessionKeyLenOffset represents the offset of the session key length
+	// flag in the header of verified session restoration request messages
+	SessionKeyLenOffset = IdentifierOffset + 8
+
+	// SessionKeyOffset represents the offset of the session key
+	// in the header of verified session restoration request messages
+	SessionKeyOffset = SessionKeyLenOffset + 1
+
+	// HandshakeFlagsOffset represents the offset of the flags
+	// in connection handshake messages
+	HandshakeFlagsOffset = 1
+
+	// HandshakeVersionOffset represents the offset of the protocol version
+	// in connection handshake messages
+	HandshakeVersionOffset = HandshakeFlagsOffset + 1
+
+	// NotificationPayloadOffset represents the offset of the payload
+	// of session notification messages
+	NotificationPayloadOffset = 1
 )
 
 const (
@@ -114,7 +246,9 @@ const (
 	MsgReplyShutdown = byte(1)
 
 	// MsgInternalError is sent by the server if an unexpected internal error
-	// arose during the processing of a request
+	// arose during the processing of a request.
+	// It may optionally carry a UTF8 encoded error message
+	// if the server is configured to expose internal errors
 	MsgInternalError = byte(2)
 
 	// MsgSessionNotFound is sent by the server in response to an unfulfilled
@@ -135,6 +269,28 @@ const (
 	// message violating the protocol
 	MsgReplyProtocolError = byte(6)
 
+	// MsgReplyUnauthenticated is sent by the server in response to a request
+	// from a connection without a session if the server requires a session
+	// for requests
+	MsgReplyUnauthenticated = byte(7)
+
+	// MsgErrorReplyPayload is sent by the server
+	// and represents an error-reply to a previously sent request
+	// carrying an additional payload such as structured error details
+	MsgErrorReplyPayload = byte(8)
+
+	// MsgReplyPayloadTooLarge is sent by the server in response to a request
+	// carrying a payload exceeding the maximum request payload size
+	MsgReplyPayloadTooLarge = byte(9)
+
+	// MsgReplyRateLimited is sent by the server in response to a request
+	// rejected due to the client exceeding a rate limit
+	MsgReplyRateLimited = byte(10)
+
+	// MsgReplyObserver is sent by the server in response to a request
+	// from an observer connection which isn't allowed to send requests
+	MsgReplyObserver = byte(11)
+
 	// MsgSessionCreated is sent by the server
 	// to notify the client about the session creation
 	MsgSessionCreated = byte(21)
@@ -143,6 +299,21 @@ const (
 	// to notify the client about the session destruction
 	MsgSessionClosed = byte(22)
 
+	// MsgHandshake is sent by the server right after the connection
+	// is established to notify the client about the protocol version
+	// and whether sessions are enabled.
+	// The message name contains the protocol version
+	// while the payload contains the sessions enabled flag
+	MsgHandshake = byte(23)
+
+	// MsgSessionInfoUpdated is sent by the server
+	// to notify the client about the update of the session info
+	MsgSessionInfoUpdated = byte(24)
+
+	// MsgSessionUpdated is sent by the server to notify the client
+	// about the update of the session carrying the updated session
+	MsgSessionUpdated = byte(25)
+
 	// CLIENT
 
 	// MsgCloseSession is sent by the client
@@ -154,6 +325,21 @@ const (
 	// to request session restoration
 	MsgRestoreSession = byte(32)
 
+	// MsgRestoreSessionVerified is sent by the client
+	// to request session restoration providing an additional proof
+	// to be verified by the server.
+	// The message name contains the session key
+	// while the payload contains the proof
+	MsgRestoreSessionVerified = byte(33)
+
+	// MsgClientErrorReply is sent by the client
+	// and represents an error-reply to a previously sent server request
+	MsgClientErrorReply = byte(34)
+
+	// MsgClientInternalError is sent by the client if an unexpected
+	// internal error arose during the processing of a server request
+	MsgClientInternalError = byte(35)
+
 	// SIGNAL
 	// Signals are sent by both the client and the server
 	// and represents a one-way signal message that doesn't require a reply
@@ -180,6 +366,21 @@ const (
 	// MsgRequestUtf16 represents a request with a UTF16 encoded payload
 	MsgRequestUtf16 = byte(129)
 
+	// SERVER REQUEST
+	// Server requests are sent by the server
+	// and represent a roundtrip to the client requiring a reply
+
+	// MsgServerRequestBinary represents a server request with binary payload
+	MsgServerRequestBinary = byte(130)
+
+	// MsgServerRequestUtf8 represents a server request
+	// with a UTF8 encoded payload
+	MsgServerRequestUtf8 = byte(131)
+
+	// MsgServerRequestUtf16 represents a server request
+	// with a UTF16 encoded payload
+	MsgServerRequestUtf16 = byte(132)
+
 	// REPLY
 	// Replies are sent by the server
 	// and represent a reply to a previously sent request
@@ -192,6 +393,21 @@ const (
 
 	// MsgReplyUtf16 represents a reply with a UTF16 encoded payload
 	MsgReplyUtf16 = byte(193)
+
+	// CLIENT REPLY
+	// Client replies are sent by the client
+	// and represent a reply to a previously sent server request
+
+	// MsgClientReplyBinary represents a client reply with a binary payload
+	MsgClientReplyBinary = byte(194)
+
+	// MsgClientReplyUtf8 represents a client reply
+	// with a UTF8 encoded payload
+	MsgClientReplyUtf8 = byte(195)
+
+	// MsgClientReplyUtf16 represents a client reply
+	// with a UTF16 encoded payload
+	MsgClientReplyUtf16 = byte(196)
 )
 
 // Message represents a WebWire protocol message
@@ -200,6 +416,10 @@ type Message struct {
 	Identifier [8]byte
 	Name       string
 	Payload    pld.Payload
+
+	// ErrorMessage is only set for error replies carrying a payload,
+	// other error replies carry the error message in the payload
+	ErrorMessage string
 }
 
 // RequiresReply returns true if a message of this type requires a reply,
@@ -210,11 +430,19 @@ func (msg *Message) RequiresReply() bool {
 		fallthrough
 	case MsgRestoreSession:
 		fallthrough
+	case MsgRestoreSessionVerified:
+		fallthrough
 	case MsgRequestBinary:
 		fallthrough
 	case MsgRequestUtf8:
 		fallthrough
 	case MsgRequestUtf16:
+		fallthrough
+	case MsgServerRequestBinary:
+		fallthrough
+	case MsgServerRequestUtf8:
+		fallthrough
+	case MsgServerRequestUtf16:
 		return true
 	}
 	return false
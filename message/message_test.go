@@ -40,6 +40,24 @@ func TestRequiresReplyRestoreSession(t *testing.T) {
 	)
 }
 
+// TestRequiresReplyRestoreSessionVerified tests the RequiresReply method
+// with a verified session restoration request message
+func TestRequiresReplyRestoreSessionVerified(t *testing.T) {
+	msg := &Message{}
+	_, err := msg.Parse(NewVerifiedRestoreSessionMessage(
+		genRndMsgIdentifier(),
+		"somesamplesessionkey",
+		[]byte("somesampleproof"),
+	))
+	require.NoError(t, err)
+
+	require.True(t,
+		msg.RequiresReply(),
+		"Expected a verified session restoration request message "+
+			"to require a reply",
+	)
+}
+
 // TestRequiresReplyRequestBinary tests the RequiresReply method
 // with a binary request message
 func TestRequiresReplyRequestBinary(t *testing.T) {
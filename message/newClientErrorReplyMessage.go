@@ -0,0 +1,17 @@
+package message
+
+// NewClientErrorReplyMessage composes a new error reply message
+// to a server request and returns its binary representation.
+// Client error reply messages share the structure of error reply messages
+func NewClientErrorReplyMessage(
+	requestIdent [8]byte,
+	code,
+	message string,
+) (msg []byte) {
+	msg = NewErrorReplyMessage(requestIdent, code, message)
+
+	// Overwrite the message type flag
+	msg[0] = MsgClientErrorReply
+
+	return msg
+}
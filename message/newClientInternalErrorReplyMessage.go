@@ -0,0 +1,17 @@
+package message
+
+// NewClientInternalErrorReplyMessage composes a new internal error reply
+// message to a server request and returns its binary representation.
+// Client internal error reply messages share the structure
+// of internal error reply messages
+func NewClientInternalErrorReplyMessage(
+	requestIdent [8]byte,
+	message string,
+) (msg []byte) {
+	msg = NewInternalErrorReplyMessage(requestIdent, message)
+
+	// Overwrite the message type flag
+	msg[0] = MsgClientInternalError
+
+	return msg
+}
@@ -0,0 +1,25 @@
+package message
+
+import pld "github.com/qbeon/webwire-go/payload"
+
+// NewClientReplyMessage composes a new reply message
+// to a server request and returns its binary representation.
+// Client reply messages share the structure of reply messages
+func NewClientReplyMessage(
+	requestIdentifier [8]byte,
+	payloadEncoding pld.Encoding,
+	payloadData []byte,
+) (msg []byte) {
+	msg = NewReplyMessage(requestIdentifier, payloadEncoding, payloadData)
+
+	// Overwrite the message type flag
+	msg[0] = MsgClientReplyBinary
+	switch payloadEncoding {
+	case pld.Utf8:
+		msg[0] = MsgClientReplyUtf8
+	case pld.Utf16:
+		msg[0] = MsgClientReplyUtf16
+	}
+
+	return msg
+}
@@ -10,9 +10,7 @@ func NewEmptyRequestMessage(msgType byte, id [8]byte) (msg []byte) {
 	msg[0] = msgType
 
 	// Write request identifier
-	for i := 0; i < 8; i++ {
-		msg[1+i] = id[i]
-	}
+	writeIdentifier(msg, id)
 
 	return msg
 }
@@ -2,8 +2,24 @@ package message
 
 import "fmt"
 
+// IsValidErrorCode returns true if the given error code can be written
+// into an error reply message. Valid error codes consist of 1 to 255
+// printable ASCII 7 bit characters
+func IsValidErrorCode(code string) bool {
+	if len(code) < 1 || len(code) > 255 {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] < 32 || code[i] > 126 {
+			return false
+		}
+	}
+	return true
+}
+
 // NewErrorReplyMessage composes a new error reply message
-// and returns its binary representation
+// and returns its binary representation.
+// Panics if the error code is invalid, see IsValidErrorCode
 func NewErrorReplyMessage(
 	requestIdent [8]byte,
 	code,
@@ -22,18 +38,16 @@ func NewErrorReplyMessage(
 	}
 
 	// Determine total message length
-	msg = make([]byte, 10+len(code)+len(message))
+	msg = make([]byte, ErrorCodeOffset+len(code)+len(message))
 
 	// Write message type flag
 	msg[0] = MsgErrorReply
 
 	// Write request identifier
-	for i := 0; i < 8; i++ {
-		msg[1+i] = requestIdent[i]
-	}
+	writeIdentifier(msg, requestIdent)
 
 	// Write code length flag
-	msg[9] = byte(len(code))
+	msg[ErrorCodeLenOffset] = byte(len(code))
 
 	// Write error code
 	for i := 0; i < len(code); i++ {
@@ -44,10 +58,10 @@ func NewErrorReplyMessage(
 				string(char),
 			))
 		}
-		msg[10+i] = code[i]
+		msg[ErrorCodeOffset+i] = code[i]
 	}
 
-	errMessageOffset := 10 + len(code)
+	errMessageOffset := ErrorCodeOffset + len(code)
 
 	// Write error message
 	for i := 0; i < len(message); i++ {
@@ -0,0 +1,101 @@
+package message
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// NewErrorReplyPayloadMessage composes a new error reply message
+// carrying an additional payload and returns its binary representation
+func NewErrorReplyPayloadMessage(
+	requestIdent [8]byte,
+	code,
+	message string,
+	payloadEncoding pld.Encoding,
+	payloadData []byte,
+) (msg []byte) {
+	if len(code) < 1 {
+		panic(fmt.Errorf(
+			"Missing error code while creating a new error reply message",
+		))
+	} else if len(code) > 255 {
+		panic(fmt.Errorf(
+			"Invalid error code while creating a new error reply message,"+
+				"too long (%d)",
+			len(code),
+		))
+	}
+
+	// Verify payload data validity in case of UTF16 encoding
+	if payloadEncoding == pld.Utf16 && len(payloadData)%2 != 0 {
+		panic(fmt.Errorf(
+			"Invalid UTF16 error reply payload data length: %d",
+			len(payloadData),
+		))
+	}
+
+	// Determine total message length
+	headerSize := ErrorPayloadCodeOffset + len(code) + ErrorMessageLenSize +
+		len(message)
+	messageSize := headerSize + len(payloadData)
+
+	// Check if a header padding is necessary.
+	// A padding is necessary if the payload is UTF16 encoded
+	// but not properly aligned due to a header length not divisible by 2
+	headerPadding := false
+	if payloadEncoding == pld.Utf16 && headerSize%2 != 0 {
+		headerPadding = true
+		messageSize++
+	}
+
+	msg = make([]byte, messageSize)
+
+	// Write message type flag
+	msg[0] = MsgErrorReplyPayload
+
+	// Write request identifier
+	writeIdentifier(msg, requestIdent)
+
+	// Write payload encoding flag
+	msg[ErrorPayloadEncodingOffset] = byte(payloadEncoding)
+
+	// Write code length flag
+	msg[ErrorPayloadCodeLenOffset] = byte(len(code))
+
+	// Write error code
+	for i := 0; i < len(code); i++ {
+		char := code[i]
+		if char < 32 || char > 126 {
+			panic(fmt.Errorf(
+				"Unsupported character in reply error - error code: %s",
+				string(char),
+			))
+		}
+		msg[ErrorPayloadCodeOffset+i] = code[i]
+	}
+
+	// Write error message length
+	errMessageLenOffset := ErrorPayloadCodeOffset + len(code)
+	errMessageOffset := errMessageLenOffset + ErrorMessageLenSize
+	binary.BigEndian.PutUint32(
+		msg[errMessageLenOffset:errMessageOffset],
+		uint32(len(message)),
+	)
+
+	// Write error message
+	copy(msg[errMessageOffset:], message)
+
+	// Write header padding byte if the payload requires proper alignment
+	payloadOffset := headerSize
+	if headerPadding {
+		msg[payloadOffset] = 0
+		payloadOffset++
+	}
+
+	// Write payload
+	copy(msg[payloadOffset:], payloadData)
+
+	return msg
+}
@@ -0,0 +1,33 @@
+package message
+
+import "fmt"
+
+// NewHandshakeMessage composes a new connection handshake message
+// and returns its binary representation
+func NewHandshakeMessage(
+	protocolVersion string,
+	sessionsEnabled bool,
+) (msg []byte) {
+	if len(protocolVersion) < 1 {
+		panic(fmt.Errorf(
+			"Missing protocol version while creating a new handshake message",
+		))
+	}
+
+	msg = make([]byte, HandshakeVersionOffset+len(protocolVersion))
+
+	// Write message type flag
+	msg[0] = MsgHandshake
+
+	// Write sessions enabled flag
+	if sessionsEnabled {
+		msg[HandshakeFlagsOffset] = 1
+	}
+
+	// Write protocol version
+	for i := 0; i < len(protocolVersion); i++ {
+		msg[HandshakeVersionOffset+i] = protocolVersion[i]
+	}
+
+	return msg
+}
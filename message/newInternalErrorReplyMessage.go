@@ -0,0 +1,25 @@
+package message
+
+// NewInternalErrorReplyMessage composes a new internal error reply message
+// exposing the given UTF8 encoded error message
+// and returns its binary representation
+func NewInternalErrorReplyMessage(
+	requestIdent [8]byte,
+	message string,
+) (msg []byte) {
+	// Determine total message length
+	msg = make([]byte, IdentifiedPayloadOffset+len(message))
+
+	// Write message type flag
+	msg[0] = MsgInternalError
+
+	// Write request identifier
+	writeIdentifier(msg, requestIdent)
+
+	// Write error message
+	for i := 0; i < len(message); i++ {
+		msg[IdentifiedPayloadOffset+i] = message[i]
+	}
+
+	return msg
+}
@@ -8,19 +8,17 @@ func NewNamelessRequestMessage(
 	binaryPayload []byte,
 ) (msg []byte) {
 	// 9 byte header + n bytes payload
-	msg = make([]byte, 9+len(binaryPayload))
+	msg = make([]byte, IdentifiedPayloadOffset+len(binaryPayload))
 
 	// Write message type flag
 	msg[0] = reqType
 
 	// Write request identifier
-	for i := 0; i < 8; i++ {
-		msg[1+i] = identifier[i]
-	}
+	writeIdentifier(msg, identifier)
 
 	// Write payload
 	for i := 0; i < len(binaryPayload); i++ {
-		msg[9+i] = binaryPayload[i]
+		msg[IdentifiedPayloadOffset+i] = binaryPayload[i]
 	}
 
 	return msg
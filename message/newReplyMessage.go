@@ -14,7 +14,7 @@ func NewReplyMessage(
 	payloadData []byte,
 ) (msg []byte) {
 	// Determine total message length
-	messageSize := 9 + len(payloadData)
+	messageSize := ReplyPayloadOffset + len(payloadData)
 
 	// Verify payload data validity in case of UTF16 encoding
 	if payloadEncoding == pld.Utf16 && len(payloadData)%2 != 0 {
@@ -46,12 +46,10 @@ func NewReplyMessage(
 	msg[0] = reqType
 
 	// Write request identifier
-	for i := 0; i < 8; i++ {
-		msg[1+i] = requestIdentifier[i]
-	}
+	writeIdentifier(msg, requestIdentifier)
 
 	// Write header padding byte if the payload requires proper alignment
-	payloadOffset := 9
+	payloadOffset := ReplyPayloadOffset
 	if headerPadding {
 		msg[payloadOffset] = 0
 		payloadOffset++
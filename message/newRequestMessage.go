@@ -38,7 +38,7 @@ func NewRequestMessage(
 	}
 
 	// Determine total message length
-	messageSize := 10 + len(name) + len(payloadData)
+	messageSize := NameOffset + len(name) + len(payloadData)
 
 	// Check if a header padding is necessary.
 	// A padding is necessary if the payload is UTF16 encoded
@@ -62,12 +62,10 @@ func NewRequestMessage(
 	msg[0] = reqType
 
 	// Write request identifier
-	for i := 0; i < 8; i++ {
-		msg[1+i] = identifier[i]
-	}
+	writeIdentifier(msg, identifier)
 
 	// Write name length flag
-	msg[9] = byte(len(name))
+	msg[NameLenOffset] = byte(len(name))
 
 	// Write name
 	for i := 0; i < len(name); i++ {
@@ -78,11 +76,11 @@ func NewRequestMessage(
 				string(char),
 			))
 		}
-		msg[10+i] = char
+		msg[NameOffset+i] = char
 	}
 
 	// Write header padding byte if the payload requires proper alignment
-	payloadOffset := 10 + len(name)
+	payloadOffset := NameOffset + len(name)
 	if headerPadding {
 		msg[payloadOffset] = 0
 		payloadOffset++
@@ -0,0 +1,26 @@
+package message
+
+import pld "github.com/qbeon/webwire-go/payload"
+
+// NewServerRequestMessage composes a new named request message
+// sent by the server to the client and returns its binary representation.
+// Server request messages share the structure of request messages
+func NewServerRequestMessage(
+	identifier [8]byte,
+	name string,
+	payloadEncoding pld.Encoding,
+	payloadData []byte,
+) (msg []byte) {
+	msg = NewRequestMessage(identifier, name, payloadEncoding, payloadData)
+
+	// Overwrite the message type flag
+	msg[0] = MsgServerRequestBinary
+	switch payloadEncoding {
+	case pld.Utf8:
+		msg[0] = MsgServerRequestUtf8
+	case pld.Utf16:
+		msg[0] = MsgServerRequestUtf16
+	}
+
+	return msg
+}
@@ -0,0 +1,8 @@
+package message
+
+// NewSessionClosedMessage composes a new session closure notification
+// message attaching the optional UTF8 encoded reason
+// and returns its binary representation
+func NewSessionClosedMessage(reason string) (msg []byte) {
+	return newNotificationMessage(MsgSessionClosed, []byte(reason))
+}
@@ -0,0 +1,8 @@
+package message
+
+// NewSessionCreatedMessage composes a new session creation notification
+// message carrying the given encoded session
+// and returns its binary representation
+func NewSessionCreatedMessage(encodedSession []byte) (msg []byte) {
+	return newNotificationMessage(MsgSessionCreated, encodedSession)
+}
@@ -0,0 +1,8 @@
+package message
+
+// NewSessionInfoUpdatedMessage composes a new session info update
+// notification message carrying the given JSON encoded session info
+// and returns its binary representation
+func NewSessionInfoUpdatedMessage(encodedInfo []byte) (msg []byte) {
+	return newNotificationMessage(MsgSessionInfoUpdated, encodedInfo)
+}
@@ -0,0 +1,8 @@
+package message
+
+// NewSessionUpdatedMessage composes a new session update notification
+// message carrying the given encoded session
+// and returns its binary representation
+func NewSessionUpdatedMessage(encodedSession []byte) (msg []byte) {
+	return newNotificationMessage(MsgSessionUpdated, encodedSession)
+}
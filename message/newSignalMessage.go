@@ -29,7 +29,7 @@ func NewSignalMessage(
 	}
 
 	// Determine total message length
-	messageSize := 2 + len(name) + len(payloadData)
+	messageSize := SignalNameOffset + len(name) + len(payloadData)
 
 	// Check if a header padding is necessary.
 	// A padding is necessary if the payload is UTF16 encoded
@@ -53,7 +53,7 @@ func NewSignalMessage(
 	msg[0] = sigType
 
 	// Write name length flag
-	msg[1] = byte(len(name))
+	msg[SignalNameLenOffset] = byte(len(name))
 
 	// Write name
 	for i := 0; i < len(name); i++ {
@@ -64,11 +64,11 @@ func NewSignalMessage(
 				string(char),
 			))
 		}
-		msg[2+i] = char
+		msg[SignalNameOffset+i] = char
 	}
 
 	// Write header padding byte if the payload requires proper alignment
-	payloadOffset := 2 + len(name)
+	payloadOffset := SignalNameOffset + len(name)
 	if headerPadding {
 		msg[payloadOffset] = 0
 		payloadOffset++
@@ -17,6 +17,14 @@ func NewSpecialRequestReplyMessage(msgType byte, reqIdent [8]byte) []byte {
 		break
 	case MsgReplyProtocolError:
 		break
+	case MsgReplyUnauthenticated:
+		break
+	case MsgReplyPayloadTooLarge:
+		break
+	case MsgReplyRateLimited:
+		break
+	case MsgReplyObserver:
+		break
 	default:
 		panic(fmt.Errorf(
 			"Message type (%d) doesn't represent a special reply message",
@@ -30,9 +38,7 @@ func NewSpecialRequestReplyMessage(msgType byte, reqIdent [8]byte) []byte {
 	msg[0] = msgType
 
 	// Write request identifier
-	for i := 0; i < 8; i++ {
-		msg[1+i] = reqIdent[i]
-	}
+	writeIdentifier(msg, reqIdent)
 
 	return msg
 }
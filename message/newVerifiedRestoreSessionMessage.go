@@ -0,0 +1,50 @@
+package message
+
+import "fmt"
+
+// NewVerifiedRestoreSessionMessage composes a new session restoration
+// request message carrying an additional verification proof
+// and returns its binary representation
+func NewVerifiedRestoreSessionMessage(
+	identifier [8]byte,
+	sessionKey string,
+	proof []byte,
+) (msg []byte) {
+	if len(sessionKey) < 1 {
+		panic(fmt.Errorf(
+			"Missing session key while creating a new verified " +
+				"session restoration request message",
+		))
+	} else if len(sessionKey) > 255 {
+		panic(fmt.Errorf(
+			"Invalid session key while creating a new verified "+
+				"session restoration request message, too long (%d)",
+			len(sessionKey),
+		))
+	}
+
+	// 10 byte header + n bytes session key + n bytes proof
+	msg = make([]byte, SessionKeyOffset+len(sessionKey)+len(proof))
+
+	// Write message type flag
+	msg[0] = MsgRestoreSessionVerified
+
+	// Write request identifier
+	writeIdentifier(msg, identifier)
+
+	// Write session key length flag
+	msg[SessionKeyLenOffset] = byte(len(sessionKey))
+
+	// Write session key
+	for i := 0; i < len(sessionKey); i++ {
+		msg[SessionKeyOffset+i] = sessionKey[i]
+	}
+
+	// Write proof
+	proofOffset := SessionKeyOffset + len(sessionKey)
+	for i := 0; i < len(proof); i++ {
+		msg[proofOffset+i] = proof[i]
+	}
+
+	return msg
+}
@@ -1,6 +1,7 @@
 package message
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	pld "github.com/qbeon/webwire-go/payload"
@@ -20,6 +21,7 @@ func (msg *Message) Parse(message []byte) (parsedMsgType bool, err error) {
 
 	// Request error reply message
 	case MsgErrorReply:
+		payloadEncoding = pld.Utf8
 		err = msg.parseErrorReply(message)
 
 	// Session creation notification message
@@ -30,6 +32,18 @@ func (msg *Message) Parse(message []byte) (parsedMsgType bool, err error) {
 	case MsgSessionClosed:
 		err = msg.parseSessionClosed(message)
 
+	// Session info update notification message
+	case MsgSessionInfoUpdated:
+		err = msg.parseSessionInfoUpdated(message)
+
+	// Session update notification message
+	case MsgSessionUpdated:
+		err = msg.parseSessionUpdated(message)
+
+	// Connection handshake message
+	case MsgHandshake:
+		err = msg.parseHandshake(message)
+
 	// Session destruction request message
 	case MsgCloseSession:
 		err = msg.parseCloseSession(message)
@@ -56,6 +70,17 @@ func (msg *Message) Parse(message []byte) (parsedMsgType bool, err error) {
 		payloadEncoding = pld.Utf16
 		err = msg.parseRequestUtf16(message)
 
+	// Server request messages
+	case MsgServerRequestBinary:
+		payloadEncoding = pld.Binary
+		err = msg.parseRequest(message)
+	case MsgServerRequestUtf8:
+		payloadEncoding = pld.Utf8
+		err = msg.parseRequest(message)
+	case MsgServerRequestUtf16:
+		payloadEncoding = pld.Utf16
+		err = msg.parseRequestUtf16(message)
+
 	// Reply messages
 	case MsgReplyBinary:
 		payloadEncoding = pld.Binary
@@ -67,15 +92,35 @@ func (msg *Message) Parse(message []byte) (parsedMsgType bool, err error) {
 		payloadEncoding = pld.Utf16
 		err = msg.parseReplyUtf16(message)
 
+	// Client reply messages
+	case MsgClientReplyBinary:
+		payloadEncoding = pld.Binary
+		err = msg.parseReply(message)
+	case MsgClientReplyUtf8:
+		payloadEncoding = pld.Utf8
+		err = msg.parseReply(message)
+	case MsgClientReplyUtf16:
+		payloadEncoding = pld.Utf16
+		err = msg.parseReplyUtf16(message)
+	case MsgClientErrorReply:
+		payloadEncoding = pld.Utf8
+		err = msg.parseErrorReply(message)
+	case MsgClientInternalError:
+		payloadEncoding = pld.Utf8
+		err = msg.parseInternalError(message)
+
 	// Session restoration request message
 	case MsgRestoreSession:
 		err = msg.parseRestoreSession(message)
+	case MsgRestoreSessionVerified:
+		err = msg.parseRestoreSessionVerified(message)
 
 	// Special reply messages
 	case MsgReplyShutdown:
 		err = msg.parseSpecialReplyMessage(message)
 	case MsgInternalError:
-		err = msg.parseSpecialReplyMessage(message)
+		payloadEncoding = pld.Utf8
+		err = msg.parseInternalError(message)
 	case MsgSessionNotFound:
 		err = msg.parseSpecialReplyMessage(message)
 	case MsgMaxSessConnsReached:
@@ -84,6 +129,17 @@ func (msg *Message) Parse(message []byte) (parsedMsgType bool, err error) {
 		err = msg.parseSpecialReplyMessage(message)
 	case MsgReplyProtocolError:
 		err = msg.parseSpecialReplyMessage(message)
+	case MsgReplyUnauthenticated:
+		err = msg.parseSpecialReplyMessage(message)
+	case MsgReplyPayloadTooLarge:
+		err = msg.parseSpecialReplyMessage(message)
+	case MsgReplyRateLimited:
+		err = msg.parseSpecialReplyMessage(message)
+	case MsgReplyObserver:
+		err = msg.parseSpecialReplyMessage(message)
+	case MsgErrorReplyPayload:
+		err = msg.parseErrorReplyPayload(message)
+		payloadEncoding = msg.Payload.Encoding
 
 	// Ignore messages of invalid message type
 	default:
@@ -101,30 +157,30 @@ func (msg *Message) parseSignal(message []byte) error {
 	}
 
 	// Read name length
-	nameLen := int(byte(message[1:2][0]))
-	payloadOffset := 2 + nameLen
+	nameLen := int(message[SignalNameLenOffset])
+	payloadOffset := SignalNameOffset + nameLen
 
 	// Verify total message size to prevent segmentation faults
 	// caused by inconsistent flags. This could happen if the specified
-	// name length doesn't correspond to the actual name length
-	if len(message) < MsgMinLenSignal+nameLen {
+	// name length doesn't correspond to the actual name length.
+	// Subtract one to not require the payload if a name is given
+	if nameLen > 0 && len(message) < MsgMinLenSignal+nameLen-1 {
 		return fmt.Errorf(
-			"Invalid signal message, too short for full name (%d) "+
-				"and the minimum payload (1)",
+			"Invalid signal message, too short for full name (%d)",
 			nameLen,
 		)
 	}
 
 	if nameLen > 0 {
 		// Take name into account
-		msg.Name = string(message[2:payloadOffset])
+		msg.Name = string(message[SignalNameOffset:payloadOffset])
 		msg.Payload = pld.Payload{
 			Data: message[payloadOffset:],
 		}
 	} else {
 		// No name present, just payload
 		msg.Payload = pld.Payload{
-			Data: message[2:],
+			Data: message[SignalNameOffset:],
 		}
 	}
 	return nil
@@ -143,11 +199,15 @@ func (msg *Message) parseSignalUtf16(message []byte) error {
 	}
 
 	// Read name length
-	nameLen := int(byte(message[1:2][0]))
+	nameLen := int(message[SignalNameLenOffset])
 
-	// Determine minimum required message length
-	minMsgSize := MsgMinLenSignalUtf16 + nameLen
-	payloadOffset := 2 + nameLen
+	// Determine minimum required message length.
+	// The payload isn't required if a name is given
+	minMsgSize := MsgMinLenSignalUtf16
+	if nameLen > 0 {
+		minMsgSize = SignalNameOffset + nameLen
+	}
+	payloadOffset := SignalNameOffset + nameLen
 
 	// Check whether a name padding byte is to be expected
 	if nameLen%2 != 0 {
@@ -160,22 +220,21 @@ func (msg *Message) parseSignalUtf16(message []byte) error {
 	// name length doesn't correspond to the actual name length
 	if len(message) < minMsgSize {
 		return fmt.Errorf(
-			"Invalid signal message, too short for full name (%d) "+
-				"and the minimum payload (2)",
+			"Invalid signal message, too short for full name (%d)",
 			nameLen,
 		)
 	}
 
 	if nameLen > 0 {
 		// Take name into account
-		msg.Name = string(message[2 : 2+nameLen])
+		msg.Name = string(message[SignalNameOffset : SignalNameOffset+nameLen])
 		msg.Payload = pld.Payload{
 			Data: message[payloadOffset:],
 		}
 	} else {
 		// No name present, just payload
 		msg.Payload = pld.Payload{
-			Data: message[2:],
+			Data: message[SignalNameOffset:],
 		}
 	}
 	return nil
@@ -187,13 +246,11 @@ func (msg *Message) parseRequest(message []byte) error {
 	}
 
 	// Read identifier
-	var id [8]byte
-	copy(id[:], message[1:9])
-	msg.Identifier = id
+	msg.Identifier = readIdentifier(message)
 
 	// Read name length
-	nameLen := int(byte(message[9:10][0]))
-	payloadOffset := 10 + nameLen
+	nameLen := int(message[NameLenOffset])
+	payloadOffset := NameOffset + nameLen
 
 	// Verify total message size to prevent segmentation faults caused
 	// by inconsistent flags. This could happen if the specified name length
@@ -208,7 +265,7 @@ func (msg *Message) parseRequest(message []byte) error {
 		}
 
 		// Take name into account
-		msg.Name = string(message[10 : 10+nameLen])
+		msg.Name = string(message[NameOffset : NameOffset+nameLen])
 
 		// Read payload if any
 		if len(message) > MsgMinLenRequest+nameLen-1 {
@@ -219,7 +276,7 @@ func (msg *Message) parseRequest(message []byte) error {
 	} else {
 		// No name present, expect just the payload to be in place
 		msg.Payload = pld.Payload{
-			Data: message[10:],
+			Data: message[NameOffset:],
 		}
 	}
 
@@ -239,24 +296,22 @@ func (msg *Message) parseRequestUtf16(message []byte) error {
 	}
 
 	// Read identifier
-	var id [8]byte
-	copy(id[:], message[1:9])
-	msg.Identifier = id
+	msg.Identifier = readIdentifier(message)
 
 	// Read name length
-	nameLen := int(byte(message[9:10][0]))
+	nameLen := int(message[NameLenOffset])
 
 	// Determine minimum required message length.
 	// There's at least a 10 byte header and a 2 byte payload expected
-	minRequiredMsgSize := 12
+	minRequiredMsgSize := NameOffset + 2
 	if nameLen > 0 {
 		// ...unless a name is given, in which case the payload isn't required
-		minRequiredMsgSize = 10 + nameLen
+		minRequiredMsgSize = NameOffset + nameLen
 	}
 
 	// A header padding byte is only expected, when there's a payload
 	// beyond the name. It's not required if there's just the header and a name
-	payloadOffset := 10 + nameLen
+	payloadOffset := NameOffset + nameLen
 	if len(message) > payloadOffset && nameLen%2 != 0 {
 		minRequiredMsgSize++
 		payloadOffset++
@@ -274,7 +329,7 @@ func (msg *Message) parseRequestUtf16(message []byte) error {
 		}
 
 		// Take name into account
-		msg.Name = string(message[10 : 10+nameLen])
+		msg.Name = string(message[NameOffset : NameOffset+nameLen])
 
 		// Read payload if any
 		if len(message) > minRequiredMsgSize {
@@ -285,7 +340,7 @@ func (msg *Message) parseRequestUtf16(message []byte) error {
 	} else {
 		// No name present, just payload
 		msg.Payload = pld.Payload{
-			Data: message[10:],
+			Data: message[NameOffset:],
 		}
 	}
 
@@ -298,9 +353,7 @@ func (msg *Message) parseReply(message []byte) error {
 	}
 
 	// Read identifier
-	var id [8]byte
-	copy(id[:], message[1:9])
-	msg.Identifier = id
+	msg.Identifier = readIdentifier(message)
 
 	// Skip payload if there's none
 	if len(message) == MsgMinLenReply {
@@ -309,7 +362,7 @@ func (msg *Message) parseReply(message []byte) error {
 
 	// Read payload
 	msg.Payload = pld.Payload{
-		Data: message[9:],
+		Data: message[ReplyPayloadOffset:],
 	}
 	return nil
 }
@@ -327,9 +380,7 @@ func (msg *Message) parseReplyUtf16(message []byte) error {
 	}
 
 	// Read identifier
-	var id [8]byte
-	copy(id[:], message[1:9])
-	msg.Identifier = id
+	msg.Identifier = readIdentifier(message)
 
 	// Skip payload if there's none
 	if len(message) == MsgMinLenReplyUtf16 {
@@ -342,7 +393,7 @@ func (msg *Message) parseReplyUtf16(message []byte) error {
 	// Read payload
 	msg.Payload = pld.Payload{
 		// Take header padding byte into account
-		Data: message[10:],
+		Data: message[ReplyPayloadOffset+1:],
 	}
 	return nil
 }
@@ -356,13 +407,11 @@ func (msg *Message) parseErrorReply(message []byte) error {
 	}
 
 	// Read identifier
-	var id [8]byte
-	copy(id[:], message[1:9])
-	msg.Identifier = id
+	msg.Identifier = readIdentifier(message)
 
 	// Read error code length flag
-	errCodeLen := int(byte(message[9:10][0]))
-	errMessageOffset := 10 + errCodeLen
+	errCodeLen := int(message[ErrorCodeLenOffset])
+	errMessageOffset := ErrorCodeOffset + errCodeLen
 
 	// Verify error code length (must be at least 1 character long)
 	if errCodeLen < 1 {
@@ -385,7 +434,7 @@ func (msg *Message) parseErrorReply(message []byte) error {
 	}
 
 	// Read UTF8 encoded error message into the payload
-	msg.Name = string(message[10 : 10+errCodeLen])
+	msg.Name = string(message[ErrorCodeOffset:errMessageOffset])
 	msg.Payload = pld.Payload{
 		Encoding: pld.Utf8,
 		Data:     message[errMessageOffset:],
@@ -393,6 +442,90 @@ func (msg *Message) parseErrorReply(message []byte) error {
 	return nil
 }
 
+// parseErrorReplyPayload parses the given message assuming it's an error reply
+// message carrying a payload parsing the error code into the name field,
+// the UTF8 encoded error message into the error message field
+// and the payload into the payload
+func (msg *Message) parseErrorReplyPayload(message []byte) error {
+	if len(message) < MsgMinLenErrorReplyPayload {
+		return fmt.Errorf("Invalid error reply message, too short")
+	}
+
+	// Read identifier
+	msg.Identifier = readIdentifier(message)
+
+	// Read payload encoding flag
+	payloadEncoding := pld.Encoding(message[ErrorPayloadEncodingOffset])
+	switch payloadEncoding {
+	case pld.Binary:
+	case pld.Utf8:
+	case pld.Utf16:
+	default:
+		return fmt.Errorf(
+			"Invalid error reply message, unsupported payload encoding (%d)",
+			message[ErrorPayloadEncodingOffset],
+		)
+	}
+
+	// Read error code length flag
+	errCodeLen := int(message[ErrorPayloadCodeLenOffset])
+	if errCodeLen < 1 {
+		return fmt.Errorf(
+			"Invalid error reply message, error code length flag is zero",
+		)
+	}
+
+	// Verify total message size to prevent segmentation faults
+	// caused by inconsistent flags.
+	// Subtract 1 character already taken into account
+	// by MsgMinLenErrorReplyPayload
+	if len(message) < MsgMinLenErrorReplyPayload+errCodeLen-1 {
+		return fmt.Errorf(
+			"Invalid error reply message, "+
+				"too short for specified code length (%d)",
+			errCodeLen,
+		)
+	}
+	errMessageLenOffset := ErrorPayloadCodeOffset + errCodeLen
+	msg.Name = string(message[ErrorPayloadCodeOffset:errMessageLenOffset])
+
+	// Read error message length
+	errMessageOffset := errMessageLenOffset + ErrorMessageLenSize
+	errMessageLen := binary.BigEndian.Uint32(
+		message[errMessageLenOffset:errMessageOffset],
+	)
+	if uint64(len(message)-errMessageOffset) < uint64(errMessageLen) {
+		return fmt.Errorf(
+			"Invalid error reply message, "+
+				"too short for specified error message length (%d)",
+			errMessageLen,
+		)
+	}
+	payloadOffset := errMessageOffset + int(errMessageLen)
+	msg.ErrorMessage = string(message[errMessageOffset:payloadOffset])
+
+	// Skip the header padding byte if the payload requires proper alignment
+	if payloadEncoding == pld.Utf16 && payloadOffset%2 != 0 {
+		if len(message) < payloadOffset+1 {
+			return fmt.Errorf(
+				"Invalid error reply message, missing header padding",
+			)
+		}
+		payloadOffset++
+	}
+	if payloadEncoding == pld.Utf16 && (len(message)-payloadOffset)%2 != 0 {
+		return fmt.Errorf(
+			"Unaligned UTF16 encoded error reply payload",
+		)
+	}
+
+	msg.Payload = pld.Payload{
+		Encoding: payloadEncoding,
+		Data:     message[payloadOffset:],
+	}
+	return nil
+}
+
 func (msg *Message) parseRestoreSession(message []byte) error {
 	if len(message) < MsgMinLenRestoreSession {
 		return fmt.Errorf(
@@ -401,13 +534,52 @@ func (msg *Message) parseRestoreSession(message []byte) error {
 	}
 
 	// Read identifier
-	var id [8]byte
-	copy(id[:], message[1:9])
-	msg.Identifier = id
+	msg.Identifier = readIdentifier(message)
 
 	// Read payload
 	msg.Payload = pld.Payload{
-		Data: message[9:],
+		Data: message[IdentifiedPayloadOffset:],
+	}
+	return nil
+}
+
+func (msg *Message) parseRestoreSessionVerified(message []byte) error {
+	if len(message) < MsgMinLenRestoreSessionVerified {
+		return fmt.Errorf(
+			"Invalid verified session restoration request message, too short",
+		)
+	}
+
+	// Read identifier
+	msg.Identifier = readIdentifier(message)
+
+	// Read session key length
+	keyLen := int(message[SessionKeyLenOffset])
+	if keyLen < 1 {
+		return fmt.Errorf(
+			"Invalid verified session restoration request message, " +
+				"session key length flag is 0",
+		)
+	}
+
+	// Verify total message size to prevent segmentation faults
+	// caused by inconsistent flags. This could happen if the specified
+	// session key length doesn't correspond to the actual key length.
+	// Subtract 1 character already taken into account
+	// by MsgMinLenRestoreSessionVerified
+	if len(message) < MsgMinLenRestoreSessionVerified+keyLen-1 {
+		return fmt.Errorf(
+			"Invalid verified session restoration request message, "+
+				"too short for full session key (%d)",
+			keyLen,
+		)
+	}
+
+	// Read session key into the name and the proof into the payload
+	proofOffset := SessionKeyOffset + keyLen
+	msg.Name = string(message[SessionKeyOffset:proofOffset])
+	msg.Payload = pld.Payload{
+		Data: message[proofOffset:],
 	}
 	return nil
 }
@@ -420,9 +592,7 @@ func (msg *Message) parseCloseSession(message []byte) error {
 	}
 
 	// Read identifier
-	var id [8]byte
-	copy(id[:], message[1:9])
-	msg.Identifier = id
+	msg.Identifier = readIdentifier(message)
 
 	return nil
 }
@@ -434,6 +604,32 @@ func (msg *Message) parseSessionCreated(message []byte) error {
 		)
 	}
 
+	msg.Payload = pld.Payload{
+		Data: message[NotificationPayloadOffset:],
+	}
+	return nil
+}
+
+func (msg *Message) parseSessionInfoUpdated(message []byte) error {
+	if len(message) < MsgMinLenSessionInfoUpdated {
+		return fmt.Errorf(
+			"Invalid session info update notification message, too short",
+		)
+	}
+
+	msg.Payload = pld.Payload{
+		Data: message[NotificationPayloadOffset:],
+	}
+	return nil
+}
+
+func (msg *Message) parseSessionUpdated(message []byte) error {
+	if len(message) < MsgMinLenSessionUpdated {
+		return fmt.Errorf(
+			"Invalid session update notification message, too short",
+		)
+	}
+
 	msg.Payload = pld.Payload{
 		Data: message[1:],
 	}
@@ -441,23 +637,54 @@ func (msg *Message) parseSessionCreated(message []byte) error {
 }
 
 func (msg *Message) parseSessionClosed(message []byte) error {
-	if len(message) != MsgMinLenSessionClosed {
+	if len(message) < MsgMinLenSessionClosed {
 		return fmt.Errorf(
 			"Invalid session closure notification message, too short",
 		)
 	}
+
+	// Read the optional UTF8 encoded reason
+	if len(message) > NotificationPayloadOffset {
+		msg.Payload = pld.Payload{
+			Data: message[NotificationPayloadOffset:],
+		}
+	}
+	return nil
+}
+
+func (msg *Message) parseHandshake(message []byte) error {
+	if len(message) < MsgMinLenHandshake {
+		return fmt.Errorf("Invalid handshake message, too short")
+	}
+
+	msg.Name = string(message[HandshakeVersionOffset:])
+	msg.Payload = pld.Payload{
+		Data: message[HandshakeFlagsOffset:HandshakeVersionOffset],
+	}
 	return nil
 }
 
 func (msg *Message) parseSpecialReplyMessage(message []byte) error {
-	if len(message) < 9 {
+	if len(message) < IdentifiedPayloadOffset {
 		return fmt.Errorf("Invalid special reply message, too short")
 	}
 
 	// Read identifier
-	var id [8]byte
-	copy(id[:], message[1:9])
-	msg.Identifier = id
+	msg.Identifier = readIdentifier(message)
 
 	return nil
 }
+
+func (msg *Message) parseInternalError(message []byte) error {
+	if err := msg.parseSpecialReplyMessage(message); err != nil {
+		return err
+	}
+
+	// Read the optional UTF8 encoded error message
+	if len(message) > IdentifiedPayloadOffset {
+		msg.Payload = pld.Payload{
+			Data: message[IdentifiedPayloadOffset:],
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package message
 import (
 	"testing"
 
+	pld "github.com/qbeon/webwire-go/payload"
 	"github.com/stretchr/testify/require"
 )
 
@@ -144,6 +145,42 @@ func TestMsgParseInvalidSessCreatedSigTooShort(t *testing.T) {
 	)
 }
 
+// TestMsgParseInvalidSessInfoUpdatedSigTooShort tests parsing of an invalid
+// session info update notification message which is too short
+// to be considered valid
+func TestMsgParseInvalidSessInfoUpdatedSigTooShort(t *testing.T) {
+	lenTooShort := MsgMinLenSessionInfoUpdated - 1
+	invalidMessage := make([]byte, lenTooShort)
+
+	invalidMessage[0] = MsgSessionInfoUpdated
+
+	_, err := tryParse(t, invalidMessage)
+	require.Error(t,
+		err,
+		"Expected error while parsing invalid session info update "+
+			"notification message (too short: %d)",
+		lenTooShort,
+	)
+}
+
+// TestMsgParseInvalidSessUpdatedSigTooShort tests parsing of an invalid
+// session update notification message which is too short
+// to be considered valid
+func TestMsgParseInvalidSessUpdatedSigTooShort(t *testing.T) {
+	lenTooShort := MsgMinLenSessionUpdated - 1
+	invalidMessage := make([]byte, lenTooShort)
+
+	invalidMessage[0] = MsgSessionUpdated
+
+	_, err := tryParse(t, invalidMessage)
+	require.Error(t,
+		err,
+		"Expected error while parsing invalid session update "+
+			"notification message (too short: %d)",
+		lenTooShort,
+	)
+}
+
 // TestMsgParseInvalidSignalTooShort tests parsing of an invalid
 // binary/UTF8 signal message which is too short to be considered valid
 func TestMsgParseInvalidSignalTooShort(t *testing.T) {
@@ -194,6 +231,42 @@ func TestMsgParseInvalidErrorReplyTooShort(t *testing.T) {
 	)
 }
 
+// TestMsgParseInvalidErrorReplyPayloadTooShort tests parsing of an invalid
+// error reply message with payload which is too short to be considered valid
+func TestMsgParseInvalidErrorReplyPayloadTooShort(t *testing.T) {
+	lenTooShort := MsgMinLenErrorReplyPayload - 1
+	invalidMessage := make([]byte, lenTooShort)
+
+	invalidMessage[0] = MsgErrorReplyPayload
+
+	_, err := tryParse(t, invalidMessage)
+	require.Error(t,
+		err,
+		"Expected error while parsing invalid error reply message "+
+			"(too short: %d)",
+		lenTooShort,
+	)
+}
+
+// TestMsgParseInvalidErrorReplyPayloadCorruptMessageLen tests parsing
+// of an invalid error reply message with payload specifying an error message
+// length exceeding the actual message
+func TestMsgParseInvalidErrorReplyPayloadCorruptMessageLen(t *testing.T) {
+	encoded := NewErrorReplyPayloadMessage(
+		genRndMsgIdentifier(),
+		"SAMPLE_CODE",
+		"sample error",
+		pld.Binary,
+		nil,
+	)
+
+	// Corrupt the error message length
+	encoded[11+len("SAMPLE_CODE")] = 255
+
+	_, err := tryParse(t, encoded)
+	require.Error(t, err)
+}
+
 // TestMsgParseInvalidSpecialReplyTooShort tests parsing of an invalid
 // special reply message which is too short to be considered valid
 func TestMsgParseInvalidSpecialReplyTooShort(t *testing.T) {
@@ -209,3 +282,36 @@ func TestMsgParseInvalidSpecialReplyTooShort(t *testing.T) {
 			"(too short: 8)",
 	)
 }
+
+// TestMsgParseInvalidHandshakeTooShort tests parsing of an invalid
+// handshake message which is too short to be considered valid
+func TestMsgParseInvalidHandshakeTooShort(t *testing.T) {
+	// Missing protocol version
+	invalidMessage := []byte{MsgHandshake, 1}
+
+	_, err := tryParse(t, invalidMessage)
+	require.Error(t,
+		err,
+		"Expected error while parsing invalid handshake message "+
+			"(too short: 2)",
+	)
+}
+
+// TestMsgParseInvalidRestrSessVerifiedReqTooShort tests parsing
+// of an invalid verified session restoration request message
+// which is too short for the specified session key length
+func TestMsgParseInvalidRestrSessVerifiedReqTooShort(t *testing.T) {
+	invalidMessage := []byte{
+		MsgRestoreSessionVerified, // Message type identifier
+		0, 0, 0, 0, 0, 0, 0, 0,    // Request identifier
+		3,    // Session key length flag
+		0x41, // Session key
+	}
+
+	_, err := tryParse(t, invalidMessage)
+	require.Error(t,
+		err,
+		"Expected error while parsing invalid verified session restoration "+
+			"request message (too short for the session key)",
+	)
+}
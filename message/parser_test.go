@@ -273,6 +273,21 @@ func TestMsgParseSignalUtf16(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+// TestMsgParseSignalNoPayload tests parsing of named signals
+// without a payload
+func TestMsgParseSignalNoPayload(t *testing.T) {
+	for _, encoding := range []pld.Encoding{pld.Binary, pld.Utf16} {
+		for _, name := range []string{"a", "ab"} {
+			encoded := NewSignalMessage(name, encoding, nil)
+
+			actual := tryParseNoErr(t, encoded)
+			require.Equal(t, name, actual.Name)
+			require.Equal(t, encoding, actual.Payload.Encoding)
+			require.Len(t, actual.Payload.Data, 0)
+		}
+	}
+}
+
 // TestMsgParseSessCreatedSig tests parsing of session created signal
 func TestMsgParseSessCreatedSig(t *testing.T) {
 	//sessionKey := generateSessionKey()
@@ -335,6 +350,171 @@ func TestMsgParseSessClosedSig(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+// TestMsgParseSessClosedSigReason tests parsing of session closed
+// notification messages carrying a reason
+func TestMsgParseSessClosedSigReason(t *testing.T) {
+	reason := "closed by admin"
+
+	// Compose encoded message
+	encoded := append([]byte{MsgSessionClosed}, reason...)
+
+	// Initialize expected message
+	expected := Message{
+		Type:       MsgSessionClosed,
+		Identifier: [8]byte{0, 0, 0, 0, 0, 0, 0, 0},
+		Name:       "",
+		Payload:    pld.Payload{Data: []byte(reason)},
+	}
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgParseInternalError tests parsing of an internal error reply message
+// without an exposed error message
+func TestMsgParseInternalError(t *testing.T) {
+	id := genRndMsgIdentifier()
+	encoded := NewSpecialRequestReplyMessage(MsgInternalError, id)
+
+	// Initialize expected message
+	expected := Message{
+		Type:       MsgInternalError,
+		Identifier: id,
+		Name:       "",
+		Payload: pld.Payload{
+			Encoding: pld.Utf8,
+		},
+	}
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgParseInternalErrorExposed tests parsing of an internal error
+// reply message exposing the error message
+func TestMsgParseInternalErrorExposed(t *testing.T) {
+	id := genRndMsgIdentifier()
+	errMessage := "sample internal error message"
+	encoded := NewInternalErrorReplyMessage(id, errMessage)
+
+	// Initialize expected message
+	expected := Message{
+		Type:       MsgInternalError,
+		Identifier: id,
+		Name:       "",
+		Payload: pld.Payload{
+			Encoding: pld.Utf8,
+			Data:     []byte(errMessage),
+		},
+	}
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgParseHandshake tests parsing of a connection handshake message
+func TestMsgParseHandshake(t *testing.T) {
+	// Compose encoded message
+	// Add type flag
+	encoded := []byte{MsgHandshake}
+	// Add sessions enabled flag
+	encoded = append(encoded, 1)
+	// Add protocol version
+	encoded = append(encoded, []byte("1.5")...)
+
+	// Initialize expected message
+	expected := Message{
+		Type:       MsgHandshake,
+		Identifier: [8]byte{0, 0, 0, 0, 0, 0, 0, 0},
+		Name:       "1.5",
+		Payload: pld.Payload{
+			Data: []byte{1},
+		},
+	}
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgParseRestrSessVerifiedReq tests parsing
+// of a verified session restoration request
+func TestMsgParseRestrSessVerifiedReq(t *testing.T) {
+	id := genRndMsgIdentifier()
+	sessionKey := "somesamplesessionkey"
+	proof := []byte("somesampleproof")
+
+	// Compose encoded message
+	// Add type flag
+	encoded := []byte{MsgRestoreSessionVerified}
+	// Add identifier
+	encoded = append(encoded, id[:]...)
+	// Add session key length flag
+	encoded = append(encoded, byte(len(sessionKey)))
+	// Add session key
+	encoded = append(encoded, []byte(sessionKey)...)
+	// Add proof
+	encoded = append(encoded, proof...)
+
+	// Initialize expected message
+	expected := Message{
+		Type:       MsgRestoreSessionVerified,
+		Identifier: id,
+		Name:       sessionKey,
+		Payload: pld.Payload{
+			Data: proof,
+		},
+	}
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgParseSessInfoUpdatedSig tests parsing of session info update
+// notification messages
+func TestMsgParseSessInfoUpdatedSig(t *testing.T) {
+	marshalledInfo, err := json.Marshal(map[string]interface{}{
+		"status": "updated",
+	})
+	require.NoError(t, err)
+
+	// Compose encoded message
+	// Add type flag
+	encoded := []byte{MsgSessionInfoUpdated}
+	// Add session info payload
+	encoded = append(encoded, marshalledInfo...)
+
+	// Initialize expected message
+	expected := Message{
+		Type:       MsgSessionInfoUpdated,
+		Identifier: [8]byte{0, 0, 0, 0, 0, 0, 0, 0},
+		Name:       "",
+		Payload: pld.Payload{
+			Data: marshalledInfo,
+		},
+	}
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, expected, actual)
+}
+
 // TestMsgParseUnknownMessageType tests parsing of messages
 // with unknown message type
 func TestMsgParseUnknownMessageType(t *testing.T) {
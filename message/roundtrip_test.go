@@ -0,0 +1,422 @@
+package message
+
+import (
+	"testing"
+
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTripCase represents a message round-trip test case
+// composing an encoded message and defining the expected parsed message
+type roundTripCase struct {
+	name     string
+	encode   func() []byte
+	expected Message
+}
+
+// roundTripCases returns the round-trip test cases for all message types.
+// New message types must be added here, TestMsgRoundTripCoverage
+// ensures every message type listed in Types is covered
+func roundTripCases() []roundTripCase {
+	id := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
+	name := "samplename"
+	data := []byte("sample payload")
+	dataUtf16 := []byte("sample utf16 payload")
+
+	// special returns a test case for the given special reply message type
+	special := func(msgType byte) roundTripCase {
+		return roundTripCase{
+			name: Type(msgType).String(),
+			encode: func() []byte {
+				return NewSpecialRequestReplyMessage(msgType, id)
+			},
+			expected: Message{Type: msgType, Identifier: id},
+		}
+	}
+
+	// notification returns a test case for the given notification message
+	// type composed by the given constructor
+	notification := func(
+		msgType byte,
+		payload []byte,
+		compose func(payload []byte) []byte,
+	) roundTripCase {
+		return roundTripCase{
+			name: Type(msgType).String(),
+			encode: func() []byte {
+				return compose(payload)
+			},
+			expected: Message{
+				Type:    msgType,
+				Payload: pld.Payload{Data: payload},
+			},
+		}
+	}
+
+	return []roundTripCase{
+		{
+			name: "ErrorReply",
+			encode: func() []byte {
+				return NewErrorReplyMessage(id, "SAMPLE_CODE", "sample error")
+			},
+			expected: Message{
+				Type:       MsgErrorReply,
+				Identifier: id,
+				Name:       "SAMPLE_CODE",
+				Payload: pld.Payload{
+					Encoding: pld.Utf8,
+					Data:     []byte("sample error"),
+				},
+			},
+		},
+		special(MsgReplyShutdown),
+		{
+			name: "InternalError",
+			encode: func() []byte {
+				return NewInternalErrorReplyMessage(id, "sample error")
+			},
+			expected: Message{
+				Type:       MsgInternalError,
+				Identifier: id,
+				Payload: pld.Payload{
+					Encoding: pld.Utf8,
+					Data:     []byte("sample error"),
+				},
+			},
+		},
+		special(MsgSessionNotFound),
+		special(MsgMaxSessConnsReached),
+		special(MsgSessionsDisabled),
+		special(MsgReplyProtocolError),
+		special(MsgReplyUnauthenticated),
+		special(MsgReplyPayloadTooLarge),
+		special(MsgReplyRateLimited),
+		special(MsgReplyObserver),
+		{
+			name: "ErrorReplyPayload",
+			encode: func() []byte {
+				// The header length is odd to cover the UTF16 header padding
+				return NewErrorReplyPayloadMessage(
+					id,
+					"SAMPLE_ERR",
+					"sample error",
+					pld.Utf16,
+					dataUtf16,
+				)
+			},
+			expected: Message{
+				Type:         MsgErrorReplyPayload,
+				Identifier:   id,
+				Name:         "SAMPLE_ERR",
+				ErrorMessage: "sample error",
+				Payload: pld.Payload{
+					Encoding: pld.Utf16,
+					Data:     dataUtf16,
+				},
+			},
+		},
+		notification(
+			MsgSessionCreated,
+			[]byte(`{"k":"samplekey"}`),
+			NewSessionCreatedMessage,
+		),
+		notification(
+			MsgSessionClosed,
+			nil,
+			func(payload []byte) []byte {
+				return NewSessionClosedMessage(string(payload))
+			},
+		),
+		{
+			name: "Handshake",
+			encode: func() []byte {
+				return NewHandshakeMessage("1.5", true)
+			},
+			expected: Message{
+				Type:    MsgHandshake,
+				Name:    "1.5",
+				Payload: pld.Payload{Data: []byte{1}},
+			},
+		},
+		notification(
+			MsgSessionInfoUpdated,
+			[]byte(`{"field":"value"}`),
+			NewSessionInfoUpdatedMessage,
+		),
+		notification(
+			MsgSessionUpdated,
+			[]byte(`{"k":"samplekey"}`),
+			NewSessionUpdatedMessage,
+		),
+		{
+			name: "CloseSession",
+			encode: func() []byte {
+				return NewEmptyRequestMessage(MsgCloseSession, id)
+			},
+			expected: Message{Type: MsgCloseSession, Identifier: id},
+		},
+		{
+			name: "RestoreSession",
+			encode: func() []byte {
+				return NewNamelessRequestMessage(
+					MsgRestoreSession,
+					id,
+					[]byte("samplekey"),
+				)
+			},
+			expected: Message{
+				Type:       MsgRestoreSession,
+				Identifier: id,
+				Payload:    pld.Payload{Data: []byte("samplekey")},
+			},
+		},
+		{
+			name: "RestoreSessionVerified",
+			encode: func() []byte {
+				return NewVerifiedRestoreSessionMessage(
+					id,
+					"samplekey",
+					[]byte("sampleproof"),
+				)
+			},
+			expected: Message{
+				Type:       MsgRestoreSessionVerified,
+				Identifier: id,
+				Name:       "samplekey",
+				Payload:    pld.Payload{Data: []byte("sampleproof")},
+			},
+		},
+		{
+			name: "ClientErrorReply",
+			encode: func() []byte {
+				return NewClientErrorReplyMessage(
+					id,
+					"SAMPLE_CODE",
+					"sample error",
+				)
+			},
+			expected: Message{
+				Type:       MsgClientErrorReply,
+				Identifier: id,
+				Name:       "SAMPLE_CODE",
+				Payload: pld.Payload{
+					Encoding: pld.Utf8,
+					Data:     []byte("sample error"),
+				},
+			},
+		},
+		{
+			name: "ClientInternalError",
+			encode: func() []byte {
+				return NewClientInternalErrorReplyMessage(id, "sample error")
+			},
+			expected: Message{
+				Type:       MsgClientInternalError,
+				Identifier: id,
+				Payload: pld.Payload{
+					Encoding: pld.Utf8,
+					Data:     []byte("sample error"),
+				},
+			},
+		},
+		{
+			name: "SignalBinary",
+			encode: func() []byte {
+				return NewSignalMessage(name, pld.Binary, data)
+			},
+			expected: Message{
+				Type:    MsgSignalBinary,
+				Name:    name,
+				Payload: pld.Payload{Encoding: pld.Binary, Data: data},
+			},
+		},
+		{
+			name: "SignalUtf8",
+			encode: func() []byte {
+				return NewSignalMessage(name, pld.Utf8, data)
+			},
+			expected: Message{
+				Type:    MsgSignalUtf8,
+				Name:    name,
+				Payload: pld.Payload{Encoding: pld.Utf8, Data: data},
+			},
+		},
+		{
+			name: "SignalUtf16",
+			encode: func() []byte {
+				return NewSignalMessage(name, pld.Utf16, dataUtf16)
+			},
+			expected: Message{
+				Type:    MsgSignalUtf16,
+				Name:    name,
+				Payload: pld.Payload{Encoding: pld.Utf16, Data: dataUtf16},
+			},
+		},
+		{
+			name: "RequestBinary",
+			encode: func() []byte {
+				return NewRequestMessage(id, name, pld.Binary, data)
+			},
+			expected: Message{
+				Type:       MsgRequestBinary,
+				Identifier: id,
+				Name:       name,
+				Payload:    pld.Payload{Encoding: pld.Binary, Data: data},
+			},
+		},
+		{
+			name: "RequestUtf8",
+			encode: func() []byte {
+				return NewRequestMessage(id, name, pld.Utf8, data)
+			},
+			expected: Message{
+				Type:       MsgRequestUtf8,
+				Identifier: id,
+				Name:       name,
+				Payload:    pld.Payload{Encoding: pld.Utf8, Data: data},
+			},
+		},
+		{
+			name: "RequestUtf16",
+			encode: func() []byte {
+				return NewRequestMessage(id, name, pld.Utf16, dataUtf16)
+			},
+			expected: Message{
+				Type:       MsgRequestUtf16,
+				Identifier: id,
+				Name:       name,
+				Payload:    pld.Payload{Encoding: pld.Utf16, Data: dataUtf16},
+			},
+		},
+		{
+			name: "ServerRequestBinary",
+			encode: func() []byte {
+				return NewServerRequestMessage(id, name, pld.Binary, data)
+			},
+			expected: Message{
+				Type:       MsgServerRequestBinary,
+				Identifier: id,
+				Name:       name,
+				Payload:    pld.Payload{Encoding: pld.Binary, Data: data},
+			},
+		},
+		{
+			name: "ServerRequestUtf8",
+			encode: func() []byte {
+				return NewServerRequestMessage(id, name, pld.Utf8, data)
+			},
+			expected: Message{
+				Type:       MsgServerRequestUtf8,
+				Identifier: id,
+				Name:       name,
+				Payload:    pld.Payload{Encoding: pld.Utf8, Data: data},
+			},
+		},
+		{
+			name: "ServerRequestUtf16",
+			encode: func() []byte {
+				return NewServerRequestMessage(id, name, pld.Utf16, dataUtf16)
+			},
+			expected: Message{
+				Type:       MsgServerRequestUtf16,
+				Identifier: id,
+				Name:       name,
+				Payload:    pld.Payload{Encoding: pld.Utf16, Data: dataUtf16},
+			},
+		},
+		{
+			name: "ReplyBinary",
+			encode: func() []byte {
+				return NewReplyMessage(id, pld.Binary, data)
+			},
+			expected: Message{
+				Type:       MsgReplyBinary,
+				Identifier: id,
+				Payload:    pld.Payload{Encoding: pld.Binary, Data: data},
+			},
+		},
+		{
+			name: "ReplyUtf8",
+			encode: func() []byte {
+				return NewReplyMessage(id, pld.Utf8, data)
+			},
+			expected: Message{
+				Type:       MsgReplyUtf8,
+				Identifier: id,
+				Payload:    pld.Payload{Encoding: pld.Utf8, Data: data},
+			},
+		},
+		{
+			name: "ReplyUtf16",
+			encode: func() []byte {
+				return NewReplyMessage(id, pld.Utf16, dataUtf16)
+			},
+			expected: Message{
+				Type:       MsgReplyUtf16,
+				Identifier: id,
+				Payload:    pld.Payload{Encoding: pld.Utf16, Data: dataUtf16},
+			},
+		},
+		{
+			name: "ClientReplyBinary",
+			encode: func() []byte {
+				return NewClientReplyMessage(id, pld.Binary, data)
+			},
+			expected: Message{
+				Type:       MsgClientReplyBinary,
+				Identifier: id,
+				Payload:    pld.Payload{Encoding: pld.Binary, Data: data},
+			},
+		},
+		{
+			name: "ClientReplyUtf8",
+			encode: func() []byte {
+				return NewClientReplyMessage(id, pld.Utf8, data)
+			},
+			expected: Message{
+				Type:       MsgClientReplyUtf8,
+				Identifier: id,
+				Payload:    pld.Payload{Encoding: pld.Utf8, Data: data},
+			},
+		},
+		{
+			name: "ClientReplyUtf16",
+			encode: func() []byte {
+				return NewClientReplyMessage(id, pld.Utf16, dataUtf16)
+			},
+			expected: Message{
+				Type:       MsgClientReplyUtf16,
+				Identifier: id,
+				Payload:    pld.Payload{Encoding: pld.Utf16, Data: dataUtf16},
+			},
+		},
+	}
+}
+
+// TestMsgRoundTrip tests composing, parsing and comparing
+// messages of all types
+func TestMsgRoundTrip(t *testing.T) {
+	for _, tc := range roundTripCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tryParseNoErr(t, tc.encode())
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+// TestMsgRoundTripCoverage tests whether all message types
+// are covered by the round-trip test cases
+func TestMsgRoundTripCoverage(t *testing.T) {
+	covered := make(map[byte]bool)
+	for _, tc := range roundTripCases() {
+		covered[tc.expected.Type] = true
+	}
+	for _, msgType := range Types {
+		require.True(t,
+			covered[byte(msgType)],
+			"Message type %s not covered by the round-trip test",
+			msgType,
+		)
+	}
+}
@@ -0,0 +1,128 @@
+package message
+
+// Type represents the type of a message
+// identified by the first byte of an encoded message
+type Type byte
+
+// Types lists all message types defined by the protocol
+var Types = []Type{
+	Type(MsgErrorReply),
+	Type(MsgReplyShutdown),
+	Type(MsgInternalError),
+	Type(MsgSessionNotFound),
+	Type(MsgMaxSessConnsReached),
+	Type(MsgSessionsDisabled),
+	Type(MsgReplyProtocolError),
+	Type(MsgReplyUnauthenticated),
+	Type(MsgErrorReplyPayload),
+	Type(MsgReplyPayloadTooLarge),
+	Type(MsgReplyRateLimited),
+	Type(MsgReplyObserver),
+	Type(MsgSessionCreated),
+	Type(MsgSessionClosed),
+	Type(MsgHandshake),
+	Type(MsgSessionInfoUpdated),
+	Type(MsgSessionUpdated),
+	Type(MsgCloseSession),
+	Type(MsgRestoreSession),
+	Type(MsgRestoreSessionVerified),
+	Type(MsgClientErrorReply),
+	Type(MsgClientInternalError),
+	Type(MsgSignalBinary),
+	Type(MsgSignalUtf8),
+	Type(MsgSignalUtf16),
+	Type(MsgRequestBinary),
+	Type(MsgRequestUtf8),
+	Type(MsgRequestUtf16),
+	Type(MsgServerRequestBinary),
+	Type(MsgServerRequestUtf8),
+	Type(MsgServerRequestUtf16),
+	Type(MsgReplyBinary),
+	Type(MsgReplyUtf8),
+	Type(MsgReplyUtf16),
+	Type(MsgClientReplyBinary),
+	Type(MsgClientReplyUtf8),
+	Type(MsgClientReplyUtf16),
+}
+
+// String returns the name of the message type
+// or "Unknown" if the type isn't defined by the protocol
+func (tp Type) String() string {
+	switch byte(tp) {
+	case MsgErrorReply:
+		return "ErrorReply"
+	case MsgReplyShutdown:
+		return "ReplyShutdown"
+	case MsgInternalError:
+		return "InternalError"
+	case MsgSessionNotFound:
+		return "SessionNotFound"
+	case MsgMaxSessConnsReached:
+		return "MaxSessConnsReached"
+	case MsgSessionsDisabled:
+		return "SessionsDisabled"
+	case MsgReplyProtocolError:
+		return "ReplyProtocolError"
+	case MsgReplyUnauthenticated:
+		return "ReplyUnauthenticated"
+	case MsgErrorReplyPayload:
+		return "ErrorReplyPayload"
+	case MsgReplyPayloadTooLarge:
+		return "ReplyPayloadTooLarge"
+	case MsgReplyRateLimited:
+		return "ReplyRateLimited"
+	case MsgReplyObserver:
+		return "ReplyObserver"
+	case MsgSessionCreated:
+		return "SessionCreated"
+	case MsgSessionClosed:
+		return "SessionClosed"
+	case MsgHandshake:
+		return "Handshake"
+	case MsgSessionInfoUpdated:
+		return "SessionInfoUpdated"
+	case MsgSessionUpdated:
+		return "SessionUpdated"
+	case MsgCloseSession:
+		return "CloseSession"
+	case MsgRestoreSession:
+		return "RestoreSession"
+	case MsgRestoreSessionVerified:
+		return "RestoreSessionVerified"
+	case MsgClientErrorReply:
+		return "ClientErrorReply"
+	case MsgClientInternalError:
+		return "ClientInternalError"
+	case MsgSignalBinary:
+		return "SignalBinary"
+	case MsgSignalUtf8:
+		return "SignalUtf8"
+	case MsgSignalUtf16:
+		return "SignalUtf16"
+	case MsgRequestBinary:
+		return "RequestBinary"
+	case MsgRequestUtf8:
+		return "RequestUtf8"
+	case MsgRequestUtf16:
+		return "RequestUtf16"
+	case MsgServerRequestBinary:
+		return "ServerRequestBinary"
+	case MsgServerRequestUtf8:
+		return "ServerRequestUtf8"
+	case MsgServerRequestUtf16:
+		return "ServerRequestUtf16"
+	case MsgReplyBinary:
+		return "ReplyBinary"
+	case MsgReplyUtf8:
+		return "ReplyUtf8"
+	case MsgReplyUtf16:
+		return "ReplyUtf16"
+	case MsgClientReplyBinary:
+		return "ClientReplyBinary"
+	case MsgClientReplyUtf8:
+		return "ClientReplyUtf8"
+	case MsgClientReplyUtf16:
+		return "ClientReplyUtf16"
+	}
+	return "Unknown"
+}
@@ -0,0 +1,68 @@
+package message
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// parseTypeConstants parses message.go and returns the values
+// of all message type constants indexed by their names
+func parseTypeConstants(t *testing.T) map[string]byte {
+	file, err := parser.ParseFile(token.NewFileSet(), "message.go", nil, 0)
+	require.NoError(t, err)
+
+	constants := make(map[string]byte)
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			for i, name := range valueSpec.Names {
+				// Only consider constants of the form MsgX = byte(n)
+				call, ok := valueSpec.Values[i].(*ast.CallExpr)
+				if !ok || !strings.HasPrefix(name.Name, "Msg") {
+					continue
+				}
+				fun, ok := call.Fun.(*ast.Ident)
+				if !ok || fun.Name != "byte" {
+					continue
+				}
+				lit := call.Args[0].(*ast.BasicLit)
+				value, err := strconv.ParseUint(lit.Value, 10, 8)
+				require.NoError(t, err)
+				constants[name.Name] = byte(value)
+			}
+		}
+	}
+	return constants
+}
+
+// TestTypeConstantsNoCollision tests that no two message type constants
+// share the same value and that all of them are listed in Types
+func TestTypeConstantsNoCollision(t *testing.T) {
+	constants := parseTypeConstants(t)
+	require.Len(t, constants, len(Types))
+
+	names := make(map[byte]string, len(constants))
+	for name, value := range constants {
+		if other, exists := names[value]; exists {
+			t.Fatalf("%s collides with %s (%d)", name, other, value)
+		}
+		names[value] = name
+		require.Contains(t, Types, Type(value))
+		require.Equal(t, "Msg"+Type(value).String(), name)
+	}
+}
+
+// TestTypeStringUnknown tests the String method of undefined message types
+func TestTypeStringUnknown(t *testing.T) {
+	require.Equal(t, "Unknown", Type(200).String())
+}
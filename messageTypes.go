@@ -0,0 +1,49 @@
+package webwire
+
+import msg "github.com/qbeon/webwire-go/message"
+
+// MessageType represents the type of a protocol message.
+// It aliases message.Type which is the authoritative
+// definition of all message types
+type MessageType = msg.Type
+
+// Message type aliases of the constants defined in the message package
+const (
+	MsgErrorReply             = msg.MsgErrorReply
+	MsgReplyShutdown          = msg.MsgReplyShutdown
+	MsgInternalError          = msg.MsgInternalError
+	MsgSessionNotFound        = msg.MsgSessionNotFound
+	MsgMaxSessConnsReached    = msg.MsgMaxSessConnsReached
+	MsgSessionsDisabled       = msg.MsgSessionsDisabled
+	MsgReplyProtocolError     = msg.MsgReplyProtocolError
+	MsgReplyUnauthenticated   = msg.MsgReplyUnauthenticated
+	MsgErrorReplyPayload      = msg.MsgErrorReplyPayload
+	MsgReplyPayloadTooLarge   = msg.MsgReplyPayloadTooLarge
+	MsgReplyRateLimited       = msg.MsgReplyRateLimited
+	MsgReplyObserver          = msg.MsgReplyObserver
+	MsgSessionCreated         = msg.MsgSessionCreated
+	MsgSessionClosed          = msg.MsgSessionClosed
+	MsgHandshake              = msg.MsgHandshake
+	MsgSessionInfoUpdated     = msg.MsgSessionInfoUpdated
+	MsgSessionUpdated         = msg.MsgSessionUpdated
+	MsgCloseSession           = msg.MsgCloseSession
+	MsgRestoreSession         = msg.MsgRestoreSession
+	MsgRestoreSessionVerified = msg.MsgRestoreSessionVerified
+	MsgClientErrorReply       = msg.MsgClientErrorReply
+	MsgClientInternalError    = msg.MsgClientInternalError
+	MsgSignalBinary           = msg.MsgSignalBinary
+	MsgSignalUtf8             = msg.MsgSignalUtf8
+	MsgSignalUtf16            = msg.MsgSignalUtf16
+	MsgRequestBinary          = msg.MsgRequestBinary
+	MsgRequestUtf8            = msg.MsgRequestUtf8
+	MsgRequestUtf16           = msg.MsgRequestUtf16
+	MsgServerRequestBinary    = msg.MsgServerRequestBinary
+	MsgServerRequestUtf8      = msg.MsgServerRequestUtf8
+	MsgServerRequestUtf16     = msg.MsgServerRequestUtf16
+	MsgReplyBinary            = msg.MsgReplyBinary
+	MsgReplyUtf8              = msg.MsgReplyUtf8
+	MsgReplyUtf16             = msg.MsgReplyUtf16
+	MsgClientReplyBinary      = msg.MsgClientReplyBinary
+	MsgClientReplyUtf8        = msg.MsgClientReplyUtf8
+	MsgClientReplyUtf16       = msg.MsgClientReplyUtf16
+)
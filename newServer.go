@@ -8,7 +8,11 @@ import (
 )
 
 // NewServer creates a new headed WebWire server instance
-// with a built-in HTTP server hosting it
+// with a built-in HTTP server hosting it.
+// The built-in HTTP server serves TLS if either a TLS configuration
+// or a certificate and key file pair is provided.
+// Returns a ListenErr error if the server can't listen
+// on the configured address, for example if it's already in use
 func NewServer(
 	implementation ServerImplementation,
 	opts ServerOptions,
@@ -29,15 +33,25 @@ func NewServer(
 		Handler: srv,
 	}
 
+	// Prepare TLS configuration
+	srv.tlsConfig, err = newTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Determine final address
 	if opts.Address == "" {
-		opts.Address = ":http"
+		if srv.tlsConfig != nil {
+			opts.Address = ":https"
+		} else {
+			opts.Address = ":http"
+		}
 	}
 
 	// Initialize TCP/IP listener
 	srv.listener, err = net.Listen("tcp", opts.Address)
 	if err != nil {
-		return nil, fmt.Errorf("Failed setting up TCP/IP listener: %s", err)
+		return nil, NewListenErr(opts.Address, err)
 	}
 
 	srv.addr = srv.listener.Addr()
@@ -59,32 +73,66 @@ func NewHeadlessServer(
 
 	opts.SetDefaults()
 
+	if opts.Compression == Enabled &&
+		!isValidCompressionLevel(opts.CompressionLevel) {
+		return nil, fmt.Errorf(
+			"invalid compression level: %d",
+			opts.CompressionLevel,
+		)
+	}
+
 	sessionsEnabled := false
 	if opts.Sessions == Enabled {
 		sessionsEnabled = true
 	}
 
+	connectionsLock := &sync.Mutex{}
+
 	return &server{
-		impl:              implementation,
-		sessionManager:    opts.SessionManager,
-		sessionKeyGen:     opts.SessionKeyGenerator,
-		sessionInfoParser: opts.SessionInfoParser,
+		impl:                   implementation,
+		sessionManager:         opts.SessionManager,
+		sessionManagerLock:     &sync.RWMutex{},
+		sessionKeyGen:          opts.SessionKeyGenerator,
+		sessionInfoParser:      opts.SessionInfoParser,
+		sessionRestoreVerifier: opts.SessionRestoreVerifier,
 
 		// State
-		addr:            nil,
-		options:         opts,
-		shutdown:        false,
-		shutdownRdy:     make(chan bool),
-		currentOps:      0,
-		opsLock:         &sync.Mutex{},
-		connections:     make([]*connection, 0),
-		connectionsLock: &sync.Mutex{},
-		sessionsEnabled: sessionsEnabled,
-		sessionRegistry: newSessionRegistry(opts.MaxSessionConnections),
+		addr:               nil,
+		options:            opts,
+		shutdown:           false,
+		shutdownRdy:        make(chan bool),
+		currentOps:         0,
+		opsLock:            &sync.Mutex{},
+		connections:        make([]*connection, 0),
+		connectionsLock:    connectionsLock,
+		connectionsChanged: sync.NewCond(connectionsLock),
+		sessionsEnabled:    sessionsEnabled,
+		sessionRegistry: newSessionRegistry(
+			opts.MaxSessionConnections,
+			opts.UnlimitedObservers == Enabled,
+		),
+		pendingRequests: newPendingRequestRegistry(),
 
 		// Internals
-		connUpgrader: newConnUpgrader(),
-		warnLog:      opts.WarnLog,
-		errorLog:     opts.ErrorLog,
+		requestHandler: chainRequestMiddleware(
+			implementation.OnRequest,
+			opts.RequestMiddleware,
+		),
+		connUpgrader: newConnUpgrader(
+			opts.MaxMessageSize,
+			opts.CheckOrigin,
+			compression{
+				enabled:   opts.Compression == Enabled,
+				level:     opts.CompressionLevel,
+				threshold: opts.CompressionThreshold,
+			},
+			chunking{
+				size:     opts.WriteChunkSize,
+				timeout:  opts.WriteChunkTimeout,
+				progress: opts.WriteProgress,
+			},
+		),
+		warnLog:  opts.WarnLog,
+		errorLog: opts.ErrorLog,
 	}, nil
 }
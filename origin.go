@@ -0,0 +1,25 @@
+package webwire
+
+import (
+	"net/http"
+	"strings"
+)
+
+// AllowedOrigins returns an origin checker for ServerOptions.CheckOrigin
+// accepting upgrade requests from the given origins only
+// (such as "https://example.com"). Requests without an Origin header
+// are accepted because they don't originate from browsers
+func AllowedOrigins(origins []string) func(r *http.Request) bool {
+	allowed := make(map[string]struct{}, len(origins))
+	for _, origin := range origins {
+		allowed[strings.ToLower(origin)] = struct{}{}
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		_, isAllowed := allowed[strings.ToLower(origin)]
+		return isAllowed
+	}
+}
@@ -0,0 +1,44 @@
+package payload
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+)
+
+// Utf16ByteOrder defines the byte order of UTF16 code units
+// in UTF16 encoded payloads. It defaults to little-endian
+// which is the byte order of the Uint16Array based UTF16 encoding used by
+// browser clients on all common platforms and the byte order
+// Payload.Utf8 has always decoded. Peers exchanging UTF16 payloads
+// must agree on the byte order, it must thus only be changed
+// during initialization before any payloads are encoded or decoded
+var Utf16ByteOrder binary.ByteOrder = binary.LittleEndian
+
+// EncodeUtf16 encodes the given string into its UTF16 wire representation
+// using the given byte order. Characters outside the basic multilingual plane
+// are encoded as surrogate pairs
+func EncodeUtf16(str string, order binary.ByteOrder) []byte {
+	units := utf16.Encode([]rune(str))
+	data := make([]byte, len(units)*2)
+	for i, unit := range units {
+		order.PutUint16(data[i*2:], unit)
+	}
+	return data
+}
+
+// DecodeUtf16 decodes the given UTF16 wire representation
+// using the given byte order. Returns an error if the data
+// consists of an odd number of bytes
+func DecodeUtf16(data []byte, order binary.ByteOrder) (string, error) {
+	if len(data)%2 != 0 {
+		return "", fmt.Errorf(
+			"Cannot convert invalid UTF16 payload data to UTF8",
+		)
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[i*2:])
+	}
+	return string(utf16.Decode(units)), nil
+}
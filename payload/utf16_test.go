@@ -0,0 +1,57 @@
+package payload
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestUtf16RoundTrip tests encoding and decoding a string
+// containing multi-byte and surrogate pair characters
+// in both byte orders
+func TestUtf16RoundTrip(t *testing.T) {
+	str := "ABC ёжз φπμλβωϘ 𝄞😀"
+	orders := []binary.ByteOrder{binary.LittleEndian, binary.BigEndian}
+	for _, order := range orders {
+		t.Run(order.String(), func(t *testing.T) {
+			encoded := EncodeUtf16(str, order)
+			// 16 BMP characters and 2 surrogate pairs
+			require.Len(t, encoded, (16+2*2)*2)
+
+			decoded, err := DecodeUtf16(encoded, order)
+			require.NoError(t, err)
+			require.Equal(t, str, decoded)
+		})
+	}
+}
+
+// TestUtf16ByteOrder tests whether the byte order of encoded UTF16
+// code units is respected
+func TestUtf16ByteOrder(t *testing.T) {
+	require.Equal(t,
+		[]byte{0x73, 0x00, 0x3D, 0xD8, 0x00, 0xDE},
+		EncodeUtf16("s😀", binary.LittleEndian),
+	)
+	require.Equal(t,
+		[]byte{0x00, 0x73, 0xD8, 0x3D, 0xDE, 0x00},
+		EncodeUtf16("s😀", binary.BigEndian),
+	)
+}
+
+// TestConvertUtf16ToUtf8ByteOrder tests whether the Utf8() payload
+// conversion method respects the configured UTF16 byte order
+func TestConvertUtf16ToUtf8ByteOrder(t *testing.T) {
+	defer func(order binary.ByteOrder) {
+		Utf16ByteOrder = order
+	}(Utf16ByteOrder)
+	Utf16ByteOrder = binary.BigEndian
+
+	payload := Payload{
+		Encoding: Utf16,
+		Data:     []byte{0, 115, 0, 97, 0, 109, 0, 112, 0, 108, 0, 101},
+	}
+	result, err := payload.Utf8()
+	require.NoError(t, err)
+	require.Equal(t, "sample", result)
+}
@@ -0,0 +1,54 @@
+package webwire
+
+import (
+	"sort"
+	"sync"
+)
+
+// pendingRequestRegistry represents a thread safe registry of the names
+// of all requests currently processed by the request handler
+type pendingRequestRegistry struct {
+	lock     sync.Mutex
+	registry map[string]uint
+}
+
+// newPendingRequestRegistry returns a new pending request registry instance
+func newPendingRequestRegistry() *pendingRequestRegistry {
+	return &pendingRequestRegistry{
+		lock:     sync.Mutex{},
+		registry: make(map[string]uint),
+	}
+}
+
+// register registers a pending request by the given name
+func (reg *pendingRequestRegistry) register(name string) {
+	reg.lock.Lock()
+	reg.registry[name]++
+	reg.lock.Unlock()
+}
+
+// deregister removes a pending request by the given name
+// from the registry once its handler returned
+func (reg *pendingRequestRegistry) deregister(name string) {
+	reg.lock.Lock()
+	if reg.registry[name] > 1 {
+		reg.registry[name]--
+	} else {
+		delete(reg.registry, name)
+	}
+	reg.lock.Unlock()
+}
+
+// names returns the sorted names of all currently pending requests.
+// Names of concurrently pending requests of the same name
+// are listed only once
+func (reg *pendingRequestRegistry) names() []string {
+	reg.lock.Lock()
+	names := make([]string, 0, len(reg.registry))
+	for name := range reg.registry {
+		names = append(names, name)
+	}
+	reg.lock.Unlock()
+	sort.Strings(names)
+	return names
+}
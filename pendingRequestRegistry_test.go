@@ -0,0 +1,49 @@
+package webwire
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCallRequestHandlerPendingRequests tests whether requests are registered
+// as pending only while they're handled and only if they're reported
+func TestCallRequestHandlerPendingRequests(t *testing.T) {
+	srv := &server{pendingRequests: newPendingRequestRegistry()}
+	var pending []string
+	srv.requestHandler = func(
+		_ context.Context,
+		_ Connection,
+		_ Message,
+	) (Payload, error) {
+		pending = srv.pendingRequests.names()
+		return nil, nil
+	}
+
+	// Expect requests to not be registered if they're not reported
+	srv.options.ReportPendingRequests = Disabled
+	_, err := srv.callRequestHandler(context.Background(), nil, "req", nil)
+	require.NoError(t, err)
+	require.Len(t, pending, 0)
+
+	// Expect requests to be registered while they're handled
+	srv.options.ReportPendingRequests = Enabled
+	_, err = srv.callRequestHandler(context.Background(), nil, "req", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"req"}, pending)
+	require.Len(t, srv.pendingRequests.names(), 0)
+
+	// Expect requests to be deregistered even if the handler panics
+	srv.requestHandler = func(
+		_ context.Context,
+		_ Connection,
+		_ Message,
+	) (Payload, error) {
+		panic("handler failure")
+	}
+	require.Panics(t, func() {
+		srv.callRequestHandler(context.Background(), nil, "req", nil)
+	})
+	require.Len(t, srv.pendingRequests.names(), 0)
+}
@@ -0,0 +1,224 @@
+package webwire
+
+import (
+	"fmt"
+	"time"
+)
+
+// RedisClient defines the subset of Redis commands
+// used by the Redis session manager.
+// It's meant to be implemented by a thin adapter around a Redis client
+// library such as go-redis:
+//
+//	func (a adapter) Get(key string) ([]byte, bool, error) {
+//		value, err := a.client.Get(key).Bytes()
+//		if err == redis.Nil {
+//			return nil, false, nil
+//		}
+//		return value, err == nil, err
+//	}
+//
+//	func (a adapter) SetIfExists(
+//		key string,
+//		value []byte,
+//		ttl time.Duration,
+//	) (bool, error) {
+//		return a.client.SetXX(key, value, ttl).Result()
+//	}
+type RedisClient interface {
+	// Get must return the value stored under the given key.
+	// exists must be false and err must be nil if the key doesn't exist
+	Get(key string) (value []byte, exists bool, err error)
+
+	// Set must store the given value under the given key
+	// overwriting any existing value (SET key value PX ttl).
+	// The key must expire after the given ttl unless it's zero
+	Set(key string, value []byte, ttl time.Duration) error
+
+	// SetIfExists must store the given value under the given key only if
+	// the key already exists (SET key value PX ttl XX) and report whether
+	// the value was stored. The key must expire after the given ttl
+	// unless it's zero
+	SetIfExists(
+		key string,
+		value []byte,
+		ttl time.Duration,
+	) (stored bool, err error)
+
+	// Del must delete the given key
+	// and must not return an error if the key doesn't exist
+	Del(key string) error
+}
+
+// RedisSessionManager represents a session manager implementation
+// using Redis as a persistent storage. It allows multiple server instances
+// to share the same sessions. Sessions are stored in the same JSON layout
+// used by the session files of the DefaultSessionManager.
+// Expiring sessions are stored with a Redis TTL to be evicted by Redis
+// once they expire
+type RedisSessionManager struct {
+	client    RedisClient
+	keyPrefix string
+}
+
+// NewRedisSessionManager constructs a new Redis session manager instance
+// storing sessions under the given key prefix.
+// The key prefix defaults to "wwrsess:" if it's empty
+func NewRedisSessionManager(
+	client RedisClient,
+	keyPrefix string,
+) *RedisSessionManager {
+	if client == nil {
+		panic(fmt.Errorf("Redis session manager requires a client, got nil"))
+	}
+	if len(keyPrefix) < 1 {
+		keyPrefix = "wwrsess:"
+	}
+	return &RedisSessionManager{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}
+}
+
+// redisKey generates the Redis key of a session given the session key
+func (mng *RedisSessionManager) redisKey(sessionKey string) string {
+	return mng.keyPrefix + sessionKey
+}
+
+// ttl returns the Redis TTL of the given session file
+// which is zero if the session doesn't expire.
+// Returns false if the session has already expired
+func (mng *RedisSessionManager) ttl(file *sessionFile) (time.Duration, bool) {
+	if file.Expiration == nil {
+		return 0, true
+	}
+	ttl := file.Expiration.Sub(time.Now())
+	if ttl <= 0 {
+		return 0, false
+	}
+	return ttl, true
+}
+
+// save writes the given session file under the given session key.
+// Only overwrites an existing session if onlyExisting is true
+// and returns false if the session doesn't exist or has already expired
+func (mng *RedisSessionManager) save(
+	sessionKey string,
+	file *sessionFile,
+	onlyExisting bool,
+) (bool, error) {
+	ttl, valid := mng.ttl(file)
+	if !valid {
+		return false, nil
+	}
+	encoded, err := file.marshal()
+	if err != nil {
+		return false, fmt.Errorf("Couldn't marshal session: %s", err)
+	}
+	redisKey := mng.redisKey(sessionKey)
+	if onlyExisting {
+		stored, err := mng.client.SetIfExists(redisKey, encoded, ttl)
+		if err != nil {
+			return false, fmt.Errorf("Couldn't store session: %s", err)
+		}
+		return stored, nil
+	}
+	if err := mng.client.Set(redisKey, encoded, ttl); err != nil {
+		return false, fmt.Errorf("Couldn't store session: %s", err)
+	}
+	return true, nil
+}
+
+// sessionFileOf returns the session file of the session of the given
+// connection
+func sessionFileOf(conn Connection) *sessionFile {
+	sess := conn.Session()
+	return &sessionFile{
+		Creation:   sess.Creation,
+		LastLookup: sess.LastLookup,
+		Expiration: expirationField(sess.Expiration),
+		Info:       SessionInfoToVarMap(sess.Info),
+	}
+}
+
+// OnSessionCreated implements the session manager interface.
+// It stores the created session under the session key
+func (mng *RedisSessionManager) OnSessionCreated(conn Connection) error {
+	_, err := mng.save(conn.SessionKey(), sessionFileOf(conn), false)
+	return err
+}
+
+// OnSessionLookup implements the session manager interface.
+// It loads the session stored under the given key and updates
+// its last lookup field. Returns nil if the session doesn't exist
+// or was deleted before its last lookup field was updated.
+// Expired sessions not yet evicted by Redis are deleted
+// and reported as not found
+func (mng *RedisSessionManager) OnSessionLookup(key string) (
+	SessionLookupResult,
+	error,
+) {
+	encoded, exists, err := mng.client.Get(mng.redisKey(key))
+	if err != nil {
+		return nil, fmt.Errorf("Unexpected error during session lookup: %s", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	var file sessionFile
+	if err := file.unmarshal(encoded); err != nil {
+		return nil, fmt.Errorf("Couldn't parse session: %s", err)
+	}
+
+	// Lazily delete the session if it has expired
+	now := time.Now().UTC()
+	if file.isExpired(now) {
+		if err := mng.client.Del(mng.redisKey(key)); err != nil {
+			return nil, fmt.Errorf("Couldn't delete expired session: %s", err)
+		}
+		return nil, nil
+	}
+
+	// Update last lookup unless the session was deleted in the meantime
+	stored, err := mng.save(key, &sessionFile{
+		Creation:   file.Creation,
+		LastLookup: now,
+		Expiration: file.Expiration,
+		Info:       file.Info,
+	}, true)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't update last lookup field: %s", err)
+	}
+	if !stored {
+		return nil, nil
+	}
+
+	return NewExpiringSessionLookupResult(
+		file.Creation,
+		file.LastLookup,
+		file.expirationTime(),
+		file.Info,
+	), nil
+}
+
+// OnSessionClosed implements the session manager interface.
+// It deletes the session stored under the given key.
+// Closing an already deleted or evicted session is not an error
+func (mng *RedisSessionManager) OnSessionClosed(sessionKey string) error {
+	if err := mng.client.Del(mng.redisKey(sessionKey)); err != nil {
+		return fmt.Errorf(
+			"Unexpected error during session destruction: %s",
+			err,
+		)
+	}
+	return nil
+}
+
+// OnSessionUpdated implements the session manager interface.
+// It overwrites the session stored under the session key
+// with the updated session unless the session was deleted in the meantime
+func (mng *RedisSessionManager) OnSessionUpdated(conn Connection) error {
+	_, err := mng.save(conn.SessionKey(), sessionFileOf(conn), true)
+	return err
+}
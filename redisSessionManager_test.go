@@ -0,0 +1,151 @@
+package webwire
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testRedisClient implements the RedisClient interface
+// storing values and their TTLs in memory
+type testRedisClient struct {
+	values map[string][]byte
+	ttls   map[string]time.Duration
+
+	// afterGet is invoked after each Get if it's defined
+	afterGet func(key string)
+}
+
+func newTestRedisClient() *testRedisClient {
+	return &testRedisClient{
+		values: make(map[string][]byte),
+		ttls:   make(map[string]time.Duration),
+	}
+}
+
+func (clt *testRedisClient) Get(key string) ([]byte, bool, error) {
+	value, exists := clt.values[key]
+	if clt.afterGet != nil {
+		clt.afterGet(key)
+	}
+	return value, exists, nil
+}
+
+func (clt *testRedisClient) Set(
+	key string,
+	value []byte,
+	ttl time.Duration,
+) error {
+	clt.values[key] = value
+	clt.ttls[key] = ttl
+	return nil
+}
+
+func (clt *testRedisClient) SetIfExists(
+	key string,
+	value []byte,
+	ttl time.Duration,
+) (bool, error) {
+	if _, exists := clt.values[key]; !exists {
+		return false, nil
+	}
+	return true, clt.Set(key, value, ttl)
+}
+
+func (clt *testRedisClient) Del(key string) error {
+	delete(clt.values, key)
+	return nil
+}
+
+// TestRedisSessionManager tests creating, looking up and closing sessions
+// using the Redis session manager
+func TestRedisSessionManager(t *testing.T) {
+	client := newTestRedisClient()
+	mng := NewRedisSessionManager(client, "prefix:")
+
+	// Create a session
+	sess := NewSession(
+		GenericSessionInfoParser(map[string]interface{}{"field": "value"}),
+		func() string { return "testkey" },
+	)
+	conn := newConnection(&testSignalSocket{}, "", nil, nil)
+	conn.session = &sess
+	require.NoError(t, mng.OnSessionCreated(conn))
+	require.Contains(t, client.values, "prefix:testkey")
+	require.Equal(t, time.Duration(0), client.ttls["prefix:testkey"])
+
+	// Look the session up
+	result, err := mng.OnSessionLookup("testkey")
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.True(t, sess.Creation.Equal(result.Creation()))
+	require.Equal(t, "value", result.Info()["field"])
+
+	// Expect the last lookup to be updated
+	result, err = mng.OnSessionLookup("testkey")
+	require.NoError(t, err)
+	require.False(t, result.LastLookup().Before(sess.LastLookup))
+
+	// Expect missing sessions to be reported as not found
+	result, err = mng.OnSessionLookup("inexistent")
+	require.NoError(t, err)
+	require.Nil(t, result)
+
+	// Close the session twice
+	require.NoError(t, mng.OnSessionClosed("testkey"))
+	require.NoError(t, mng.OnSessionClosed("testkey"))
+	require.NotContains(t, client.values, "prefix:testkey")
+
+	result, err = mng.OnSessionLookup("testkey")
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
+
+// TestRedisSessionManagerTTL tests whether expiring sessions are stored
+// with a Redis TTL
+func TestRedisSessionManagerTTL(t *testing.T) {
+	client := newTestRedisClient()
+	mng := NewRedisSessionManager(client, "")
+
+	sess := NewSession(nil, func() string { return "testkey" })
+	sess.Expiration = sess.Creation.Add(time.Hour)
+	conn := newConnection(&testSignalSocket{}, "", nil, nil)
+	conn.session = &sess
+	require.NoError(t, mng.OnSessionCreated(conn))
+
+	ttl := client.ttls["wwrsess:testkey"]
+	require.True(t, ttl > 0 && ttl <= time.Hour)
+
+	// Expect the TTL to be kept on lookup
+	result, err := mng.OnSessionLookup("testkey")
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	ttl = client.ttls["wwrsess:testkey"]
+	require.True(t, ttl > 0 && ttl <= time.Hour)
+}
+
+// TestRedisSessionManagerNoResurrection tests whether a session deleted
+// during its lookup or before its update isn't stored again
+func TestRedisSessionManagerNoResurrection(t *testing.T) {
+	client := newTestRedisClient()
+	mng := NewRedisSessionManager(client, "")
+
+	sess := NewSession(nil, func() string { return "testkey" })
+	conn := newConnection(&testSignalSocket{}, "", nil, nil)
+	conn.session = &sess
+	require.NoError(t, mng.OnSessionCreated(conn))
+
+	// Delete the session right after it was read during the lookup
+	client.afterGet = func(key string) {
+		require.NoError(t, mng.OnSessionClosed("testkey"))
+	}
+	result, err := mng.OnSessionLookup("testkey")
+	require.NoError(t, err)
+	require.Nil(t, result)
+	require.NotContains(t, client.values, "wwrsess:testkey")
+
+	// Expect updates of deleted sessions to be discarded
+	require.NoError(t, mng.OnSessionUpdated(conn))
+	require.NotContains(t, client.values, "wwrsess:testkey")
+}
@@ -33,7 +33,9 @@ type Request struct {
 	// timeout represents the configured timeout duration of this request
 	timeout time.Duration
 
-	// reply represents a channel for asynchronous reply handling
+	// reply represents a channel for asynchronous reply handling.
+	// It's buffered to not block the delivery of replies
+	// that are no longer awaited
 	reply chan reply
 }
 
@@ -46,10 +48,16 @@ func (req *Request) Identifier() RequestIdentifier {
 // until either the reply is fulfilled or failed, the request timed out
 // a user-defined deadline was exceeded or the request was prematurely canceled.
 // The timer is started when AwaitReply is called.
+// If the timeout is zero then AwaitReply waits indefinitely
+// until either the reply is received or the context is done.
 func (req *Request) AwaitReply(ctx context.Context) (webwire.Payload, error) {
-	// Start timeout timer
-	timeoutTimer := time.NewTimer(req.timeout)
-	defer timeoutTimer.Stop()
+	// Start timeout timer if a timeout is defined
+	var timeout <-chan time.Time
+	if req.timeout > 0 {
+		timeoutTimer := time.NewTimer(req.timeout)
+		defer timeoutTimer.Stop()
+		timeout = timeoutTimer.C
+	}
 
 	// Block until either deadline exceeded, canceled,
 	// timed out or reply received
@@ -57,12 +65,11 @@ func (req *Request) AwaitReply(ctx context.Context) (webwire.Payload, error) {
 	case <-ctx.Done():
 		req.manager.deregister(req.identifier)
 		return nil, webwire.TranslateContextError(ctx.Err())
-	case <-timeoutTimer.C:
+	case <-timeout:
 		req.manager.deregister(req.identifier)
 		return &webwire.EncodedPayload{},
 			webwire.NewTimeoutErr(fmt.Errorf("timed out"))
 	case reply := <-req.reply:
-		timeoutTimer.Stop()
 		if reply.Error != nil {
 			return nil, reply.Error
 		}
@@ -78,30 +85,50 @@ func (req *Request) AwaitReply(ctx context.Context) (webwire.Payload, error) {
 	}
 }
 
+// Discard deregisters the request without awaiting its reply.
+// It's used to release requests that couldn't be sent
+func (req *Request) Discard() {
+	req.manager.deregister(req.identifier)
+}
+
 // RequestManager manages and keeps track of outgoing pending requests
 type RequestManager struct {
-	lastID uint64
-	lock   sync.RWMutex
+	lastID     uint64
+	lock       sync.RWMutex
+	maxPending uint
 
 	// pending represents an indexed list of all pending requests
 	pending map[RequestIdentifier]*Request
 }
 
-// NewRequestManager constructs and returns a new instance of a RequestManager
-func NewRequestManager() RequestManager {
+// NewRequestManager constructs and returns a new instance of a RequestManager.
+// maxPending limits the number of concurrently pending requests,
+// the number of pending requests is unlimited if maxPending is zero
+func NewRequestManager(maxPending uint) RequestManager {
 	return RequestManager{
-		lastID:  0,
-		lock:    sync.RWMutex{},
-		pending: make(map[RequestIdentifier]*Request),
+		lastID:     0,
+		lock:       sync.RWMutex{},
+		maxPending: maxPending,
+		pending:    make(map[RequestIdentifier]*Request),
 	}
 }
 
 // Create creates and registers a new request.
 // Create doesn't start the timeout timer,
-// this is done in the subsequent request.AwaitReply
-func (manager *RequestManager) Create(timeout time.Duration) *Request {
+// this is done in the subsequent request.AwaitReply.
+// Returns a webwire.TooManyPendingErr error if the maximum number
+// of pending requests is reached
+func (manager *RequestManager) Create(
+	timeout time.Duration,
+) (*Request, error) {
 	manager.lock.Lock()
 
+	if manager.maxPending > 0 &&
+		uint(len(manager.pending)) >= manager.maxPending {
+		manager.lock.Unlock()
+		return nil, webwire.TooManyPendingErr{}
+	}
+
 	// Generate unique request identifier by incrementing the last assigned id
 	manager.lastID++
 	var identifier RequestIdentifier
@@ -113,7 +140,7 @@ func (manager *RequestManager) Create(timeout time.Duration) *Request {
 		manager,
 		identifier,
 		timeout,
-		make(chan reply),
+		make(chan reply, 1),
 	}
 
 	// Register the newly created request
@@ -121,7 +148,7 @@ func (manager *RequestManager) Create(timeout time.Duration) *Request {
 
 	manager.lock.Unlock()
 
-	return newRequest
+	return newRequest, nil
 }
 
 // deregister deregisters the given clients session from the list
@@ -132,28 +159,41 @@ func (manager *RequestManager) deregister(identifier RequestIdentifier) {
 	manager.lock.Unlock()
 }
 
+// take deregisters and returns the request associated
+// with the given identifier if it's still pending.
+// Looking up and deregistering the request atomically ensures
+// a reply is delivered at most once
+func (manager *RequestManager) take(
+	identifier RequestIdentifier,
+) (*Request, bool) {
+	manager.lock.Lock()
+	req, exists := manager.pending[identifier]
+	delete(manager.pending, identifier)
+	manager.lock.Unlock()
+	return req, exists
+}
+
 // Fulfill fulfills the request associated with the given request identifier
 // with the provided reply payload.
 // Returns true if a pending request was fulfilled and deregistered,
-// otherwise returns false
+// otherwise returns false. Replies to requests that were already
+// canceled or timed out are discarded
 func (manager *RequestManager) Fulfill(
 	identifier RequestIdentifier,
 	payload pld.Payload,
 ) bool {
-	manager.lock.RLock()
-	req, exists := manager.pending[identifier]
-	manager.lock.RUnlock()
+	// Deregister the request before delivering the reply
+	// to have it released by the time the reply is awaited
+	req, exists := manager.take(identifier)
 	if !exists {
 		return false
 	}
-
 	req.reply <- reply{
 		Reply: &webwire.EncodedPayload{
 			Payload: payload,
 		},
 		Error: nil,
 	}
-	manager.deregister(identifier)
 	return true
 }
 
@@ -164,9 +204,7 @@ func (manager *RequestManager) Fail(
 	identifier RequestIdentifier,
 	err error,
 ) bool {
-	manager.lock.RLock()
-	req, exists := manager.pending[identifier]
-	manager.lock.RUnlock()
+	req, exists := manager.take(identifier)
 	if !exists {
 		return false
 	}
@@ -174,7 +212,6 @@ func (manager *RequestManager) Fail(
 		Reply: nil,
 		Error: err,
 	}
-	manager.deregister(identifier)
 	return true
 }
 
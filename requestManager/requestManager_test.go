@@ -0,0 +1,56 @@
+package requestmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFulfillNotAwaited tests fulfilling a request
+// that's no longer awaited expecting the reply to be discarded
+// without blocking the caller
+func TestFulfillNotAwaited(t *testing.T) {
+	manager := NewRequestManager(0)
+	req, err := manager.Create(0)
+	require.NoError(t, err)
+
+	fulfilled := make(chan bool, 1)
+	go func() {
+		fulfilled <- manager.Fulfill(req.Identifier(), pld.Payload{})
+	}()
+
+	select {
+	case ok := <-fulfilled:
+		require.True(t, ok)
+	case <-time.After(1 * time.Second):
+		t.Fatal("Fulfill blocked")
+	}
+	require.Equal(t, 0, manager.PendingRequests())
+
+	// Expect a late reply to a request to be discarded
+	require.False(t, manager.Fulfill(req.Identifier(), pld.Payload{}))
+}
+
+// TestFulfillCanceled tests fulfilling a canceled request
+// expecting the reply to be discarded
+func TestFulfillCanceled(t *testing.T) {
+	manager := NewRequestManager(1)
+	req, err := manager.Create(0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = req.AwaitReply(ctx)
+	require.Error(t, err)
+
+	// Expect the slot to be freed and the late reply to be discarded
+	require.Equal(t, 0, manager.PendingRequests())
+	require.False(t, manager.Fulfill(req.Identifier(), pld.Payload{}))
+	require.False(t, manager.Fail(req.Identifier(), context.Canceled))
+
+	_, err = manager.Create(0)
+	require.NoError(t, err)
+}
@@ -0,0 +1,14 @@
+package webwire
+
+// chainRequestMiddleware wraps the given request handler in the given
+// middleware. The first middleware is the outermost one and is thus
+// invoked first
+func chainRequestMiddleware(
+	handler RequestHandler,
+	middleware []RequestMiddleware,
+) RequestHandler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
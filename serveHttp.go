@@ -4,8 +4,14 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+
+	msg "github.com/qbeon/webwire-go/message"
 )
 
+// closeGoingAway is the WebSocket close code
+// sent to connections closed due to inactivity
+const closeGoingAway = 1001
+
 // ServeHTTP will make the server listen for incoming HTTP requests
 // eventually trying to upgrade them to WebSocket connections
 func (srv *server) ServeHTTP(
@@ -31,6 +37,18 @@ func (srv *server) ServeHTTP(
 		return
 	}
 
+	// Reject incoming connections when the maximum number
+	// of concurrent connections is reached
+	if !srv.reserveConnection() {
+		http.Error(
+			resp,
+			"Maximum number of concurrent connections reached",
+			http.StatusServiceUnavailable,
+		)
+		return
+	}
+	defer srv.releaseConnection()
+
 	connectionOptions := srv.impl.BeforeUpgrade(resp, req)
 
 	// Abort connection establishment if no options are provided
@@ -39,40 +57,76 @@ func (srv *server) ServeHTTP(
 	}
 
 	// Establish connection
+	upgradeStart := time.Now()
 	conn, err := srv.connUpgrader.Upgrade(resp, req)
 	if err != nil {
-		srv.errorLog.Print("Upgrade failed:", err)
+		srv.logError(
+			newErrorContext(ErrOpUpgrade, nil),
+			err,
+			"Upgrade failed: %s",
+			err,
+		)
 		return
 	}
 	defer conn.Close()
 
-	// Set ping/pong handlers
-	conn.OnPong(func(string) error {
+	if srv.options.Metrics != nil {
+		srv.options.Metrics.RecordUpgrade(
+			conn.RemoteAddr(),
+			time.Since(upgradeStart),
+		)
+	}
+
+	// Set ping/pong handlers extending the read deadline
+	// to close the connection when the heartbeat times out (if enabled)
+	if srv.options.Heartbeat == Enabled {
+		conn.OnPong(func(string) error {
+			if err := conn.SetReadDeadline(
+				time.Now().Add(srv.options.HeartbeatTimeout),
+			); err != nil {
+				return fmt.Errorf(
+					"Couldn't set read deadline in Pong handler: %s",
+					err,
+				)
+			}
+			return nil
+		})
+		conn.OnPing(func(string) error {
+			if err := conn.SetReadDeadline(
+				time.Now().Add(srv.options.HeartbeatTimeout),
+			); err != nil {
+				return fmt.Errorf(
+					"Couldn't set read deadline in Ping handler: %s",
+					err,
+				)
+			}
+			return nil
+		})
 		if err := conn.SetReadDeadline(
 			time.Now().Add(srv.options.HeartbeatTimeout),
 		); err != nil {
-			return fmt.Errorf(
-				"Couldn't set read deadline in Pong handler: %s",
+			srv.logError(
+				newErrorContext(ErrOpHeartbeat, nil),
 				err,
-			)
-		}
-		return nil
-	})
-	conn.OnPing(func(string) error {
-		if err := conn.SetReadDeadline(
-			time.Now().Add(srv.options.HeartbeatTimeout),
-		); err != nil {
-			return fmt.Errorf(
-				"Couldn't set read deadline in Ping handler: %s",
+				"Couldn't set read deadline: %s",
 				err,
 			)
+			return
 		}
-		return nil
-	})
-	if err := conn.SetReadDeadline(
-		time.Now().Add(srv.options.HeartbeatTimeout),
-	); err != nil {
-		srv.errorLog.Printf("Couldn't set read deadline: %s", err)
+	}
+
+	// Send the handshake before any other message to let the client
+	// know the connection is established
+	if err := conn.Write(msg.NewHandshakeMessage(
+		protocolVersion,
+		srv.sessionsEnabled,
+	)); err != nil {
+		srv.logError(
+			newErrorContext(ErrOpHandshake, nil),
+			err,
+			"Couldn't send handshake: %s",
+			err,
+		)
 		return
 	}
 
@@ -83,18 +137,51 @@ func (srv *server) ServeHTTP(
 		srv,
 		connectionOptions,
 	)
+	connection.info.ClientIdentifier = req.Header.Get(ClientIdentifierHeader)
+	connection.info.Observer = req.Header.Get(ObserverHeader) == "true"
 
 	srv.connectionsLock.Lock()
 	srv.connections = append(srv.connections, connection)
+	srv.connectionsChanged.Broadcast()
 	srv.connectionsLock.Unlock()
 
+	// Close the connection on any disconnect path (including panics)
+	// which deregisters it from the session registry
+	// as soon as all of its currently executed handlers returned
+	// and remove it from the list of connections
+	defer func() {
+		connection.Close()
+		srv.removeConnection(connection)
+	}()
+
 	// Call hook on successful connection
-	srv.impl.OnClientConnected(connection)
+	// and close the connection if it's rejected
+	if err := srv.impl.OnClientConnected(connection); err != nil {
+		if err := closeWithError(conn, err); err != nil {
+			srv.warnLog.Printf("Couldn't notify rejected client: %s", err)
+		}
+		return
+	}
+
+	// Close the connection when it doesn't send any messages
+	// for the duration of the idle timeout (if enabled)
+	var idleTimer *time.Timer
+	if srv.options.IdleTimeout > 0 {
+		idleTimer = time.AfterFunc(srv.options.IdleTimeout, func() {
+			if err := conn.CloseWithCode(
+				closeGoingAway,
+				"Idle timeout",
+			); err != nil {
+				srv.warnLog.Printf("Couldn't close idle connection: %s", err)
+			}
+		})
+		defer idleTimer.Stop()
+	}
 
 	// Start heartbeat sender (if enabled)
 	stopHeartbeat := make(chan struct{}, 1)
 	if srv.options.Heartbeat == Enabled {
-		go srv.heartbeat(conn, stopHeartbeat)
+		go srv.heartbeat(connection, stopHeartbeat)
 	}
 
 	for {
@@ -106,12 +193,18 @@ func (srv *server) ServeHTTP(
 			}
 
 			connection.Close()
-			srv.impl.OnClientDisconnected(connection)
+			srv.notifyConnectionsChanged()
+			srv.notifyClientDisconnected(connection)
 			break
 		}
 
+		// Reset the idle timeout on every incoming message
+		if idleTimer != nil {
+			idleTimer.Reset(srv.options.IdleTimeout)
+		}
+
 		// Parse & handle the message
-		go srv.handleMessage(connection, message)
+		srv.dispatchMessage(connection, message)
 	}
 
 	// Connection closed
@@ -2,24 +2,30 @@ package webwire
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
+	"strings"
 	"sync"
+	"time"
 )
 
-const protocolVersion = "1.4"
+const protocolVersion = "1.5"
 
 // server represents a headless WebWire server instance,
 // where headless means there's no HTTP server that's hosting it
 type server struct {
-	impl              ServerImplementation
-	httpServer        *http.Server
-	listener          net.Listener
-	sessionManager    SessionManager
-	sessionKeyGen     SessionKeyGenerator
-	sessionInfoParser SessionInfoParser
+	impl                   ServerImplementation
+	httpServer             *http.Server
+	listener               net.Listener
+	tlsConfig              *tls.Config
+	sessionManager         SessionManager
+	sessionManagerLock     *sync.RWMutex
+	sessionKeyGen          SessionKeyGenerator
+	sessionInfoParser      SessionInfoParser
+	sessionRestoreVerifier SessionRestoreVerifier
 
 	// State
 	addr            net.Addr
@@ -30,13 +36,21 @@ type server struct {
 	opsLock         *sync.Mutex
 	connectionsLock *sync.Mutex
 	connections     []*connection
-	sessionsEnabled bool
-	sessionRegistry *sessionRegistry
+	// connectionsNum is the number of currently accepted connections
+	// including connections that are still being upgraded
+	connectionsNum uint
+	// connectionsChanged is signaled whenever a connection
+	// is established or lost
+	connectionsChanged *sync.Cond
+	sessionsEnabled    bool
+	sessionRegistry    *sessionRegistry
+	pendingRequests    *pendingRequestRegistry
 
 	// Internals
-	connUpgrader ConnUpgrader
-	warnLog      *log.Logger
-	errorLog     *log.Logger
+	requestHandler RequestHandler
+	connUpgrader   ConnUpgrader
+	warnLog        *log.Logger
+	errorLog       *log.Logger
 }
 
 func (srv *server) shutdownHTTPServer() error {
@@ -51,10 +65,15 @@ func (srv *server) shutdownHTTPServer() error {
 
 // Run implements the Server interface
 func (srv *server) Run() error {
+	var listener net.Listener = tcpKeepAliveListener{
+		srv.listener.(*net.TCPListener),
+	}
+	if srv.tlsConfig != nil {
+		listener = tls.NewListener(listener, srv.tlsConfig)
+	}
+
 	// Launch HTTP server
-	if err := srv.httpServer.Serve(
-		tcpKeepAliveListener{srv.listener.(*net.TCPListener)},
-	); err != http.ErrServerClosed {
+	if err := srv.httpServer.Serve(listener); err != http.ErrServerClosed {
 		return fmt.Errorf("HTTP Server failure: %s", err)
 	}
 
@@ -66,19 +85,145 @@ func (srv *server) Addr() net.Addr {
 	return srv.addr
 }
 
+// Scheme implements the Server interface
+func (srv *server) Scheme() string {
+	if srv.tlsConfig != nil {
+		return "wss"
+	}
+	return "ws"
+}
+
 // Shutdown implements the Server interface
 func (srv *server) Shutdown() error {
+	start := time.Now()
+
+	// Mark the server as shutting down before awaiting the currently
+	// processed handlers. The flag is checked under the same lock
+	// the handlers are registered with, thus any handler registered
+	// after this point is rejected
 	srv.opsLock.Lock()
 	srv.shutdown = true
 	// Don't block if there's no currently processed operations
 	if srv.currentOps < 1 {
 		srv.opsLock.Unlock()
-		return srv.shutdownHTTPServer()
+		srv.sessionRegistry.closeWatchers()
+		return srv.completeShutdown(start)
 	}
 	srv.opsLock.Unlock()
+
+	// Report the requests blocking the graceful shutdown if enabled
+	if srv.options.ReportPendingRequests == Enabled {
+		if names := srv.pendingRequests.names(); len(names) > 0 {
+			srv.warnLog.Printf(
+				"Shutdown awaiting pending requests: %s",
+				strings.Join(names, ", "),
+			)
+		}
+	}
+
+	if srv.options.ShutdownProgress != nil {
+		go srv.reportShutdownProgress(start)
+	}
+
 	<-srv.shutdownRdy
 
-	return srv.shutdownHTTPServer()
+	srv.sessionRegistry.closeWatchers()
+	return srv.completeShutdown(start)
+}
+
+// reportShutdownProgress periodically reports the number of remaining
+// operations to the ShutdownProgress callback
+// until all operations are finished
+func (srv *server) reportShutdownProgress(start time.Time) {
+	ticker := time.NewTicker(srv.options.ShutdownProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-srv.shutdownRdy:
+			return
+		case <-ticker.C:
+			srv.opsLock.Lock()
+			remainingOps := int(srv.currentOps)
+			srv.opsLock.Unlock()
+			if remainingOps < 1 {
+				return
+			}
+			srv.options.ShutdownProgress(remainingOps, time.Since(start))
+		}
+	}
+}
+
+// completeShutdown shuts the HTTP server down
+// and reports the completion to the ShutdownProgress callback if any
+func (srv *server) completeShutdown(start time.Time) error {
+	err := srv.shutdownHTTPServer()
+	if srv.options.ShutdownProgress != nil {
+		srv.options.ShutdownProgress(0, time.Since(start))
+	}
+	return err
+}
+
+// maxSessionKeyAttempts defines the maximum number of attempts
+// to generate a session key that doesn't collide with any active session
+const maxSessionKeyAttempts = 8
+
+// newSession creates a new session carrying the given info
+// and registers the given connection with it moving the connection
+// out of its current session if any. The key generation is retried
+// if the generated key collides with the key of a currently active session.
+// Returns the number of connections left in the previous session
+// of the connection or -1 if it had none.
+// Returns an InvalidSessionKeyErr error if the generated key is invalid
+// and a SessionKeyCollisionErr error if no unique key
+// could be generated within maxSessionKeyAttempts attempts
+func (srv *server) newSession(
+	con *connection,
+	info SessionInfo,
+) (Session, int, error) {
+	for attempt := 0; attempt < maxSessionKeyAttempts; attempt++ {
+		key := srv.sessionKeyGen.Generate()
+		if !isValidSessionKey(key) {
+			return Session{}, -1, InvalidSessionKeyErr{Key: key}
+		}
+		session := NewSession(info, func() string { return key })
+
+		// Check for collisions and register atomically
+		// to never let concurrently created sessions share a key
+		remainingConns, registered := srv.sessionRegistry.registerNew(
+			con,
+			key,
+		)
+		if registered {
+			return session, remainingConns, nil
+		}
+		srv.warnLog.Printf(
+			"Generated session key collides with an active session "+
+				"(attempt %d/%d)",
+			attempt+1,
+			maxSessionKeyAttempts,
+		)
+	}
+	return Session{}, -1, SessionKeyCollisionErr{
+		Attempts: maxSessionKeyAttempts,
+	}
+}
+
+// SetSessionManager implements the Server interface
+func (srv *server) SetSessionManager(sessionManager SessionManager) {
+	if sessionManager == nil {
+		panic(fmt.Errorf("session manager must not be nil"))
+	}
+	srv.sessionManagerLock.Lock()
+	srv.sessionManager = sessionManager
+	srv.sessionManagerLock.Unlock()
+}
+
+// getSessionManager returns the currently used session manager
+func (srv *server) getSessionManager() SessionManager {
+	srv.sessionManagerLock.RLock()
+	sessionManager := srv.sessionManager
+	srv.sessionManagerLock.RUnlock()
+	return sessionManager
 }
 
 // ActiveSessionsNum implements the Server interface
@@ -86,6 +231,148 @@ func (srv *server) ActiveSessionsNum() int {
 	return srv.sessionRegistry.activeSessionsNum()
 }
 
+// WatchSessions implements the Server interface
+func (srv *server) WatchSessions() (<-chan SessionEvent, func()) {
+	return srv.sessionRegistry.watch()
+}
+
+// AnonymousConnectionsNum implements the Server interface
+func (srv *server) AnonymousConnectionsNum() int {
+	srv.connectionsLock.Lock()
+	defer srv.connectionsLock.Unlock()
+	active := make([]*connection, 0, len(srv.connections))
+	for _, connection := range srv.connections {
+		if connection.IsActive() {
+			active = append(active, connection)
+		}
+	}
+	return srv.sessionRegistry.unregisteredNum(active)
+}
+
+// AwaitConnections implements the Server interface
+func (srv *server) AwaitConnections(ctx context.Context, num int) error {
+	// Wake up the waiter when the context is done
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			srv.connectionsLock.Lock()
+			srv.connectionsChanged.Broadcast()
+			srv.connectionsLock.Unlock()
+		case <-done:
+		}
+	}()
+
+	srv.connectionsLock.Lock()
+	defer srv.connectionsLock.Unlock()
+	for srv.activeConnectionsNum() != num {
+		if err := ctx.Err(); err != nil {
+			return TranslateContextError(err)
+		}
+		srv.connectionsChanged.Wait()
+	}
+	return nil
+}
+
+// activeConnectionsNum returns the number of currently active connections.
+// Expects the connections lock to be held by the caller
+func (srv *server) activeConnectionsNum() int {
+	num := 0
+	for _, connection := range srv.connections {
+		if connection.IsActive() {
+			num++
+		}
+	}
+	return num
+}
+
+// reserveConnection reserves a slot for a new connection
+// returning false if the maximum number of concurrent connections is reached
+func (srv *server) reserveConnection() bool {
+	srv.connectionsLock.Lock()
+	defer srv.connectionsLock.Unlock()
+	if srv.options.MaxConcurrentConnections > 0 &&
+		srv.connectionsNum >= srv.options.MaxConcurrentConnections {
+		return false
+	}
+	srv.connectionsNum++
+	return true
+}
+
+// releaseConnection releases a connection slot
+// previously reserved by reserveConnection
+func (srv *server) releaseConnection() {
+	srv.connectionsLock.Lock()
+	srv.connectionsNum--
+	srv.connectionsLock.Unlock()
+}
+
+// removeConnection removes the given closed connection from the list
+// of connections and wakes up all goroutines awaiting connections
+func (srv *server) removeConnection(con *connection) {
+	srv.connectionsLock.Lock()
+	for i, current := range srv.connections {
+		if current == con {
+			srv.connections = append(
+				srv.connections[:i],
+				srv.connections[i+1:]...,
+			)
+			break
+		}
+	}
+	srv.connectionsChanged.Broadcast()
+	srv.connectionsLock.Unlock()
+}
+
+// notifyConnectionsChanged wakes up all goroutines awaiting connections
+func (srv *server) notifyConnectionsChanged() {
+	srv.connectionsLock.Lock()
+	srv.connectionsChanged.Broadcast()
+	srv.connectionsLock.Unlock()
+}
+
+// Stats implements the Server interface
+func (srv *server) Stats() ServerStats {
+	stats := ServerStats{
+		ActiveSessions: srv.sessionRegistry.activeSessionsNum(),
+	}
+
+	srv.opsLock.Lock()
+	stats.PendingOperations = int(srv.currentOps)
+	stats.ShuttingDown = srv.shutdown
+	srv.opsLock.Unlock()
+
+	srv.connectionsLock.Lock()
+	for _, connection := range srv.connections {
+		if !connection.IsActive() {
+			continue
+		}
+		stats.Connections++
+		if connection.HasSession() {
+			stats.AuthenticatedConnections++
+		} else {
+			stats.AnonymousConnections++
+		}
+	}
+	srv.connectionsLock.Unlock()
+
+	return stats
+}
+
+// Connections implements the Server interface
+func (srv *server) Connections() []Connection {
+	srv.connectionsLock.Lock()
+	defer srv.connectionsLock.Unlock()
+	list := make([]Connection, 0, len(srv.connections))
+	for _, connection := range srv.connections {
+		if connection.IsActive() {
+			list = append(list, connection)
+		}
+	}
+	return list
+}
+
 // SessionConnectionsNum implements the Server interface
 func (srv *server) SessionConnectionsNum(sessionKey string) int {
 	return srv.sessionRegistry.sessionConnectionsNum(sessionKey)
@@ -106,11 +393,157 @@ func (srv *server) SessionConnections(sessionKey string) []Connection {
 	return list
 }
 
+// SetMaxSessionConnections implements the Server interface
+func (srv *server) SetMaxSessionConnections(
+	sessionKey string,
+	max uint,
+) error {
+	if !srv.sessionRegistry.setMaxConns(sessionKey, max) {
+		return SessNotFoundErr{}
+	}
+	return nil
+}
+
+// SessionConnectionDetails implements the Server interface
+func (srv *server) SessionConnectionDetails(
+	sessionKey string,
+) []ConnectionDetails {
+	connections := srv.sessionRegistry.sessionConnections(sessionKey)
+	if connections == nil {
+		return nil
+	}
+	list := make([]ConnectionDetails, 0, len(connections))
+	for connection := range connections {
+		list = append(list, ConnectionDetails{
+			Connection: connection,
+			ClientInfo: connection.Info(),
+		})
+	}
+	return list
+}
+
+// SignalSession implements the Server interface
+func (srv *server) SignalSession(sessionKey, name string, payload Payload) (
+	results []SignalResult,
+	generalError error,
+) {
+	connections := srv.sessionRegistry.sessionConnections(sessionKey)
+	if connections == nil {
+		return nil, nil
+	}
+
+	results = make([]SignalResult, 0, len(connections))
+	errNum := 0
+	for connection := range connections {
+		err := connection.Signal(name, payload)
+		if err != nil {
+			errNum++
+		}
+		results = append(results, SignalResult{
+			Connection: connection,
+			Err:        err,
+		})
+	}
+
+	if errNum > 0 {
+		generalError = fmt.Errorf(
+			"%d errors during the signaling of a session",
+			errNum,
+		)
+	}
+
+	return results, generalError
+}
+
+// SignalWhere implements the Server interface
+func (srv *server) SignalWhere(
+	predicate func(*Session) bool,
+	name string,
+	payload Payload,
+) (
+	results []SignalResult,
+	generalError error,
+) {
+	// The sessions are read after the registry lock is released
+	// to not acquire the session locks while holding the registry lock
+	errNum := 0
+	for key, connection := range srv.sessionRegistry.sessionSnapshot() {
+		session := connection.Session()
+		if session == nil || !predicate(session) {
+			continue
+		}
+		sessionResults, err := srv.SignalSession(key, name, payload)
+		if err != nil {
+			errNum++
+		}
+		results = append(results, sessionResults...)
+	}
+
+	if errNum > 0 {
+		generalError = fmt.Errorf(
+			"errors during the signaling of %d sessions",
+			errNum,
+		)
+	}
+
+	return results, generalError
+}
+
+// UpdateSessionInfo implements the Server interface
+func (srv *server) UpdateSessionInfo(sessionKey string, info SessionInfo) (
+	affectedConnections []Connection,
+	errors []error,
+	generalError error,
+) {
+	connections := srv.sessionRegistry.sessionConnections(sessionKey)
+	if connections == nil {
+		return nil, nil, nil
+	}
+
+	errors = make([]error, len(connections))
+	affectedConnections = make([]Connection, len(connections))
+	i := 0
+	errNum := 0
+	for connection := range connections {
+		affectedConnections[i] = connection
+
+		// Provide each connection with its own copy of the session info
+		var connInfo SessionInfo
+		if info != nil {
+			connInfo = info.Copy()
+		}
+
+		if err := connection.UpdateSessionInfo(connInfo); err != nil {
+			errors[i] = err
+			errNum++
+		}
+		i++
+	}
+
+	if errNum > 0 {
+		generalError = fmt.Errorf(
+			"%d errors during the update of a session info",
+			errNum,
+		)
+	}
+
+	return affectedConnections, errors, generalError
+}
+
 // CloseSession implements the Server interface
 func (srv *server) CloseSession(sessionKey string) (
 	affectedConnections []Connection,
 	errors []error,
 	generalError error,
+) {
+	return srv.CloseSessionWithReason(sessionKey, "")
+}
+
+// CloseSessionWithReason implements the Server interface
+func (srv *server) CloseSessionWithReason(sessionKey, reason string) (
+	affectedConnections []Connection,
+	errors []error,
+	generalError error,
 ) {
 	connections := srv.sessionRegistry.sessionConnections(sessionKey)
 
@@ -123,7 +556,7 @@ func (srv *server) CloseSession(sessionKey string) (
 	errNum := 0
 	for connection := range connections {
 		affectedConnections[i] = connection
-		err := connection.CloseSession()
+		err := connection.CloseSessionWithReason(reason)
 		if err != nil {
 			errors[i] = err
 			errNum++
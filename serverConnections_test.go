@@ -0,0 +1,35 @@
+package webwire
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestServerRemoveConnection tests whether closed connections
+// are removed from the list of connections
+func TestServerRemoveConnection(t *testing.T) {
+	connectionsLock := &sync.Mutex{}
+	srv := &server{
+		connectionsLock:    connectionsLock,
+		connectionsChanged: sync.NewCond(connectionsLock),
+	}
+	conns := []*connection{
+		newConnection(&testSignalSocket{}, "", nil, nil),
+		newConnection(&testSignalSocket{}, "", nil, nil),
+		newConnection(&testSignalSocket{}, "", nil, nil),
+	}
+	srv.connections = append(srv.connections, conns...)
+
+	srv.removeConnection(conns[1])
+	require.Equal(t, []*connection{conns[0], conns[2]}, srv.connections)
+
+	// Expect removing an unknown connection to do nothing
+	srv.removeConnection(conns[1])
+	require.Len(t, srv.connections, 2)
+
+	srv.removeConnection(conns[0])
+	srv.removeConnection(conns[2])
+	require.Len(t, srv.connections, 0)
+}
@@ -1,7 +1,11 @@
 package webwire
 
 import (
+	"compress/flate"
+	"crypto/tls"
 	"log"
+	"net"
+	"net/http"
 	"os"
 	"time"
 )
@@ -23,17 +27,54 @@ const (
 // ServerOptions represents the options
 // used during the creation of a new WebWire server instance
 type ServerOptions struct {
-	Address               string
-	Sessions              OptionValue
-	SessionManager        SessionManager
-	SessionKeyGenerator   SessionKeyGenerator
-	SessionInfoParser     SessionInfoParser
-	MaxSessionConnections uint
-	Heartbeat             OptionValue
-	HeartbeatTimeout      time.Duration
-	HeartbeatInterval     time.Duration
-	WarnLog               *log.Logger
-	ErrorLog              *log.Logger
+	Address                    string
+	TLSConfig                  *tls.Config
+	CertFile                   string
+	KeyFile                    string
+	Sessions                   OptionValue
+	SessionManager             SessionManager
+	SessionKeyGenerator        SessionKeyGenerator
+	SessionInfoParser          SessionInfoParser
+	SessionRestoreVerifier     SessionRestoreVerifier
+	SessionCodec               SessionCodec
+	SlowSessionLookupThreshold time.Duration
+	AsyncSessionPersistence    OptionValue
+	SessionTTL                 time.Duration
+	SlidingSessionTTL          OptionValue
+	Metrics                    MetricsRecorder
+	Hooks                      MessageHooks
+	Executor                   Executor
+	RequestFilter              RequestFilter
+	RequestMiddleware          []RequestMiddleware
+	Codec                      Codec
+	ParseErrorHandler          ParseErrorHandler
+	MaxSessionConnections      uint
+	UnlimitedObservers         OptionValue
+	MaxConcurrentConnections   uint
+	MaxMessageSize             int64
+	MaxRequestNameLength       uint
+	MaxRequestPayloadSize      uint
+	CheckOrigin                func(r *http.Request) bool
+	Heartbeat                  OptionValue
+	HeartbeatTimeout           time.Duration
+	HeartbeatInterval          time.Duration
+	IdleTimeout                time.Duration
+	Compression                OptionValue
+	CompressionLevel           int
+	CompressionThreshold       int
+	WriteChunkSize             int
+	WriteChunkTimeout          time.Duration
+	WriteProgress              func(remoteAddr net.Addr, written, total int)
+	ExposeInternalErrors       OptionValue
+	RequireSessionForRequests  OptionValue
+	ReplaceSessions            OptionValue
+	ServerRequestTimeout       time.Duration
+	ReportPendingRequests      OptionValue
+	ShutdownProgress           func(remainingOps int, elapsed time.Duration)
+	ShutdownProgressInterval   time.Duration
+	OnError                    func(err error, errCtx ErrorContext)
+	WarnLog                    *log.Logger
+	ErrorLog                   *log.Logger
 }
 
 // SetDefaults sets the defaults for undefined required values
@@ -57,11 +98,108 @@ func (srvOpt *ServerOptions) SetDefaults() {
 		srvOpt.SessionInfoParser = GenericSessionInfoParser
 	}
 
+	// Encode session objects in JSON by default
+	if srvOpt.SessionCodec == nil {
+		srvOpt.SessionCodec = JSONSessionCodec{}
+	}
+
+	// Spawn a goroutine for each incoming message by default
+	if srvOpt.Executor == nil {
+		srvOpt.Executor = goroutineExecutor{}
+	}
+
+	// Persist sessions synchronously by default to guarantee the session
+	// is stored by the time CreateSession returns
+	if srvOpt.AsyncSessionPersistence == OptionUnset {
+		srvOpt.AsyncSessionPersistence = Disabled
+	}
+
+	// Use a fixed session expiration by default
+	// if sessions are configured to expire
+	if srvOpt.SlidingSessionTTL == OptionUnset {
+		srvOpt.SlidingSessionTTL = Disabled
+	}
+
+	// Use a default 1 second slow session lookup warning threshold
+	// if the specified threshold is undefined
+	if srvOpt.SlowSessionLookupThreshold < 1 {
+		srvOpt.SlowSessionLookupThreshold = 1 * time.Second
+	}
+
 	// Disable heartbeat by default
 	if srvOpt.Heartbeat == OptionUnset {
 		srvOpt.Heartbeat = Disabled
 	}
 
+	// Disable permessage-deflate compression by default
+	if srvOpt.Compression == OptionUnset {
+		srvOpt.Compression = Disabled
+	}
+
+	// Favor speed over size when compressing
+	// if the compression level is undefined
+	if srvOpt.CompressionLevel == 0 {
+		srvOpt.CompressionLevel = flate.BestSpeed
+	}
+
+	// Don't compress messages smaller than 256 bytes
+	// if the compression threshold is undefined
+	if srvOpt.CompressionThreshold < 1 {
+		srvOpt.CompressionThreshold = 256
+	}
+
+	// Use a default 10 seconds write deadline for each chunk
+	// of large outgoing messages if the specified timeout is undefined
+	if srvOpt.WriteChunkTimeout < 1 {
+		srvOpt.WriteChunkTimeout = 10 * time.Second
+	}
+
+	// Don't expose internal errors to the clients by default
+	// to prevent accidental leaks of sensitive information
+	if srvOpt.ExposeInternalErrors == OptionUnset {
+		srvOpt.ExposeInternalErrors = Disabled
+	}
+
+	// Allow requests from connections without a session by default
+	if srvOpt.RequireSessionForRequests == OptionUnset {
+		srvOpt.RequireSessionForRequests = Disabled
+	}
+
+	// Don't report the requests pending during shutdown by default
+	if srvOpt.ReportPendingRequests == OptionUnset {
+		srvOpt.ReportPendingRequests = Disabled
+	}
+
+	// Report the shutdown progress every second
+	// if the specified interval is undefined
+	if srvOpt.ShutdownProgressInterval < 1 {
+		srvOpt.ShutdownProgressInterval = 1 * time.Second
+	}
+
+	// Count observers towards the session connection limit by default
+	// since any client can declare itself an observer
+	if srvOpt.UnlimitedObservers == OptionUnset {
+		srvOpt.UnlimitedObservers = Disabled
+	}
+
+	// Replace the active session of a connection when a new session
+	// is created for it by default
+	if srvOpt.ReplaceSessions == OptionUnset {
+		srvOpt.ReplaceSessions = Enabled
+	}
+
+	// Limit incoming messages to a default of 8 MiB
+	// if the specified limit is undefined
+	if srvOpt.MaxMessageSize < 1 {
+		srvOpt.MaxMessageSize = 8 * 1024 * 1024
+	}
+
+	// Use a default 60 seconds timeout for requests sent to clients
+	// if the specified timeout is undefined
+	if srvOpt.ServerRequestTimeout < 1 {
+		srvOpt.ServerRequestTimeout = 60 * time.Second
+	}
+
 	// Use a default 60 seconds heartbeat timeout
 	// if the specified timeout is below 2 seconds
 	if srvOpt.HeartbeatTimeout < 2*time.Second {
@@ -0,0 +1,70 @@
+package webwire
+
+import (
+	"encoding/binary"
+	"sync"
+)
+
+// serverReply represents the results of a request sent by the server
+// to a client (both failed and succeeded)
+type serverReply struct {
+	payload Payload
+	err     error
+}
+
+// serverRequestManager keeps track of the pending requests
+// sent by the server to a particular client
+type serverRequestManager struct {
+	lock    sync.Mutex
+	lastID  uint64
+	pending map[[8]byte]chan serverReply
+}
+
+// newServerRequestManager constructs a new server request manager instance
+func newServerRequestManager() *serverRequestManager {
+	return &serverRequestManager{
+		pending: make(map[[8]byte]chan serverReply),
+	}
+}
+
+// create registers a new pending request and returns its identifier
+// and the channel the reply is delivered to
+func (mng *serverRequestManager) create() ([8]byte, chan serverReply) {
+	mng.lock.Lock()
+	defer mng.lock.Unlock()
+
+	// Generate a unique identifier by incrementing the last assigned id
+	mng.lastID++
+	var identifier [8]byte
+	binary.LittleEndian.PutUint64(identifier[:], mng.lastID)
+
+	// The reply channel is buffered to never block the reader
+	// of the connection if the request is no longer awaited
+	reply := make(chan serverReply, 1)
+	mng.pending[identifier] = reply
+	return identifier, reply
+}
+
+// deregister removes the request from the list of pending requests
+func (mng *serverRequestManager) deregister(identifier [8]byte) {
+	mng.lock.Lock()
+	delete(mng.pending, identifier)
+	mng.lock.Unlock()
+}
+
+// resolve deregisters the request associated with the given identifier
+// and delivers the reply. Returns false if there's no such pending request
+func (mng *serverRequestManager) resolve(
+	identifier [8]byte,
+	reply serverReply,
+) bool {
+	mng.lock.Lock()
+	replyChan, exists := mng.pending[identifier]
+	delete(mng.pending, identifier)
+	mng.lock.Unlock()
+	if !exists {
+		return false
+	}
+	replyChan <- reply
+	return true
+}
@@ -0,0 +1,102 @@
+package webwire
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testSignalSocket implements the Socket interface
+// recording written messages or failing if it's dead
+type testSignalSocket struct {
+	dead    bool
+	written [][]byte
+}
+
+func (sock *testSignalSocket) Dial(_ string) error { return nil }
+
+func (sock *testSignalSocket) Write(data []byte) error {
+	if sock.dead {
+		return DisconnectedErr{Cause: fmt.Errorf("socket is dead")}
+	}
+	sock.written = append(sock.written, data)
+	return nil
+}
+
+func (sock *testSignalSocket) Read() ([]byte, SockReadErr) { return nil, nil }
+
+func (sock *testSignalSocket) IsConnected() bool { return !sock.dead }
+
+func (sock *testSignalSocket) RemoteAddr() net.Addr { return nil }
+
+func (sock *testSignalSocket) Close() error { return nil }
+
+func (sock *testSignalSocket) CloseWithReason(_ string) error { return nil }
+
+func (sock *testSignalSocket) CloseWithCode(_ int, _ string) error {
+	return nil
+}
+
+func (sock *testSignalSocket) SetReadDeadline(_ time.Time) error { return nil }
+
+func (sock *testSignalSocket) OnPong(_ func(string) error) {}
+
+func (sock *testSignalSocket) OnPing(_ func(string) error) {}
+
+func (sock *testSignalSocket) WritePing(_ []byte, _ time.Time) error {
+	return nil
+}
+
+// TestServerSignalSession tests the per-connection results
+// of signaling all connections of a session
+func TestServerSignalSession(t *testing.T) {
+	srv := &server{
+		sessionRegistry: newSessionRegistry(0, false),
+	}
+	sess := NewSession(nil, func() string { return "testkey_A" })
+
+	// Register a healthy and a dead connection on the same session
+	healthySock := &testSignalSocket{}
+	healthyConn := newConnection(healthySock, "", srv, nil)
+	healthyConn.session = &sess
+	require.NoError(t, srv.sessionRegistry.register(healthyConn))
+
+	deadSock := &testSignalSocket{dead: true}
+	deadConn := newConnection(deadSock, "", srv, nil)
+	deadConn.session = &sess
+	require.NoError(t, srv.sessionRegistry.register(deadConn))
+
+	results, err := srv.SignalSession(
+		"testkey_A",
+		"sample",
+		NewPayload(EncodingBinary, []byte("sample data")),
+	)
+	require.Error(t, err)
+	require.Len(t, results, 2)
+
+	for _, result := range results {
+		switch result.Connection {
+		case healthyConn:
+			require.NoError(t, result.Err)
+		case deadConn:
+			require.Error(t, result.Err)
+			require.IsType(t, DisconnectedErr{}, result.Err)
+		default:
+			t.Fatalf("unexpected connection in results")
+		}
+	}
+	require.Len(t, healthySock.written, 1)
+	require.Len(t, deadSock.written, 0)
+
+	// Expect no results for inexistent sessions
+	results, err = srv.SignalSession(
+		"inexistent",
+		"sample",
+		NewPayload(EncodingBinary, []byte("sample data")),
+	)
+	require.NoError(t, err)
+	require.Nil(t, results)
+}
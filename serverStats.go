@@ -0,0 +1,24 @@
+package webwire
+
+// ServerStats represents an aggregated snapshot of the server state
+type ServerStats struct {
+	// ActiveSessions is the number of currently active sessions
+	ActiveSessions int
+
+	// Connections is the total number of currently active connections
+	Connections int
+
+	// AuthenticatedConnections is the number of currently active connections
+	// with a session
+	AuthenticatedConnections int
+
+	// AnonymousConnections is the number of currently active connections
+	// without a session
+	AnonymousConnections int
+
+	// PendingOperations is the number of currently processed operations
+	PendingOperations int
+
+	// ShuttingDown is true if the server is currently being shut down
+	ShuttingDown bool
+}
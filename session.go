@@ -36,25 +36,54 @@ func generateSessionKey() string {
 }
 
 // JSONEncodedSession represents a JSON encoded session object.
-// This structure is used during session restoration for unmarshalling
+// This structure is used during session restoration for unmarshalling.
+// Session files of the default session manager share its field names,
+// see sessionFile.
 // TODO: move to internal shared package
 type JSONEncodedSession struct {
 	Key        string                 `json:"k"`
 	Creation   time.Time              `json:"c"`
 	LastLookup time.Time              `json:"l"`
+	Expiration *time.Time             `json:"e,omitempty"`
 	Info       map[string]interface{} `json:"i,omitempty"`
 }
 
+// expirationField returns the JSON representation of the given expiration
+// time which is omitted if the session doesn't expire
+func expirationField(expiration time.Time) *time.Time {
+	if expiration.IsZero() {
+		return nil
+	}
+	return &expiration
+}
+
+// ExpirationTime returns the expiration time of the encoded session
+// or the zero time if the session doesn't expire
+func (s *JSONEncodedSession) ExpirationTime() time.Time {
+	if s.Expiration == nil {
+		return time.Time{}
+	}
+	return *s.Expiration
+}
+
 // Session represents a session object.
 // If the key is empty the session is invalid.
-// Info can contain arbitrary attached data
+// Info can contain arbitrary attached data.
+// The session never expires if Expiration is the zero time
 type Session struct {
 	Key        string
 	Creation   time.Time
 	LastLookup time.Time
+	Expiration time.Time
 	Info       SessionInfo
 }
 
+// IsExpired returns true if the session expires and
+// the expiration time has passed at the given time
+func (s *Session) IsExpired(now time.Time) bool {
+	return !s.Expiration.IsZero() && now.After(s.Expiration)
+}
+
 // Clone returns an exact copy of the session object
 func (s *Session) Clone() *Session {
 	if s == nil {
@@ -70,25 +99,46 @@ func (s *Session) Clone() *Session {
 		Key:        s.Key,
 		Creation:   s.Creation,
 		LastLookup: s.LastLookup,
+		Expiration: s.Expiration,
 		Info:       info,
 	}
 }
 
+// isValidSessionKey returns true if the given session key is not empty
+// and consists of URL-safe base64 characters only.
+// Session keys are used as file names and JSON strings
+// and must thus be restricted to this character set
+func isValidSessionKey(key string) bool {
+	if len(key) < 1 {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		char := key[i]
+		if (char < 'a' || char > 'z') &&
+			(char < 'A' || char > 'Z') &&
+			(char < '0' || char > '9') &&
+			char != '-' && char != '_' && char != '=' {
+			return false
+		}
+	}
+	return true
+}
+
 // NewSession generates a new session object
-// generating a cryptographically random secure key
+// generating a cryptographically random secure key.
+// Panics with an InvalidSessionKeyErr error if the generated key is empty
+// or contains characters other than URL-safe base64 characters
 func NewSession(info SessionInfo, generator func() string) Session {
 	key := generator()
-	if len(key) < 1 {
-		panic(fmt.Errorf(
-			"Invalid session key returned by the session key generator (empty)",
-		))
+	if !isValidSessionKey(key) {
+		panic(InvalidSessionKeyErr{Key: key})
 	}
 	timeNow := time.Now()
 	return Session{
-		key,
-		timeNow,
-		timeNow,
-		info,
+		Key:        key,
+		Creation:   timeNow,
+		LastLookup: timeNow,
+		Info:       info,
 	}
 }
 
@@ -0,0 +1,130 @@
+package webwire
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// JSONSessionCodec represents the default session codec
+// encoding session objects in JSON
+type JSONSessionCodec struct{}
+
+// Encoding implements the SessionCodec interface
+func (JSONSessionCodec) Encoding() PayloadEncoding {
+	return EncodingUtf8
+}
+
+// Encode implements the SessionCodec interface
+func (JSONSessionCodec) Encode(session *JSONEncodedSession) ([]byte, error) {
+	return json.Marshal(session)
+}
+
+// Decode implements the SessionCodec interface
+func (JSONSessionCodec) Decode(data []byte) (*JSONEncodedSession, error) {
+	session := &JSONEncodedSession{}
+	if err := json.Unmarshal(data, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// BinarySessionCodec represents a session codec encoding session objects
+// in a compact binary representation:
+//  1. the length of the key as an unsigned varint followed by the key
+//  2. the creation, last lookup and expiration time
+//     as 64 bit little endian unix nanoseconds each,
+//     zero for the zero time
+//  3. the JSON encoded session info, empty if there's none
+type BinarySessionCodec struct{}
+
+// Encoding implements the SessionCodec interface
+func (BinarySessionCodec) Encoding() PayloadEncoding {
+	return EncodingBinary
+}
+
+// Encode implements the SessionCodec interface
+func (BinarySessionCodec) Encode(session *JSONEncodedSession) ([]byte, error) {
+	var info []byte
+	if session.Info != nil {
+		var err error
+		if info, err = json.Marshal(session.Info); err != nil {
+			return nil, fmt.Errorf("Couldn't encode session info: %s", err)
+		}
+	}
+
+	encoded := make(
+		[]byte,
+		binary.MaxVarintLen64+len(session.Key)+24+len(info),
+	)
+	offset := binary.PutUvarint(encoded, uint64(len(session.Key)))
+	offset += copy(encoded[offset:], session.Key)
+	for _, tm := range []time.Time{
+		session.Creation,
+		session.LastLookup,
+		session.ExpirationTime(),
+	} {
+		binary.LittleEndian.PutUint64(encoded[offset:], unixNano(tm))
+		offset += 8
+	}
+	offset += copy(encoded[offset:], info)
+
+	return encoded[:offset], nil
+}
+
+// Decode implements the SessionCodec interface
+func (BinarySessionCodec) Decode(data []byte) (*JSONEncodedSession, error) {
+	keyLen, offset := binary.Uvarint(data)
+	if offset < 1 {
+		return nil, fmt.Errorf("Invalid binary encoded session")
+	}
+
+	// Compare against the remaining length instead of adding
+	// to the key length to not overflow on huge key lengths
+	remaining := uint64(len(data) - offset)
+	if keyLen > remaining || remaining-keyLen < 24 {
+		return nil, fmt.Errorf("Invalid binary encoded session")
+	}
+	session := &JSONEncodedSession{
+		Key: string(data[offset : offset+int(keyLen)]),
+	}
+	offset += int(keyLen)
+
+	var times [3]time.Time
+	for i := range times {
+		times[i] = timeFromUnixNano(
+			binary.LittleEndian.Uint64(data[offset:]),
+		)
+		offset += 8
+	}
+	session.Creation = times[0]
+	session.LastLookup = times[1]
+	session.Expiration = expirationField(times[2])
+
+	if offset < len(data) {
+		if err := json.Unmarshal(data[offset:], &session.Info); err != nil {
+			return nil, fmt.Errorf("Couldn't decode session info: %s", err)
+		}
+	}
+
+	return session, nil
+}
+
+// unixNano returns the given time in unix nanoseconds
+// or zero if the given time is the zero time
+func unixNano(tm time.Time) uint64 {
+	if tm.IsZero() {
+		return 0
+	}
+	return uint64(tm.UnixNano())
+}
+
+// timeFromUnixNano returns the time represented by the given
+// unix nanoseconds or the zero time if they're zero
+func timeFromUnixNano(nanoseconds uint64) time.Time {
+	if nanoseconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(nanoseconds))
+}
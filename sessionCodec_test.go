@@ -0,0 +1,107 @@
+package webwire
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSessionCodecRoundTrip tests encoding and decoding session objects
+// using the built-in session codecs without data loss
+func TestSessionCodecRoundTrip(t *testing.T) {
+	expiration := time.Now().Add(time.Hour)
+	sessions := map[string]JSONEncodedSession{
+		"full": {
+			Key:        "samplekey",
+			Creation:   time.Now(),
+			LastLookup: time.Now(),
+			Expiration: &expiration,
+			Info: map[string]interface{}{
+				"field1": "value1",
+				"field2": float64(42),
+				"field3": []interface{}{"item1", "item2"},
+			},
+		},
+		"minimal": {
+			Key:      "samplekey",
+			Creation: time.Now(),
+		},
+	}
+
+	for _, codec := range []SessionCodec{
+		JSONSessionCodec{},
+		BinarySessionCodec{},
+	} {
+		for name, original := range sessions {
+			encoded, err := codec.Encode(&original)
+			require.NoError(t, err, name)
+
+			decoded, err := codec.Decode(encoded)
+			require.NoError(t, err, name)
+			require.Equal(t, original.Key, decoded.Key)
+			require.True(t, original.Creation.Equal(decoded.Creation))
+			require.True(t, original.LastLookup.Equal(decoded.LastLookup))
+			require.True(t,
+				original.ExpirationTime().Equal(decoded.ExpirationTime()),
+			)
+			require.Equal(t, original.Info, decoded.Info)
+		}
+	}
+}
+
+// TestBinarySessionCodecInvalid tests decoding truncated
+// binary encoded session objects
+func TestBinarySessionCodecInvalid(t *testing.T) {
+	codec := BinarySessionCodec{}
+	encoded, err := codec.Encode(&JSONEncodedSession{
+		Key:      "samplekey",
+		Creation: time.Now(),
+	})
+	require.NoError(t, err)
+
+	for _, data := range [][]byte{nil, encoded[:5], encoded[:len(encoded)-1]} {
+		_, err := codec.Decode(data)
+		require.Error(t, err)
+	}
+}
+
+// TestBinarySessionCodecMalformed tests decoding binary encoded session
+// objects with key lengths overflowing the message length
+// and randomly corrupted session objects expecting no panics
+func TestBinarySessionCodecMalformed(t *testing.T) {
+	codec := BinarySessionCodec{}
+
+	// Key lengths overflowing when adding the length of the timestamps
+	for _, keyLen := range []uint64{
+		math.MaxUint64,
+		math.MaxUint64 - 23,
+		math.MaxUint64 / 2,
+	} {
+		data := make([]byte, binary.MaxVarintLen64+32)
+		binary.PutUvarint(data, keyLen)
+		require.NotPanics(t, func() {
+			_, err := codec.Decode(data)
+			require.Error(t, err)
+		})
+	}
+
+	// Randomly corrupted session objects
+	encoded, err := codec.Encode(&JSONEncodedSession{
+		Key:      "samplekey",
+		Creation: time.Now(),
+		Info:     map[string]interface{}{"field": "value"},
+	})
+	require.NoError(t, err)
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		data := append([]byte(nil), encoded[:random.Intn(len(encoded)+1)]...)
+		for j := random.Intn(4); j >= 0 && len(data) > 0; j-- {
+			data[random.Intn(len(data))] = byte(random.Intn(256))
+		}
+		require.NotPanics(t, func() { codec.Decode(data) })
+	}
+}
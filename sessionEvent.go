@@ -0,0 +1,79 @@
+package webwire
+
+import "time"
+
+// SessionEventType represents the type of a session registry change
+type SessionEventType int
+
+const (
+	// SessionEventCreated is emitted when a session becomes active
+	// on the server when the first connection is registered for it
+	// either by creating or by restoring the session
+	SessionEventCreated SessionEventType = iota
+
+	// SessionEventClosed is emitted when a session becomes inactive
+	// on the server when its last connection is deregistered
+	SessionEventClosed
+
+	// SessionEventConnectionAdded is emitted when a connection
+	// is registered for a session
+	SessionEventConnectionAdded
+
+	// SessionEventConnectionRemoved is emitted when a connection
+	// is deregistered from a session
+	SessionEventConnectionRemoved
+)
+
+// String stringifies the session event type
+func (tp SessionEventType) String() string {
+	switch tp {
+	case SessionEventCreated:
+		return "created"
+	case SessionEventClosed:
+		return "closed"
+	case SessionEventConnectionAdded:
+		return "connection added"
+	case SessionEventConnectionRemoved:
+		return "connection removed"
+	}
+	return "unknown"
+}
+
+// SessionEvent represents a change of the session registry
+type SessionEvent struct {
+	// Type is the type of the change
+	Type SessionEventType
+
+	// SessionKey is the key of the affected session
+	SessionKey string
+
+	// Connection is the connection whose registration
+	// or deregistration caused the change
+	Connection
+
+	// Time is the time the change occurred at
+	Time time.Time
+}
+
+// sessionEventsBufferSize defines the number of events buffered
+// for each session registry watcher
+const sessionEventsBufferSize = 256
+
+// sessionWatcher represents a subscriber of session registry changes
+type sessionWatcher chan SessionEvent
+
+// send sends the given event without ever blocking
+// dropping the oldest buffered event if the buffer is full
+func (watcher sessionWatcher) send(event SessionEvent) {
+	for {
+		select {
+		case watcher <- event:
+			return
+		default:
+		}
+		select {
+		case <-watcher:
+		default:
+		}
+	}
+}
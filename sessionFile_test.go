@@ -0,0 +1,167 @@
+package webwire
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSessionFileCompressed tests writing and reading back
+// a gzip compressed session file
+func TestSessionFileCompressed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wwrsess")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	check := func(fileName string, compress bool) {
+		filePath := filepath.Join(dir, fileName)
+		original := sessionFile{
+			Creation:   time.Now().UTC().Round(time.Second),
+			LastLookup: time.Now().UTC().Round(time.Second),
+			Info: map[string]interface{}{
+				"field1": "value1",
+				"field2": float64(42),
+			},
+		}
+		require.NoError(t, original.Save(filePath, compress, nil, 0600))
+
+		// Verify the magic bytes header is only present in compressed files
+		contents, err := ioutil.ReadFile(filePath)
+		require.NoError(t, err)
+		require.Equal(t, compress, bytes.HasPrefix(contents, gzipMagic))
+
+		// Verify the file is read back transparently
+		var parsed sessionFile
+		require.NoError(t, parsed.Parse(filePath, nil))
+		require.True(t, original.Creation.Equal(parsed.Creation))
+		require.True(t, original.LastLookup.Equal(parsed.LastLookup))
+		require.Equal(t, original.Info, parsed.Info)
+	}
+
+	check("compressed.wwrsess", true)
+	check("uncompressed.wwrsess", false)
+}
+
+// TestSessionFileEncrypted tests writing and reading back
+// an encrypted session file making sure no plaintext is written to disk
+func TestSessionFileEncrypted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wwrsess")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	aead := NewEncryptedDefaultSessionManager(dir, key).aead
+	require.NotNil(t, aead)
+
+	check := func(fileName string, compress bool) {
+		filePath := filepath.Join(dir, fileName)
+		original := sessionFile{
+			Creation:   time.Now().UTC().Round(time.Second),
+			LastLookup: time.Now().UTC().Round(time.Second),
+			Info: map[string]interface{}{
+				"secret": "sensitivesessionvalue",
+			},
+		}
+		require.NoError(t, original.Save(filePath, compress, aead, 0600))
+
+		// Verify no plaintext is written to disk
+		contents, err := ioutil.ReadFile(filePath)
+		require.NoError(t, err)
+		require.Equal(t, sessionFileEncryptionV1, contents[0])
+		require.False(t, bytes.Contains(contents, []byte("secret")))
+		require.False(t,
+			bytes.Contains(contents, []byte("sensitivesessionvalue")),
+		)
+
+		// Verify the file is read back transparently
+		var parsed sessionFile
+		require.NoError(t, parsed.Parse(filePath, aead))
+		require.True(t, original.Creation.Equal(parsed.Creation))
+		require.True(t, original.LastLookup.Equal(parsed.LastLookup))
+		require.Equal(t, original.Info, parsed.Info)
+
+		// Verify the file can't be read without the key
+		var unencrypted sessionFile
+		require.Error(t, unencrypted.Parse(filePath, nil))
+	}
+
+	check("encrypted.wwrsess", false)
+	check("encryptedcompressed.wwrsess", true)
+
+	// Verify a wrong key is rejected
+	wrongKey := []byte("fedcba9876543210fedcba9876543210")
+	wrongAead := NewEncryptedDefaultSessionManager(dir, wrongKey).aead
+	var parsed sessionFile
+	require.Error(t, parsed.Parse(
+		filepath.Join(dir, "encrypted.wwrsess"),
+		wrongAead,
+	))
+}
+
+// TestSessionFileWireFormat tests reconstructing a session written
+// to a session file into the wire format without data loss
+func TestSessionFileWireFormat(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wwrsess")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	mng := NewDefaultSessionManager(dir)
+	key := "samplekey"
+	original := JSONEncodedSession{
+		Key:        key,
+		Creation:   time.Now().UTC().Round(time.Second),
+		LastLookup: time.Now().UTC().Round(time.Second),
+		Info: map[string]interface{}{
+			"field1": "value1",
+			"field2": float64(42),
+			"field3": []interface{}{"item1", "item2"},
+		},
+	}
+
+	// Write the session file
+	file := sessionFile{
+		Creation:   original.Creation,
+		LastLookup: original.LastLookup,
+		Info:       original.Info,
+	}
+	require.NoError(t, file.Save(mng.filePath(key), false, nil, 0600))
+
+	// Read it back and reconstruct the wire format
+	var parsed sessionFile
+	require.NoError(t, parsed.Parse(mng.filePath(key), nil))
+	require.Equal(t, sessionFileFormatVersion, parsed.Version)
+
+	encoded, err := json.Marshal(parsed.encodedSession(key))
+	require.NoError(t, err)
+
+	var decoded JSONEncodedSession
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	require.Equal(t, original.Key, decoded.Key)
+	require.True(t, original.Creation.Equal(decoded.Creation))
+	require.True(t, original.LastLookup.Equal(decoded.LastLookup))
+	require.Equal(t, original.Info, decoded.Info)
+}
+
+// TestSessionFileUnsupportedVersion tests parsing a session file
+// of an unsupported future format version
+func TestSessionFileUnsupportedVersion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wwrsess")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "future.wwrsess")
+	require.NoError(t, ioutil.WriteFile(
+		filePath,
+		[]byte(`{"v":255,"c":"2018-01-01T00:00:00Z"}`),
+		0640,
+	))
+
+	var parsed sessionFile
+	require.Error(t, parsed.Parse(filePath, nil))
+}
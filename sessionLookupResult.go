@@ -3,6 +3,7 @@ package webwire
 import "time"
 
 // NewSessionLookupResult creates a new result of a session lookup operation
+// for a session that doesn't expire
 func NewSessionLookupResult(
 	creation time.Time,
 	lastLookup time.Time,
@@ -15,11 +16,39 @@ func NewSessionLookupResult(
 	}
 }
 
+// NewExpiringSessionLookupResult creates a new result
+// of a session lookup operation for a session expiring at the given time.
+// A zero expiration time stands for a session that doesn't expire
+func NewExpiringSessionLookupResult(
+	creation time.Time,
+	lastLookup time.Time,
+	expiration time.Time,
+	info map[string]interface{},
+) ExpiringSessionLookupResult {
+	return &sessionLookupResult{
+		creation:   creation,
+		lastLookup: lastLookup,
+		expiration: expiration,
+		info:       info,
+	}
+}
+
+// lookupResultExpiration returns the expiration time of the session
+// of the given lookup result or the zero time if the result doesn't implement
+// the ExpiringSessionLookupResult interface
+func lookupResultExpiration(result SessionLookupResult) time.Time {
+	if expiring, ok := result.(ExpiringSessionLookupResult); ok {
+		return expiring.Expiration()
+	}
+	return time.Time{}
+}
+
 // sessionLookupResult represents an implementation
-// of the SessionLookupResult interface
+// of the SessionLookupResult and ExpiringSessionLookupResult interfaces
 type sessionLookupResult struct {
 	creation   time.Time
 	lastLookup time.Time
+	expiration time.Time
 	info       map[string]interface{}
 }
 
@@ -33,6 +62,11 @@ func (slr *sessionLookupResult) LastLookup() time.Time {
 	return slr.lastLookup
 }
 
+// Expiration implements the ExpiringSessionLookupResult interface
+func (slr *sessionLookupResult) Expiration() time.Time {
+	return slr.expiration
+}
+
 // Info implements the SessionLookupResult interface
 func (slr *sessionLookupResult) Info() map[string]interface{} {
 	return slr.info
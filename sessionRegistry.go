@@ -1,8 +1,8 @@
 package webwire
 
 import (
-	"fmt"
 	"sync"
+	"time"
 )
 
 // sessionRegistry represents a thread safe registry
@@ -11,70 +11,282 @@ type sessionRegistry struct {
 	lock     sync.RWMutex
 	maxConns uint
 	registry map[string]map[*connection]struct{}
+
+	// exemptObservers defines whether observer connections
+	// don't count towards the maximum number of concurrent connections
+	exemptObservers bool
+
+	// limits overrides the maximum number of concurrent connections
+	// of individual sessions indexed by the session key
+	limits map[string]uint
+
+	// keys indexes the session key each connection is registered with
+	// to never read the session of a connection during deregistration
+	keys map[*connection]string
+
+	// watchers are the subscribers of registry changes
+	watchers []sessionWatcher
+
+	// closed is set when the watchers were closed during the shutdown
+	closed bool
 }
 
 // newSessionRegistry returns a new instance of a session registry.
 // maxConns defines the maximum number of concurrent connections
-// for a single session while zero stands for unlimited
-func newSessionRegistry(maxConns uint) *sessionRegistry {
+// for a single session while zero stands for unlimited.
+// Observer connections are exempt from the limit if exemptObservers is true
+func newSessionRegistry(
+	maxConns uint,
+	exemptObservers bool,
+) *sessionRegistry {
 	return &sessionRegistry{
-		lock:     sync.RWMutex{},
-		maxConns: maxConns,
-		registry: make(map[string]map[*connection]struct{}),
+		lock:            sync.RWMutex{},
+		maxConns:        maxConns,
+		registry:        make(map[string]map[*connection]struct{}),
+		exemptObservers: exemptObservers,
+		limits:          make(map[string]uint),
+		keys:            make(map[*connection]string),
+		watchers:        make([]sessionWatcher, 0),
 	}
 }
 
-// register registers a new connection for the given clients session.
-// Returns an error if the given clients session already reached
-// the maximum number of concurrent connections
-func (asr *sessionRegistry) register(con *connection) error {
+// watch subscribes a new watcher to the registry changes.
+// The returned cancel function unsubscribes the watcher closing its channel.
+// Returns a closed channel if the watchers were already closed
+func (asr *sessionRegistry) watch() (<-chan SessionEvent, func()) {
+	watcher := make(sessionWatcher, sessionEventsBufferSize)
 	asr.lock.Lock()
 	defer asr.lock.Unlock()
-	if connSet, exists := asr.registry[con.session.Key]; exists {
-		// Ensure max connections isn't exceeded
-		if asr.maxConns > 0 && uint(len(connSet)+1) > asr.maxConns {
-			return fmt.Errorf(
-				"Max conns (%d) reached for session %s",
-				asr.maxConns,
-				con.session.Key,
-			)
+	if asr.closed {
+		close(watcher)
+		return watcher, func() {}
+	}
+	asr.watchers = append(asr.watchers, watcher)
+	return watcher, func() { asr.unwatch(watcher) }
+}
+
+// unwatch unsubscribes the given watcher from the registry changes
+// closing its channel. Does nothing if the watcher was already unsubscribed
+// or if the watchers were already closed
+func (asr *sessionRegistry) unwatch(watcher sessionWatcher) {
+	asr.lock.Lock()
+	defer asr.lock.Unlock()
+	for i, subscribed := range asr.watchers {
+		if subscribed == watcher {
+			asr.watchers = append(asr.watchers[:i], asr.watchers[i+1:]...)
+			close(watcher)
+			return
 		}
-		// Overwrite the current entry incrementing the number of connections
+	}
+}
+
+// closeWatchers closes the channels of all watchers
+// and prevents new watchers from subscribing
+func (asr *sessionRegistry) closeWatchers() {
+	asr.lock.Lock()
+	defer asr.lock.Unlock()
+	if asr.closed {
+		return
+	}
+	asr.closed = true
+	for _, watcher := range asr.watchers {
+		close(watcher)
+	}
+	asr.watchers = nil
+}
+
+// emit sends an event of the given type to all watchers.
+// Expects the lock to be held by the caller
+func (asr *sessionRegistry) emit(
+	eventType SessionEventType,
+	key string,
+	conn *connection,
+) {
+	if len(asr.watchers) < 1 {
+		return
+	}
+	event := SessionEvent{
+		Type:       eventType,
+		SessionKey: key,
+		Connection: conn,
+		Time:       time.Now(),
+	}
+	for _, watcher := range asr.watchers {
+		watcher.send(event)
+	}
+}
+
+// register registers a new connection for the given clients session
+// moving it if it's still registered with another session.
+// Returns a MaxSessConnsReachedErr error if the given clients session
+// already reached the maximum number of concurrent connections
+// in which case the connection remains registered with its current session.
+// Expects the session of the connection to not be concurrently modified
+// during registration
+func (asr *sessionRegistry) register(con *connection) error {
+	key := con.session.Key
+
+	asr.lock.Lock()
+	defer asr.lock.Unlock()
+
+	// Ensure max connections isn't exceeded before touching
+	// the current registration of the connection
+	connSet, exists := asr.registry[key]
+	if exists && asr.isLimitReached(key, connSet, con) {
+		return MaxSessConnsReachedErr{}
+	}
+
+	if currentKey, registered := asr.keys[con]; registered &&
+		currentKey != key {
+		asr.remove(con, currentKey)
+	}
+	if exists {
+		// Add the connection incrementing the number of connections
 		connSet[con] = struct{}{}
-		asr.registry[con.session.Key] = connSet
-		return nil
+	} else {
+		asr.registry[key] = map[*connection]struct{}{
+			con: {},
+		}
+		asr.emit(SessionEventCreated, key, con)
 	}
-	newList := map[*connection]struct{}{
+	asr.keys[con] = key
+	asr.emit(SessionEventConnectionAdded, key, con)
+	return nil
+}
+
+// registerNew registers the given connection with a new session
+// identified by the given key moving it out of its current session if any.
+// Returns false without modifying the registry if a session with the given
+// key is already active. Otherwise returns the number of connections left
+// in the previous session of the connection or -1 if it had none
+func (asr *sessionRegistry) registerNew(
+	con *connection,
+	key string,
+) (remainingConns int, registered bool) {
+	asr.lock.Lock()
+	defer asr.lock.Unlock()
+	if _, exists := asr.registry[key]; exists {
+		return 0, false
+	}
+
+	remainingConns = -1
+	if currentKey, registered := asr.keys[con]; registered {
+		remainingConns = asr.remove(con, currentKey)
+	}
+	asr.registry[key] = map[*connection]struct{}{
 		con: {},
 	}
-	asr.registry[con.session.Key] = newList
-	return nil
+	asr.keys[con] = key
+	asr.emit(SessionEventCreated, key, con)
+	asr.emit(SessionEventConnectionAdded, key, con)
+	return remainingConns, true
+}
+
+// setMaxConns overrides the maximum number of concurrent connections
+// of the given session while zero stands for unlimited.
+// Returns false without setting the override
+// if the given session isn't active
+func (asr *sessionRegistry) setMaxConns(key string, maxConns uint) bool {
+	asr.lock.Lock()
+	defer asr.lock.Unlock()
+	if _, exists := asr.registry[key]; !exists {
+		return false
+	}
+	asr.limits[key] = maxConns
+	return true
+}
+
+// dropMaxConns drops the connection limit override of the given session
+// if any. Must be called when the session is destroyed
+func (asr *sessionRegistry) dropMaxConns(key string) {
+	asr.lock.Lock()
+	delete(asr.limits, key)
+	asr.lock.Unlock()
 }
 
-// deregister removes a connection from the list of connections of a session
-// returns the number of connections left.
+// maxConnsOf returns the maximum number of concurrent connections
+// of the given session while zero stands for unlimited.
+// Expects the lock to be held by the caller
+func (asr *sessionRegistry) maxConnsOf(key string) uint {
+	if maxConns, overridden := asr.limits[key]; overridden {
+		return maxConns
+	}
+	return asr.maxConns
+}
+
+// isLimitReached returns true if registering the given connection
+// for the given set of session connections would exceed the maximum number
+// of concurrent connections of the session.
+// The given connection isn't counted twice if it's already in the set.
+// Observers don't count towards the limit if they're exempt.
+// Expects the lock to be held by the caller
+func (asr *sessionRegistry) isLimitReached(
+	key string,
+	connSet map[*connection]struct{},
+	con *connection,
+) bool {
+	maxConns := asr.maxConnsOf(key)
+	if maxConns < 1 || asr.isExempt(con) {
+		return false
+	}
+	connsNum := asr.limitedConnsNum(connSet)
+	if _, registered := connSet[con]; registered {
+		connsNum--
+	}
+	return connsNum+1 > maxConns
+}
+
+// limitedConnsNum returns the number of connections of the given set
+// counting towards the maximum number of connections per session
+func (asr *sessionRegistry) limitedConnsNum(
+	connSet map[*connection]struct{},
+) uint {
+	num := uint(0)
+	for conn := range connSet {
+		if !asr.isExempt(conn) {
+			num++
+		}
+	}
+	return num
+}
+
+// isExempt returns true if the given connection doesn't count towards
+// the maximum number of concurrent connections of its session
+func (asr *sessionRegistry) isExempt(conn *connection) bool {
+	return asr.exemptObservers && conn.info.Observer
+}
+
+// deregister removes a connection from the list of connections of the session
+// it's registered with and returns the number of connections left.
 // If there's only one connection left then the entire session will be removed
 // from the register and 0 will be returned.
 // If the given connection is not in the register -1 is returned
 func (asr *sessionRegistry) deregister(conn *connection) int {
-	if conn.session == nil {
+	asr.lock.Lock()
+	defer asr.lock.Unlock()
+	key, registered := asr.keys[conn]
+	if !registered {
 		return -1
 	}
+	return asr.remove(conn, key)
+}
 
-	asr.lock.Lock()
-	defer asr.lock.Unlock()
-	if connSet, exists := asr.registry[conn.session.Key]; exists {
-		// If a single connection is left then remove the session
-		if len(connSet) < 2 {
-			delete(asr.registry, conn.session.Key)
-			return 0
-		}
+// remove removes the given connection from the given session
+// and returns the number of connections left.
+// Expects the lock to be held by the caller
+func (asr *sessionRegistry) remove(conn *connection, key string) int {
+	delete(asr.keys, conn)
+	connSet := asr.registry[key]
+	delete(connSet, conn)
+	asr.emit(SessionEventConnectionRemoved, key, conn)
 
-		// Find and remove the client from the connections list
-		delete(connSet, conn)
-		return len(connSet)
+	// Remove the session if no connections are left
+	if len(connSet) < 1 {
+		delete(asr.registry, key)
+		asr.emit(SessionEventClosed, key, conn)
+		return 0
 	}
-	return -1
+	return len(connSet)
 }
 
 // activeSessionsNum returns the number of currently active sessions
@@ -97,15 +309,59 @@ func (asr *sessionRegistry) sessionConnectionsNum(sessionKey string) int {
 	return -1
 }
 
-// sessionConnections implements the sessionRegistry interface
+// sessionConnections returns a copy of the set of connections
+// of the given session to be safely iterated over
+// while the registry is modified concurrently.
+// Returns nil if the session isn't registered
 func (asr *sessionRegistry) sessionConnections(
 	sessionKey string,
 ) map[*connection]struct{} {
 	asr.lock.RLock()
-	if connSet, exists := asr.registry[sessionKey]; exists {
-		asr.lock.RUnlock()
-		return connSet
+	defer asr.lock.RUnlock()
+	connSet, exists := asr.registry[sessionKey]
+	if !exists {
+		return nil
 	}
-	asr.lock.RUnlock()
-	return nil
+	connections := make(map[*connection]struct{}, len(connSet))
+	for conn := range connSet {
+		connections[conn] = struct{}{}
+	}
+	return connections
+}
+
+// unregisteredNum returns the number of the given connections
+// that aren't registered for any session
+func (asr *sessionRegistry) unregisteredNum(connections []*connection) int {
+	asr.lock.RLock()
+	defer asr.lock.RUnlock()
+
+	registered := make(map[*connection]struct{})
+	for _, connSet := range asr.registry {
+		for conn := range connSet {
+			registered[conn] = struct{}{}
+		}
+	}
+
+	num := 0
+	for _, conn := range connections {
+		if _, exists := registered[conn]; !exists {
+			num++
+		}
+	}
+	return num
+}
+
+// sessionSnapshot returns one connection of each currently active session
+// indexed by the session key
+func (asr *sessionRegistry) sessionSnapshot() map[string]*connection {
+	asr.lock.RLock()
+	defer asr.lock.RUnlock()
+	snapshot := make(map[string]*connection, len(asr.registry))
+	for key, connSet := range asr.registry {
+		for conn := range connSet {
+			snapshot[key] = conn
+			break
+		}
+	}
+	return snapshot
 }
@@ -12,7 +12,7 @@ import (
 
 // TestSessRegRegistration tests registration
 func TestSessRegRegistration(t *testing.T) {
-	reg := newSessionRegistry(0)
+	reg := newSessionRegistry(0, false)
 
 	// Register connection with session
 	clt := newConnection(nil, "", nil, nil)
@@ -31,7 +31,7 @@ func TestSessRegRegistration(t *testing.T) {
 // TestSessRegActiveSessionsNum tests the ActiveSessionsNum method
 func TestSessRegActiveSessionsNum(t *testing.T) {
 	expectedSessionsNum := 2
-	reg := newSessionRegistry(0)
+	reg := newSessionRegistry(0, false)
 
 	// Register 2 connections on two separate sessions
 	cltA1 := newConnection(nil, "", nil, nil)
@@ -56,7 +56,7 @@ func TestSessRegActiveSessionsNum(t *testing.T) {
 // TestSessRegsessionConnectionsNum tests the sessionConnectionsNum method
 func TestSessRegsessionConnectionsNum(t *testing.T) {
 	expectedSessionsNum := 1
-	reg := newSessionRegistry(0)
+	reg := newSessionRegistry(0, false)
 
 	// Register first connection on session A
 	cltA1 := newConnection(nil, "", nil, nil)
@@ -83,7 +83,7 @@ func TestSessRegsessionConnectionsNum(t *testing.T) {
 // when the maximum number of concurrent connections of a session was reached
 func TestSessRegSessionMaxConns(t *testing.T) {
 	// Set the maximum number of concurrent session connection to 1
-	reg := newSessionRegistry(1)
+	reg := newSessionRegistry(1, false)
 
 	// Register first connection on session A
 	cltA1 := newConnection(nil, "", nil, nil)
@@ -92,21 +92,71 @@ func TestSessRegSessionMaxConns(t *testing.T) {
 
 	require.NoError(t, reg.register(cltA1))
 
-	// Register first connection on session A
+	// Register second connection on session A
 	cltA2 := newConnection(nil, "", nil, nil)
 	sessA2 := NewSession(nil, func() string { return "testkey_A" })
 	cltA2.session = &sessA2
 
 	require.Error(t,
-		reg.register(cltA1),
+		reg.register(cltA2),
 		"Expected register to return an error "+
 			"due to the limit of concurrent connection being reached",
 	)
 }
 
+// TestSessRegSetMaxConns tests overriding the maximum number of concurrent
+// connections of an individual session
+func TestSessRegSetMaxConns(t *testing.T) {
+	reg := newSessionRegistry(1, false)
+
+	cltA1 := newConnection(nil, "", nil, nil)
+	sessA1 := NewSession(nil, func() string { return "testkey_A" })
+	cltA1.session = &sessA1
+	require.NoError(t, reg.register(cltA1))
+
+	cltA2 := newConnection(nil, "", nil, nil)
+	sessA2 := NewSession(nil, func() string { return "testkey_A" })
+	cltA2.session = &sessA2
+	require.Equal(t, MaxSessConnsReachedErr{}, reg.register(cltA2))
+
+	// Expect overriding the limit of an inactive session to be rejected
+	require.False(t, reg.setMaxConns("testkey_B", 2))
+
+	// Raise the limit of session A
+	require.True(t, reg.setMaxConns("testkey_A", 2))
+	require.NoError(t, reg.register(cltA2))
+	require.Equal(t, 2, reg.sessionConnectionsNum("testkey_A"))
+
+	// Expect the override to survive the removal
+	// of the last connection of the session
+	require.Equal(t, 1, reg.deregister(cltA1))
+	require.Equal(t, 0, reg.deregister(cltA2))
+	require.NoError(t, reg.register(cltA1))
+	require.NoError(t, reg.register(cltA2))
+
+	// Expect the default limit to apply again
+	// after the override is dropped on session closure
+	reg.dropMaxConns("testkey_A")
+	require.Equal(t, 1, reg.deregister(cltA2))
+	require.Equal(t, MaxSessConnsReachedErr{}, reg.register(cltA2))
+}
+
+// TestSessRegReregisterAtLimit tests whether registering a connection
+// that's already registered for the session at its limit isn't counted twice
+func TestSessRegReregisterAtLimit(t *testing.T) {
+	reg := newSessionRegistry(1, false)
+
+	cltA1 := newConnection(nil, "", nil, nil)
+	sessA1 := NewSession(nil, func() string { return "testkey_A" })
+	cltA1.session = &sessA1
+	require.NoError(t, reg.register(cltA1))
+	require.NoError(t, reg.register(cltA1))
+	require.Equal(t, 1, reg.sessionConnectionsNum("testkey_A"))
+}
+
 // TestSessRegDeregistration tests deregistration
 func TestSessRegDeregistration(t *testing.T) {
-	reg := newSessionRegistry(0)
+	reg := newSessionRegistry(0, false)
 
 	// Register 2 connections on two separate sessions
 	cltA1 := newConnection(nil, "", nil, nil)
@@ -148,10 +198,56 @@ func TestSessRegDeregistration(t *testing.T) {
 	require.Equal(t, -1, reg.sessionConnectionsNum("testkey_B"))
 }
 
+// TestSessRegDeregistrationUnregistered tests deregistration
+// of a connection that isn't registered with its session
+func TestSessRegDeregistrationUnregistered(t *testing.T) {
+	reg := newSessionRegistry(0, false)
+
+	// Register a connection on session A
+	cltA1 := newConnection(nil, "", nil, nil)
+	sessA := NewSession(nil, func() string { return "testkey_A" })
+	cltA1.session = &sessA
+	require.NoError(t, reg.register(cltA1))
+
+	// Deregister another connection referencing the same session
+	cltA2 := newConnection(nil, "", nil, nil)
+	cltA2.session = &sessA
+	require.Equal(t, -1, reg.deregister(cltA2))
+
+	// Expect the session to remain registered with the first connection
+	require.Equal(t, 1, reg.activeSessionsNum())
+	require.Equal(t, 1, reg.sessionConnectionsNum("testkey_A"))
+}
+
+// TestSessRegReregistration tests whether registering a connection
+// with another session moves it out of its current session
+func TestSessRegReregistration(t *testing.T) {
+	reg := newSessionRegistry(0, false)
+
+	clt := newConnection(nil, "", nil, nil)
+	sessA := NewSession(nil, func() string { return "testkey_A" })
+	clt.session = &sessA
+	require.NoError(t, reg.register(clt))
+
+	// Switch the connection to session B
+	sessB := NewSession(nil, func() string { return "testkey_B" })
+	clt.session = &sessB
+	require.NoError(t, reg.register(clt))
+
+	// Expect session A to be removed
+	require.Equal(t, 1, reg.activeSessionsNum())
+	require.Equal(t, -1, reg.sessionConnectionsNum("testkey_A"))
+	require.Equal(t, 1, reg.sessionConnectionsNum("testkey_B"))
+
+	// Expect deregistration to remove the connection from session B
+	require.Equal(t, 0, reg.deregister(clt))
+	require.Equal(t, 0, reg.activeSessionsNum())
+}
+
 // TestSessRegDeregistrationMultiple tests deregistration of multiple
 // connections of a single session
 func TestSessRegDeregistrationMultiple(t *testing.T) {
-	reg := newSessionRegistry(0)
+	reg := newSessionRegistry(0, false)
 
 	// Register 2 connections on the same session
 	cltA1 := newConnection(nil, "", nil, nil)
@@ -192,7 +288,7 @@ func TestSessRegDeregistrationMultiple(t *testing.T) {
 
 // TestSessRegConcurrentAccess tests concurrent (de)registration
 func TestSessRegConcurrentAccess(t *testing.T) {
-	reg := newSessionRegistry(0)
+	reg := newSessionRegistry(0, false)
 	connsToRegister := uint(16)
 	registeredConns := make([]*connection, connsToRegister)
 	var awaitRegistration sync.WaitGroup
@@ -259,7 +355,7 @@ func TestSessRegConcurrentAccess(t *testing.T) {
 // TestSessRegSessionConnections tests the sessionConnections method
 func TestSessRegSessionConnections(t *testing.T) {
 	expectedSessionsNum := 1
-	reg := newSessionRegistry(0)
+	reg := newSessionRegistry(0, false)
 
 	// Register first connection on session A
 	cltA1 := newConnection(nil, "A1", nil, nil)
@@ -284,3 +380,218 @@ func TestSessRegSessionConnections(t *testing.T) {
 	require.Contains(t, list, cltA1)
 	require.Contains(t, list, cltA2)
 }
+
+// TestSessRegUnregisteredNum tests the unregisteredNum method
+func TestSessRegUnregisteredNum(t *testing.T) {
+	reg := newSessionRegistry(0, false)
+
+	// Register 2 connections on a session
+	sess := NewSession(nil, func() string { return "testkey_A" })
+	cltA1 := newConnection(nil, "", nil, nil)
+	cltA1.session = &sess
+	cltA2 := newConnection(nil, "", nil, nil)
+	cltA2.session = &sess
+	require.NoError(t, reg.register(cltA1))
+	require.NoError(t, reg.register(cltA2))
+
+	// Leave 2 connections anonymous
+	anonymous1 := newConnection(nil, "", nil, nil)
+	anonymous2 := newConnection(nil, "", nil, nil)
+
+	require.Equal(t, 2, reg.unregisteredNum([]*connection{
+		cltA1,
+		anonymous1,
+		cltA2,
+		anonymous2,
+	}))
+	require.Equal(t, 0, reg.unregisteredNum(nil))
+}
+
+// TestSessRegObserversUnlimited tests whether observer connections
+// don't count towards the maximum number of connections per session
+// if they're exempt from the limit
+func TestSessRegObserversUnlimited(t *testing.T) {
+	reg := newSessionRegistry(1, true)
+	sess := NewSession(nil, func() string { return "testkey_A" })
+
+	clt := newConnection(nil, "", nil, nil)
+	clt.session = &sess
+	require.NoError(t, reg.register(clt))
+
+	// Expect observers to be registered despite the limit
+	observer := newConnection(nil, "", nil, nil)
+	observer.info.Observer = true
+	observer.session = &sess
+	require.NoError(t, reg.register(observer))
+
+	// Expect regular connections to still be limited
+	clt2 := newConnection(nil, "", nil, nil)
+	clt2.session = &sess
+	require.Error(t, reg.register(clt2))
+
+	require.Equal(t, 2, reg.sessionConnectionsNum("testkey_A"))
+}
+
+// TestSessRegObserversLimited tests whether observer connections
+// count towards the maximum number of connections per session
+// unless they're exempt from the limit
+func TestSessRegObserversLimited(t *testing.T) {
+	reg := newSessionRegistry(1, false)
+	sess := NewSession(nil, func() string { return "testkey_A" })
+
+	clt := newConnection(nil, "", nil, nil)
+	clt.session = &sess
+	require.NoError(t, reg.register(clt))
+
+	observer := newConnection(nil, "", nil, nil)
+	observer.info.Observer = true
+	observer.session = &sess
+	require.Equal(t, MaxSessConnsReachedErr{}, reg.register(observer))
+
+	require.Equal(t, 1, reg.sessionConnectionsNum("testkey_A"))
+}
+
+// TestSessRegWatch tests whether watchers receive the registry changes
+func TestSessRegWatch(t *testing.T) {
+	reg := newSessionRegistry(0, false)
+	events, _ := reg.watch()
+	sess := NewSession(nil, func() string { return "testkey_A" })
+
+	clt1 := newConnection(nil, "", nil, nil)
+	clt1.session = &sess
+	clt2 := newConnection(nil, "", nil, nil)
+	clt2.session = &sess
+
+	require.NoError(t, reg.register(clt1))
+	require.NoError(t, reg.register(clt2))
+	require.Equal(t, 1, reg.deregister(clt1))
+	require.Equal(t, 0, reg.deregister(clt2))
+
+	expected := []struct {
+		eventType SessionEventType
+		conn      *connection
+	}{
+		{SessionEventCreated, clt1},
+		{SessionEventConnectionAdded, clt1},
+		{SessionEventConnectionAdded, clt2},
+		{SessionEventConnectionRemoved, clt1},
+		{SessionEventConnectionRemoved, clt2},
+		{SessionEventClosed, clt2},
+	}
+	for _, exp := range expected {
+		event := <-events
+		require.Equal(t, exp.eventType, event.Type)
+		require.Equal(t, "testkey_A", event.SessionKey)
+		require.Equal(t, exp.conn, event.Connection)
+	}
+
+	// Expect the channel to be closed
+	reg.closeWatchers()
+	_, open := <-events
+	require.False(t, open)
+	closed, cancel := reg.watch()
+	_, open = <-closed
+	require.False(t, open)
+	cancel()
+}
+
+// TestSessRegUnwatch tests whether cancelled watchers are unsubscribed
+// and their channels closed
+func TestSessRegUnwatch(t *testing.T) {
+	reg := newSessionRegistry(0, false)
+	eventsA, cancelA := reg.watch()
+	eventsB, cancelB := reg.watch()
+	defer cancelB()
+
+	cancelA()
+	require.Len(t, reg.watchers, 1)
+	_, open := <-eventsA
+	require.False(t, open)
+
+	// Expect cancelling twice to do nothing
+	cancelA()
+	require.Len(t, reg.watchers, 1)
+
+	// Expect only the remaining watcher to receive events
+	sess := NewSession(nil, func() string { return "testkey_A" })
+	clt := newConnection(nil, "", nil, nil)
+	clt.session = &sess
+	require.NoError(t, reg.register(clt))
+	require.Len(t, eventsB, 2)
+}
+
+// TestSessRegWatchOverflow tests whether the oldest events are dropped
+// when a watcher doesn't keep up with the registry changes
+func TestSessRegWatchOverflow(t *testing.T) {
+	reg := newSessionRegistry(0, false)
+	events, _ := reg.watch()
+	sess := NewSession(nil, func() string { return "testkey_A" })
+
+	// Register as many connections as fit into the buffer
+	// overflowing it by the preceding creation event
+	conns := make([]*connection, sessionEventsBufferSize)
+	for i := range conns {
+		conns[i] = newConnection(nil, "", nil, nil)
+		conns[i].session = &sess
+		require.NoError(t, reg.register(conns[i]))
+	}
+
+	require.Len(t, events, sessionEventsBufferSize)
+
+	// Expect the creation event to be dropped
+	event := <-events
+	require.Equal(t, SessionEventConnectionAdded, event.Type)
+	require.Equal(t, conns[0], event.Connection)
+}
+
+// TestSessRegRejectedMove tests whether a connection remains registered
+// with its current session if moving it to another session is rejected
+// due to the connection limit of the other session
+func TestSessRegRejectedMove(t *testing.T) {
+	reg := newSessionRegistry(1, false)
+
+	cltA := newConnection(nil, "", nil, nil)
+	sessA := NewSession(nil, func() string { return "testkey_A" })
+	cltA.session = &sessA
+	require.NoError(t, reg.register(cltA))
+
+	cltB := newConnection(nil, "", nil, nil)
+	sessB := NewSession(nil, func() string { return "testkey_B" })
+	cltB.session = &sessB
+	require.NoError(t, reg.register(cltB))
+
+	// Try to move the connection of session B to session A
+	cltB.session = &sessA
+	require.Equal(t, MaxSessConnsReachedErr{}, reg.register(cltB))
+
+	// Expect the connection to remain registered with session B
+	require.Equal(t, 1, reg.sessionConnectionsNum("testkey_A"))
+	require.Equal(t, 1, reg.sessionConnectionsNum("testkey_B"))
+	require.Equal(t, 0, reg.deregister(cltB))
+	require.Equal(t, -1, reg.sessionConnectionsNum("testkey_B"))
+}
+
+// TestSessRegRegisterNew tests whether registering a connection
+// with a new session is refused if the session key is already active
+// and whether it moves the connection out of its current session otherwise
+func TestSessRegRegisterNew(t *testing.T) {
+	reg := newSessionRegistry(0, false)
+
+	cltA := newConnection(nil, "", nil, nil)
+	remaining, registered := reg.registerNew(cltA, "testkey_A")
+	require.True(t, registered)
+	require.Equal(t, -1, remaining)
+
+	// Expect the colliding key to be refused
+	cltB := newConnection(nil, "", nil, nil)
+	_, registered = reg.registerNew(cltB, "testkey_A")
+	require.False(t, registered)
+	require.Equal(t, 1, reg.sessionConnectionsNum("testkey_A"))
+
+	// Expect the connection to be moved to the new session
+	remaining, registered = reg.registerNew(cltA, "testkey_B")
+	require.True(t, registered)
+	require.Equal(t, 0, remaining)
+	require.Equal(t, -1, reg.sessionConnectionsNum("testkey_A"))
+	require.Equal(t, 1, reg.sessionConnectionsNum("testkey_B"))
+}
@@ -0,0 +1,12 @@
+package webwire
+
+// SignalResult represents the result of an attempt to send a signal
+// to a particular connection
+type SignalResult struct {
+	// Connection is the connection the signal was sent to
+	Connection
+
+	// Err is the error that occurred while sending the signal
+	// to the connection or nil if the signal was sent successfully
+	Err error
+}
@@ -11,6 +11,11 @@ type SockReadErr interface {
 	// IsAbnormalCloseErr must return true if the error represents
 	// an abnormal closure error
 	IsAbnormalCloseErr() bool
+
+	// CloseFrame must return the close code and reason sent by the other
+	// side if the socket was closed by a close frame,
+	// otherwise ok must be false
+	CloseFrame() (code int, reason string, ok bool)
 }
 
 // Socket defines the abstract socket implementation interface
@@ -37,6 +42,14 @@ type Socket interface {
 	// Close must close the socket
 	Close() error
 
+	// CloseWithReason must notify the other side of the socket about
+	// the given reason of closure and close the socket
+	CloseWithReason(reason string) error
+
+	// CloseWithCode must notify the other side of the socket about
+	// the given close code and reason of closure and close the socket
+	CloseWithCode(code int, reason string) error
+
 	// SetReadDeadline must set the readers deadline
 	SetReadDeadline(deadline time.Time) error
 
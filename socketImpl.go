@@ -1,12 +1,15 @@
 package webwire
 
 import (
+	"compress/flate"
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
 	"net/url"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gorilla/websocket"
 )
@@ -15,16 +18,61 @@ import (
 // the gorilla/websocket library
 type connUpgrader struct {
 	gorillaWsUpgrader websocket.Upgrader
+	maxMessageSize    int64
+	compression       compression
+	chunking          chunking
+}
+
+// compression represents the permessage-deflate compression configuration
+// of a socket
+type compression struct {
+	enabled bool
+	// level is the flate compression level of outgoing messages
+	level int
+	// threshold is the minimum size in bytes of outgoing messages
+	// to be compressed
+	threshold int
+}
+
+// chunking represents the configuration of chunked writes of large messages
+type chunking struct {
+	// size is the maximum number of bytes written at once,
+	// messages are written at once if it's zero
+	size int
+	// timeout is the write deadline applied to each chunk
+	timeout time.Duration
+	// progress is invoked after each written chunk if it's not nil.
+	// It's invoked while the socket is locked for writing, thus it must
+	// neither block nor use the socket or its connection
+	progress func(remoteAddr net.Addr, written, total int)
+}
+
+// isValidCompressionLevel returns true if the given level
+// is a valid flate compression level, otherwise returns false
+func isValidCompressionLevel(level int) bool {
+	return level >= flate.HuffmanOnly && level <= flate.BestCompression
 }
 
 // newConnUpgrader constructs a new default HTTP connection upgrader
-// based on gorilla/websocket
-func newConnUpgrader() *connUpgrader {
+// based on gorilla/websocket limiting incoming messages
+// to the given size in bytes. Upgrade requests are verified by the given
+// origin checker, only same-origin requests are accepted if it's nil.
+// Permessage-deflate compression is negotiated if it's enabled.
+// Messages larger than the chunk size are written in chunks
+// if chunking is enabled
+func newConnUpgrader(
+	maxMessageSize int64,
+	checkOrigin func(r *http.Request) bool,
+	compression compression,
+	chunking chunking,
+) *connUpgrader {
 	return &connUpgrader{
+		maxMessageSize: maxMessageSize,
+		compression:    compression,
+		chunking:       chunking,
 		gorillaWsUpgrader: websocket.Upgrader{
-			CheckOrigin: func(_ *http.Request) bool {
-				return true
-			},
+			CheckOrigin:       checkOrigin,
+			EnableCompression: compression.enabled,
 		},
 	}
 }
@@ -38,7 +86,22 @@ func (upgrader *connUpgrader) Upgrade(
 	if err != nil {
 		return nil, err
 	}
-	return newConnectedSocket(conn), nil
+
+	// Close the connection when a message exceeds the size limit
+	conn.SetReadLimit(upgrader.maxMessageSize)
+
+	if upgrader.compression.enabled {
+		err := conn.SetCompressionLevel(upgrader.compression.level)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	sock := newConnectedSocket(conn).(*socket)
+	sock.compression = upgrader.compression
+	sock.chunking = upgrader.chunking
+	return sock, nil
 }
 
 // sockReadErr implements the webwire.SockReadErr interface using
@@ -61,12 +124,30 @@ func (err sockReadErr) IsAbnormalCloseErr() bool {
 	)
 }
 
+// CloseFrame implements the webwire.SockReadErr interface
+func (err sockReadErr) CloseFrame() (code int, reason string, ok bool) {
+	if closeErr, isCloseErr := err.cause.(*websocket.CloseError); isCloseErr {
+		return closeErr.Code, closeErr.Text, true
+	}
+	return 0, "", false
+}
+
 // socket implements the webwire.Socket interface using
 // the gorilla/websocket library
 type socket struct {
 	connected bool
 	lock      sync.RWMutex
 	conn      *websocket.Conn
+	// header is sent along with the upgrade request when dialing
+	header http.Header
+	// dialer establishes the connection when dialing
+	dialer *websocket.Dialer
+	// scheme is the URL scheme used when dialing
+	scheme string
+	// compression defines whether and how outgoing messages are compressed
+	compression compression
+	// chunking defines whether and how large outgoing messages are chunked
+	chunking chunking
 }
 
 // newConnectedSocket creates a new gorilla/websocket based socket instance
@@ -79,31 +160,120 @@ func newConnectedSocket(conn *websocket.Conn) Socket {
 		connected: connected,
 		lock:      sync.RWMutex{},
 		conn:      conn,
+		dialer:    websocket.DefaultDialer,
+		scheme:    "ws",
 	}
 }
 
 // NewSocket creates a new disconnected gorilla/websocket based socket instance
 func NewSocket() Socket {
+	return NewSocketWithHeader(nil)
+}
+
+// NewSocketWithHeader creates a new disconnected gorilla/websocket based
+// socket instance sending the given HTTP header when dialing the server
+func NewSocketWithHeader(header http.Header) Socket {
+	return NewDialingSocket(DialerOptions{Header: header})
+}
+
+// DialerOptions represents the options used by a socket
+// when dialing the server
+type DialerOptions struct {
+	// Header is sent along with the upgrade request
+	Header http.Header
+
+	// TLSConfig defines the TLS configuration used when dialing.
+	// The socket connects over TLS (wss) if it's defined
+	TLSConfig *tls.Config
+
+	// HandshakeTimeout defines the duration for the handshake to complete.
+	// If undefined then the default value of 45 seconds is applied
+	HandshakeTimeout time.Duration
+
+	// Proxy defines the function returning the proxy for a given request.
+	// If undefined then the proxy is determined by the environment variables
+	Proxy func(*http.Request) (*url.URL, error)
+
+	// Compression defines whether permessage-deflate compression
+	// is to be negotiated with the server
+	Compression bool
+
+	// CompressionLevel defines the flate compression level
+	// of outgoing messages (see compress/flate).
+	// If undefined (zero) then flate.BestSpeed is applied
+	CompressionLevel int
+
+	// CompressionThreshold defines the minimum size in bytes
+	// of outgoing messages to be compressed.
+	// If undefined (zero) then all messages are compressed
+	CompressionThreshold int
+}
+
+// NewDialingSocket creates a new disconnected gorilla/websocket based
+// socket instance dialing the server using the given options
+func NewDialingSocket(opts DialerOptions) Socket {
+	dialer := *websocket.DefaultDialer
+	if opts.TLSConfig != nil {
+		dialer.TLSClientConfig = opts.TLSConfig
+	}
+	if opts.HandshakeTimeout > 0 {
+		dialer.HandshakeTimeout = opts.HandshakeTimeout
+	}
+	if opts.Proxy != nil {
+		dialer.Proxy = opts.Proxy
+	}
+
+	scheme := "ws"
+	if opts.TLSConfig != nil {
+		scheme = "wss"
+	}
+
+	dialer.EnableCompression = opts.Compression
+	compressionLevel := opts.CompressionLevel
+	if compressionLevel == 0 {
+		compressionLevel = flate.BestSpeed
+	}
+
 	connected := false
 	return &socket{
 		connected: connected,
 		lock:      sync.RWMutex{},
+		header:    opts.Header,
+		dialer:    &dialer,
+		scheme:    scheme,
+		compression: compression{
+			enabled:   opts.Compression,
+			level:     compressionLevel,
+			threshold: opts.CompressionThreshold,
+		},
 	}
 }
 
 // Dial implements the webwire.Socket interface
 func (sock *socket) Dial(serverAddr string) (err error) {
-	connURL := url.URL{Scheme: "ws", Host: serverAddr, Path: "/"}
+	connURL := url.URL{Scheme: sock.scheme, Host: serverAddr, Path: "/"}
 	sock.lock.Lock()
 	defer sock.lock.Unlock()
 	if sock.connected {
 		sock.conn.Close()
 		sock.conn = nil
+		sock.connected = false
 	}
-	sock.conn, _, err = websocket.DefaultDialer.Dial(connURL.String(), nil)
+	sock.conn, _, err = sock.dialer.Dial(
+		connURL.String(),
+		sock.header,
+	)
 	if err != nil {
 		return NewDisconnectedErr(fmt.Errorf("Dial failure: %s", err))
 	}
+	if sock.compression.enabled {
+		err = sock.conn.SetCompressionLevel(sock.compression.level)
+		if err != nil {
+			sock.conn.Close()
+			sock.conn = nil
+			return fmt.Errorf("invalid compression level: %s", err)
+		}
+	}
 	sock.connected = true
 	return nil
 }
@@ -117,9 +287,69 @@ func (sock *socket) Write(data []byte) error {
 			Cause: fmt.Errorf("Can't write to a socket"),
 		}
 	}
+	if sock.compression.enabled {
+		// Don't waste time compressing messages below the threshold
+		sock.conn.EnableWriteCompression(
+			len(data) >= sock.compression.threshold,
+		)
+	}
+	if sock.chunking.size > 0 && len(data) > sock.chunking.size {
+		return sock.writeChunked(data)
+	}
 	return sock.conn.WriteMessage(websocket.BinaryMessage, data)
 }
 
+// writeChunked writes the given data as a single message in chunks
+// applying the write deadline to each chunk individually to prevent
+// slow clients from blocking the socket indefinitely.
+// Expects the lock to be held by the caller, the lock can't be released
+// between the chunks because they're frames of a single message,
+// which is why the progress callback is invoked under the lock
+func (sock *socket) writeChunked(data []byte) error {
+	// Reset the write deadline when done
+	defer sock.conn.SetWriteDeadline(time.Time{})
+
+	writer, err := sock.conn.NextWriter(websocket.BinaryMessage)
+	if err != nil {
+		return err
+	}
+
+	remoteAddr := sock.conn.RemoteAddr()
+	written := 0
+	for written < len(data) {
+		end := written + sock.chunking.size
+		if end > len(data) {
+			end = len(data)
+		}
+		if err := sock.conn.SetWriteDeadline(
+			time.Now().Add(sock.chunking.timeout),
+		); err != nil {
+			return err
+		}
+		num, err := writer.Write(data[written:end])
+		written += num
+		if err != nil {
+			return fmt.Errorf(
+				"Couldn't write chunk (%d/%d bytes written): %s",
+				written,
+				len(data),
+				err,
+			)
+		}
+		if sock.chunking.progress != nil {
+			sock.chunking.progress(remoteAddr, written, len(data))
+		}
+	}
+
+	// Flush the last chunk
+	if err := sock.conn.SetWriteDeadline(
+		time.Now().Add(sock.chunking.timeout),
+	); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
 // Read implements the webwire.Socket interface
 func (sock *socket) Read() ([]byte, SockReadErr) {
 	_, message, err := sock.conn.ReadMessage()
@@ -154,6 +384,48 @@ func (sock *socket) Close() error {
 	return sock.conn.Close()
 }
 
+// CloseWithReason implements the webwire.Socket interface
+func (sock *socket) CloseWithReason(reason string) error {
+	return sock.CloseWithCode(websocket.ClosePolicyViolation, reason)
+}
+
+// CloseWithCode implements the webwire.Socket interface
+func (sock *socket) CloseWithCode(code int, reason string) error {
+	reason = truncateCloseReason(reason)
+
+	sock.lock.Lock()
+	defer sock.lock.Unlock()
+	sock.connected = false
+	writeErr := sock.conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(time.Second),
+	)
+	if err := sock.conn.Close(); err != nil {
+		return err
+	}
+	return writeErr
+}
+
+// maxCloseReasonLen is the maximum length of a close reason in bytes.
+// The payload of a control frame mustn't exceed 125 bytes,
+// 2 of which are occupied by the close code
+const maxCloseReasonLen = 123
+
+// truncateCloseReason truncates the given close reason to the maximum
+// close reason length without splitting UTF-8 encoded characters
+// because the close reason must be valid UTF-8
+func truncateCloseReason(reason string) string {
+	if len(reason) <= maxCloseReasonLen {
+		return reason
+	}
+	end := maxCloseReasonLen
+	for end > 0 && !utf8.RuneStart(reason[end]) {
+		end--
+	}
+	return reason[:end]
+}
+
 // SetReadDeadline implements the webwire.Socket interface
 func (sock *socket) SetReadDeadline(deadline time.Time) error {
 	return sock.conn.SetReadDeadline(deadline)
@@ -164,12 +436,36 @@ func (sock *socket) OnPong(handler func(string) error) {
 	sock.conn.SetPongHandler(handler)
 }
 
-// OnPing implements the webwire.Socket interface
+// OnPing implements the webwire.Socket interface.
+// Pings are still answered with a pong after the handler is invoked
+// just like the default handler of gorilla/websocket does
 func (sock *socket) OnPing(handler func(string) error) {
-	sock.conn.SetPingHandler(handler)
+	sock.conn.SetPingHandler(func(data string) error {
+		if err := handler(data); err != nil {
+			return err
+		}
+		err := sock.conn.WriteControl(
+			websocket.PongMessage,
+			[]byte(data),
+			time.Now().Add(time.Second),
+		)
+		if err == websocket.ErrCloseSent {
+			return nil
+		} else if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+			return nil
+		}
+		return err
+	})
 }
 
 // WritePing implements the webwire.Socket interface
 func (sock *socket) WritePing(data []byte, deadline time.Time) error {
+	sock.lock.RLock()
+	defer sock.lock.RUnlock()
+	if !sock.connected {
+		return DisconnectedErr{
+			Cause: fmt.Errorf("Can't write to a socket"),
+		}
+	}
 	return sock.conn.WriteControl(websocket.PingMessage, data, deadline)
 }
@@ -0,0 +1,30 @@
+package webwire
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestTruncateCloseReason tests whether close reasons are truncated
+// to the maximum close reason length without splitting characters
+func TestTruncateCloseReason(t *testing.T) {
+	// Short reasons are kept
+	require.Equal(t, "short", truncateCloseReason("short"))
+
+	// ASCII reasons are cut at the maximum length
+	ascii := strings.Repeat("a", 200)
+	require.Equal(t, ascii[:maxCloseReasonLen], truncateCloseReason(ascii))
+
+	// Multi-byte characters crossing the limit are dropped entirely
+	for offset := 0; offset < 4; offset++ {
+		reason := strings.Repeat("a", offset) + strings.Repeat("€", 60)
+		truncated := truncateCloseReason(reason)
+		require.True(t, len(truncated) <= maxCloseReasonLen)
+		require.True(t, len(truncated) > maxCloseReasonLen-3)
+		require.True(t, utf8.ValidString(truncated))
+		require.True(t, strings.HasPrefix(reason, truncated))
+	}
+}
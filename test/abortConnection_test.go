@@ -0,0 +1,105 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// awaitAbortedConnection sets up a server aborting the connection
+// with the given error on request and returns the error
+// the client is disconnected with
+func awaitAbortedConnection(t *testing.T, abortErr error) error {
+	disconnected := make(chan error, 1)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, abortErr
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{
+			OnDisconnected: func(err error) {
+				disconnected <- err
+			},
+		},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Expect the request to fail
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		200*time.Millisecond,
+	)
+	defer cancel()
+	_, err := client.connection.Request(
+		ctx,
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+	)
+	require.Error(t, err)
+
+	select {
+	case err := <-disconnected:
+		return err
+	case <-time.After(2 * time.Second):
+		t.Fatal("Client not disconnected")
+	}
+	return nil
+}
+
+// TestAbortConnection tests whether request handlers can close
+// the connection with a custom close code and reason
+// which are propagated to the client
+func TestAbortConnection(t *testing.T) {
+	require.Equal(t, wwr.ConnClosedErr{
+		Code:   4001,
+		Reason: "auth expired",
+	}, awaitAbortedConnection(t, wwr.AbortConnection(4001, "auth expired")))
+}
+
+// TestAbortConnectionPointer tests whether request handlers can close
+// the connection returning a pointer to an AbortConnectionErr error
+func TestAbortConnectionPointer(t *testing.T) {
+	require.Equal(t, wwr.ConnClosedErr{
+		Code:   4002,
+		Reason: "banned",
+	}, awaitAbortedConnection(t, &wwr.AbortConnectionErr{
+		Code:   4002,
+		Reason: "banned",
+	}))
+}
+
+// TestAbortConnectionInvalidCode tests whether connections aborted
+// with close codes that can't be sent in a close frame
+// are closed with a policy violation instead
+func TestAbortConnectionInvalidCode(t *testing.T) {
+	for _, code := range []int{0, 999, 1005, 1006, 1015, 2000, 5000} {
+		require.Equal(t, wwr.ConnClosedErr{
+			Code:   1008,
+			Reason: "aborted",
+		}, awaitAbortedConnection(t, wwr.AbortConnection(code, "aborted")))
+	}
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestAnonymousConnectionsNum tests counting the active connections
+// without a session
+func TestAnonymousConnectionsNum(t *testing.T) {
+	connected := tmdwg.NewTimedWaitGroup(3, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(_ wwr.Connection) error {
+				connected.Progress(1)
+				return nil
+			},
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Try to create a new session
+				err := conn.CreateSession(nil)
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize 1 authenticated and 2 anonymous clients
+	clients := make([]*callbackPoweredClient, 3)
+	for i := range clients {
+		clients[i] = newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+			},
+			callbackPoweredClientHooks{},
+		)
+		defer clients[i].connection.Close()
+		require.NoError(t, clients[i].connection.Connect())
+	}
+	require.NoError(t, connected.Wait(), "Clients not connected")
+	require.Equal(t, 3, server.AnonymousConnectionsNum())
+
+	_, err := clients[0].connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, server.AnonymousConnectionsNum())
+}
@@ -0,0 +1,147 @@
+package test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestAsyncSessionPersistence tests whether the client receives its session
+// before a slow session manager finishes persisting the session
+// when asynchronous session persistence is enabled
+func TestAsyncSessionPersistence(t *testing.T) {
+	release := make(chan struct{})
+	persisted := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	persistedFlag := int32(0)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Try to create a new session
+				err := conn.CreateSession(nil)
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{
+			AsyncSessionPersistence: wwr.Enabled,
+			SessionManager: &callbackPoweredSessionManager{
+				SessionCreated: func(_ wwr.Connection) error {
+					// Simulate a slow persistent store
+					<-release
+					atomic.StoreInt32(&persistedFlag, 1)
+					persisted.Progress(1)
+					return nil
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Expect the login to return before the session is persisted
+	_, err := client.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, client.connection.Session())
+	require.Equal(t, int32(0), atomic.LoadInt32(&persistedFlag))
+
+	// Let the session manager finish persisting the session
+	close(release)
+	require.NoError(t, persisted.Wait(), "Session not persisted")
+}
+
+// TestAsyncSessionPersistenceSnapshot tests whether the asynchronously
+// invoked OnSessionCreated hook is passed the created session rather than
+// the session the connection holds at the time the hook reads it
+func TestAsyncSessionPersistenceSnapshot(t *testing.T) {
+	release := make(chan struct{})
+	var createdKey atomic.Value
+	var persistedKey atomic.Value
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Create a new session and close it right away
+				// before the session manager persists it
+				assert.NoError(t, conn.CreateSession(nil))
+				createdKey.Store(conn.SessionKey())
+				assert.NoError(t, conn.CloseSession())
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			AsyncSessionPersistence: wwr.Enabled,
+			SessionManager: &callbackPoweredSessionManager{
+				SessionCreated: func(conn wwr.Connection) error {
+					// Simulate a slow persistent store
+					<-release
+					persistedKey.Store(conn.SessionKey())
+					return nil
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	require.NoError(t, err)
+
+	// Let the session manager finish and await all ongoing operations
+	close(release)
+	require.NoError(t, server.Shutdown())
+
+	// Expect the hook to either be skipped for the closed session
+	// or to be passed the created session
+	if key := persistedKey.Load(); key != nil {
+		require.Equal(t, createdKey.Load(), key)
+	}
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestAwaitConnections tests whether the server deterministically awaits
+// the establishment and the loss of concurrently connecting clients
+func TestAwaitConnections(t *testing.T) {
+	clientsNum := 16
+
+	// Initialize webwire server
+	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{})
+
+	// Concurrently connect the clients
+	connecting := sync.WaitGroup{}
+	connecting.Add(clientsNum)
+	clients := make([]*callbackPoweredClient, clientsNum)
+	for i := range clients {
+		clients[i] = newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				Autoconnect: wwr.Disabled,
+			},
+			callbackPoweredClientHooks{},
+		)
+		defer clients[i].connection.Close()
+		go func(client *callbackPoweredClient) {
+			defer connecting.Done()
+			assert.NoError(t, client.connection.Connect())
+		}(clients[i])
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// Expect all clients to be connected
+	require.NoError(t, server.AwaitConnections(ctx, clientsNum))
+	require.Len(t, server.Connections(), clientsNum)
+
+	// Expect all clients to be disconnected
+	// once they're closed after connecting
+	connecting.Wait()
+	for _, client := range clients {
+		client.connection.Close()
+	}
+	require.NoError(t, server.AwaitConnections(ctx, 0))
+	require.Len(t, server.Connections(), 0)
+}
+
+// TestAwaitConnectionsDeadline tests whether awaiting connections
+// fails when the context deadline is exceeded
+func TestAwaitConnectionsDeadline(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{})
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		50*time.Millisecond,
+	)
+	defer cancel()
+
+	err := server.AwaitConnections(ctx, 1)
+	require.IsType(t, wwr.DeadlineExceededErr{}, err)
+}
@@ -0,0 +1,92 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestBinarySessionCodec tests session creation and restoration
+// using the binary session codec on both the server and the client
+func TestBinarySessionCodec(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				err := conn.CreateSession(&testAuthenticationSessInfo{
+					UserIdent:  "clientidentifiergoeshere",
+					SomeNumber: 12345,
+				})
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{
+			SessionCodec: wwr.BinarySessionCodec{},
+		},
+	)
+
+	clientOpts := wwrclt.Options{
+		DefaultRequestTimeout: 2 * time.Second,
+		Autoconnect:           wwr.Disabled,
+		SessionCodec:          wwr.BinarySessionCodec{},
+	}
+
+	// Create a session
+	initialClient := newCallbackPoweredClient(
+		server.Addr().String(),
+		clientOpts,
+		callbackPoweredClientHooks{},
+	)
+	require.NoError(t, initialClient.connection.Connect())
+
+	_, err := initialClient.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	require.NoError(t, err)
+
+	createdSession := initialClient.connection.Session()
+	require.NotNil(t, createdSession)
+	require.Equal(t,
+		"clientidentifiergoeshere",
+		createdSession.Info.Value("uid"),
+	)
+	initialClient.connection.Close()
+
+	// Restore the session on another client
+	secondClient := newCallbackPoweredClient(
+		server.Addr().String(),
+		clientOpts,
+		callbackPoweredClientHooks{},
+	)
+	defer secondClient.connection.Close()
+	require.NoError(t, secondClient.connection.Connect())
+
+	require.NoError(t, secondClient.connection.RestoreSession(
+		[]byte(createdSession.Key),
+	))
+
+	restoredSession := secondClient.connection.Session()
+	compareSessions(t, createdSession, restoredSession)
+	require.Equal(t,
+		createdSession.Info.Value("uid"),
+		restoredSession.Info.Value("uid"),
+	)
+	require.Equal(t,
+		createdSession.Info.Value("some-number"),
+		restoredSession.Info.Value("some-number"),
+	)
+}
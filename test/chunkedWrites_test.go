@@ -0,0 +1,230 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// throttlingProxy forwards TCP connections to the given address
+// throttling the bytes sent from the target back to the clients
+// to at most readSize bytes per delay
+type throttlingProxy struct {
+	listener net.Listener
+	readSize int
+	delay    time.Duration
+	// stalled stops forwarding to the clients when set
+	stalled int32
+}
+
+// newThrottlingProxy starts a new throttling proxy forwarding
+// to the given address
+func newThrottlingProxy(
+	t *testing.T,
+	target string,
+	readSize int,
+	delay time.Duration,
+) *throttlingProxy {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	proxy := &throttlingProxy{
+		listener: listener,
+		readSize: readSize,
+		delay:    delay,
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			upstream, err := net.Dial("tcp", target)
+			if err != nil {
+				conn.Close()
+				return
+			}
+			closed := make(chan struct{})
+			go func() {
+				io.Copy(upstream, conn)
+				upstream.Close()
+				close(closed)
+			}()
+			go proxy.forward(conn, upstream, closed)
+		}
+	}()
+
+	return proxy
+}
+
+// stall stops forwarding to the clients
+// without closing the connections
+func (proxy *throttlingProxy) stall() {
+	atomic.StoreInt32(&proxy.stalled, 1)
+}
+
+// forward throttles the bytes sent from upstream to conn
+// until the upstream connection is closed
+func (proxy *throttlingProxy) forward(
+	conn,
+	upstream net.Conn,
+	closed <-chan struct{},
+) {
+	defer conn.Close()
+	buf := make([]byte, proxy.readSize)
+	for {
+		if atomic.LoadInt32(&proxy.stalled) == 1 {
+			<-closed
+			return
+		}
+		num, err := upstream.Read(buf)
+		if err != nil {
+			return
+		}
+		if _, err := conn.Write(buf[:num]); err != nil {
+			return
+		}
+		time.Sleep(proxy.delay)
+	}
+}
+
+// TestChunkedWrites tests whether large replies are written in chunks
+// reporting the progress when the client reads slowly
+func TestChunkedWrites(t *testing.T) {
+	chunkSize := 64 * 1024
+	data := bytes.Repeat([]byte("0123456789abcdef"), 64*1024)
+
+	var progressLock sync.Mutex
+	var written []int
+	var total []int
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return wwr.NewPayload(wwr.EncodingBinary, data), nil
+			},
+		},
+		wwr.ServerOptions{
+			WriteChunkSize:    chunkSize,
+			WriteChunkTimeout: 2 * time.Second,
+			WriteProgress: func(_ net.Addr, chunkWritten, chunkTotal int) {
+				progressLock.Lock()
+				defer progressLock.Unlock()
+				written = append(written, chunkWritten)
+				total = append(total, chunkTotal)
+			},
+		},
+	)
+
+	proxy := newThrottlingProxy(
+		t,
+		server.Addr().String(),
+		128*1024,
+		10*time.Millisecond,
+	)
+	defer proxy.listener.Close()
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		proxy.listener.Addr().String(),
+		wwrclt.Options{
+			Autoconnect:           wwr.Disabled,
+			DefaultRequestTimeout: 5 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	reply, err := client.connection.Request(
+		context.Background(),
+		"large",
+		nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, data, reply.Data())
+
+	// Expect the progress to be reported for each chunk
+	progressLock.Lock()
+	defer progressLock.Unlock()
+	require.True(t, len(written) >= len(data)/chunkSize)
+	require.Equal(t, total[len(total)-1], written[len(written)-1])
+	require.True(t, total[len(total)-1] > len(data))
+}
+
+// TestChunkedWritesTimeout tests whether writing a large reply to a client
+// that stops reading fails once the write deadline of a chunk is exceeded
+func TestChunkedWritesTimeout(t *testing.T) {
+	data := make([]byte, 64*1024*1024)
+	disconnected := make(chan struct{})
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientDisconnected: func(_ wwr.Connection) {
+				close(disconnected)
+			},
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return wwr.NewPayload(wwr.EncodingBinary, data), nil
+			},
+		},
+		wwr.ServerOptions{
+			WriteChunkSize:    64 * 1024,
+			WriteChunkTimeout: 200 * time.Millisecond,
+		},
+	)
+
+	proxy := newThrottlingProxy(t, server.Addr().String(), 4096, 0)
+	defer proxy.listener.Close()
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		proxy.listener.Addr().String(),
+		wwrclt.Options{
+			Autoconnect:           wwr.Disabled,
+			DefaultRequestTimeout: 5 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Let the client stop reading
+	proxy.stall()
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		200*time.Millisecond,
+	)
+	defer cancel()
+	_, err := client.connection.Request(ctx, "large", nil)
+	require.Error(t, err)
+
+	// Expect the server to give up writing to the stalled client
+	select {
+	case <-disconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Writing to the stalled client didn't time out")
+	}
+}
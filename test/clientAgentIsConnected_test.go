@@ -23,7 +23,7 @@ func TestClientConnIsConnected(t *testing.T) {
 	server := setupServer(
 		t,
 		&serverImpl{
-			onClientConnected: func(newConn wwr.Connection) {
+			onClientConnected: func(newConn wwr.Connection) error {
 				assert.True(t,
 					newConn.IsActive(),
 					"Expected connection to be active",
@@ -44,6 +44,7 @@ func TestClientConnIsConnected(t *testing.T) {
 
 					testerGoroutineFinished.Progress(1)
 				}()
+				return nil
 			},
 			onClientDisconnected: func(_ wwr.Connection) {
 				assert.False(t,
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -42,4 +43,10 @@ func TestClientConcurrentConnect(t *testing.T) {
 	}
 
 	require.NoError(t, finished.Wait(), "Expectation timed out")
+
+	// Expect the concurrent calls to establish a single connection
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+	require.NoError(t, server.AwaitConnections(ctx, 1))
+	require.Len(t, server.Connections(), 1)
 }
@@ -0,0 +1,55 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientConnectHandshake tests issuing a request
+// immediately after the connection is established
+func TestClientConnectHandshake(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				return msg.Payload(), nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	for i := 0; i < 10; i++ {
+		// Initialize client with autoconnect disabled
+		// to ensure the request doesn't wait for the connection
+		client := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+				Autoconnect:           wwr.Disabled,
+			},
+			callbackPoweredClientHooks{},
+		)
+
+		require.NoError(t, client.connection.Connect())
+		reply, err := client.connection.Request(
+			context.Background(),
+			"",
+			wwr.NewPayload(wwr.EncodingUtf8, []byte("test")),
+		)
+		require.NoError(t, err)
+		require.Equal(t, []byte("test"), reply.Data())
+
+		client.connection.Close()
+	}
+}
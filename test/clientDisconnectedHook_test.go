@@ -24,10 +24,11 @@ func TestClientDisconnectedHook(t *testing.T) {
 	server := setupServer(
 		t,
 		&serverImpl{
-			onClientConnected: func(conn wwr.Connection) {
+			onClientConnected: func(conn wwr.Connection) error {
 				connectedClientLock.Lock()
 				clientConn = conn
 				connectedClientLock.Unlock()
+				return nil
 			},
 			onClientDisconnected: func(conn wwr.Connection) {
 				connectedClientLock.Lock()
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+	"github.com/qbeon/webwire-go/message"
+)
+
+// TestClientHeartbeat tests whether the client remains connected
+// while the server answers the ping frames
+func TestClientHeartbeat(t *testing.T) {
+	disconnected := tmdwg.NewTimedWaitGroup(1, 300*time.Millisecond)
+
+	// Initialize webwire server
+	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{})
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Heartbeat:         wwr.Enabled,
+			HeartbeatInterval: 20 * time.Millisecond,
+			HeartbeatTimeout:  100 * time.Millisecond,
+		},
+		callbackPoweredClientHooks{
+			OnDisconnected: func(_ error) {
+				disconnected.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Expect the connection to outlive the heartbeat timeout
+	require.Error(t, disconnected.Wait(), "Client disconnected")
+	_, err := client.connection.Request(
+		context.Background(),
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+	)
+	require.NoError(t, err)
+}
+
+// TestClientHeartbeatTimeout tests whether the client closes the connection
+// when the server doesn't answer the ping frames in time
+func TestClientHeartbeatTimeout(t *testing.T) {
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Setup a server completing the handshake but never sending pongs
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(
+		resp http.ResponseWriter,
+		req *http.Request,
+	) {
+		if req.Method == "WEBWIRE" {
+			resp.Write([]byte(`{"protocol-version":"1.5"}`))
+			return
+		}
+		conn, err := upgrader.Upgrade(resp, req, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetPingHandler(func(string) error { return nil })
+		if err := conn.WriteMessage(
+			websocket.BinaryMessage,
+			message.NewHandshakeMessage("1.5", false),
+		); err != nil {
+			return
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		strings.TrimPrefix(server.URL, "http://"),
+		wwrclt.Options{
+			Autoconnect:       wwr.Disabled,
+			Heartbeat:         wwr.Enabled,
+			HeartbeatInterval: 20 * time.Millisecond,
+			HeartbeatTimeout:  100 * time.Millisecond,
+		},
+		callbackPoweredClientHooks{
+			OnDisconnected: func(_ error) {
+				disconnected.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	require.NoError(t, disconnected.Wait(), "Client not disconnected")
+	require.Equal(t, wwrclt.Disconnected, client.connection.Status())
+}
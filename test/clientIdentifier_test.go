@@ -0,0 +1,52 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientIdentifier tests whether the server reads
+// the identifier set by the client
+func TestClientIdentifier(t *testing.T) {
+	connected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	clientIdentifier := "Test Device (app v1.2.3)"
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) error {
+				assert.Equal(t, clientIdentifier, conn.ClientIdentifier())
+				assert.Equal(
+					t,
+					clientIdentifier,
+					conn.Info().ClientIdentifier,
+				)
+				connected.Progress(1)
+				return nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect:      wwr.Disabled,
+			ClientIdentifier: clientIdentifier,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+	require.NoError(t, connected.Wait(), "Client not connected")
+}
@@ -1,15 +1,21 @@
 package test
 
 import (
+	"context"
+
 	wwr "github.com/qbeon/webwire-go"
 	wwrclt "github.com/qbeon/webwire-go/client"
 )
 
 type callbackPoweredClientHooks struct {
-	OnSessionCreated func(*wwr.Session)
-	OnSessionClosed  func()
-	OnDisconnected   func()
-	OnSignal         func(wwr.Message)
+	OnSessionCreated        func(*wwr.Session)
+	OnSessionCreationFailed func(error)
+	OnSessionClosed         func(reason string)
+	OnSessionInfoChanged    func(wwr.SessionInfo)
+	OnDisconnected          func(error)
+	OnReconnectionFailed    func()
+	OnSignal                func(wwr.Message)
+	OnServerRequest         func(context.Context, wwr.Message) (wwr.Payload, error)
 }
 
 // callbackPoweredClient implements the wwrclt.Implementation interface
@@ -42,17 +48,38 @@ func (clt *callbackPoweredClient) OnSessionCreated(newSession *wwr.Session) {
 	}
 }
 
+// OnSessionCreationFailed implements the wwrclt.Implementation interface
+func (clt *callbackPoweredClient) OnSessionCreationFailed(err error) {
+	if clt.hooks.OnSessionCreationFailed != nil {
+		clt.hooks.OnSessionCreationFailed(err)
+	}
+}
+
 // OnSessionClosed implements the wwrclt.Implementation interface
-func (clt *callbackPoweredClient) OnSessionClosed() {
+func (clt *callbackPoweredClient) OnSessionClosed(reason string) {
 	if clt.hooks.OnSessionClosed != nil {
-		clt.hooks.OnSessionClosed()
+		clt.hooks.OnSessionClosed(reason)
+	}
+}
+
+// OnSessionInfoChanged implements the wwrclt.Implementation interface
+func (clt *callbackPoweredClient) OnSessionInfoChanged(info wwr.SessionInfo) {
+	if clt.hooks.OnSessionInfoChanged != nil {
+		clt.hooks.OnSessionInfoChanged(info)
 	}
 }
 
 // OnDisconnected implements the wwrclt.Implementation interface
-func (clt *callbackPoweredClient) OnDisconnected() {
+func (clt *callbackPoweredClient) OnDisconnected(err error) {
 	if clt.hooks.OnDisconnected != nil {
-		clt.hooks.OnDisconnected()
+		clt.hooks.OnDisconnected(err)
+	}
+}
+
+// OnReconnectionFailed implements the wwrclt.Implementation interface
+func (clt *callbackPoweredClient) OnReconnectionFailed() {
+	if clt.hooks.OnReconnectionFailed != nil {
+		clt.hooks.OnReconnectionFailed()
 	}
 }
 
@@ -62,3 +89,14 @@ func (clt *callbackPoweredClient) OnSignal(message wwr.Message) {
 		clt.hooks.OnSignal(message)
 	}
 }
+
+// OnServerRequest implements the wwrclt.Implementation interface
+func (clt *callbackPoweredClient) OnServerRequest(
+	ctx context.Context,
+	message wwr.Message,
+) (wwr.Payload, error) {
+	if clt.hooks.OnServerRequest != nil {
+		return clt.hooks.OnServerRequest(ctx, message)
+	}
+	return nil, nil
+}
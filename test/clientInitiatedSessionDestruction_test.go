@@ -86,7 +86,7 @@ func TestClientInitiatedSessionDestruction(t *testing.T) {
 				// Mark the client-side session creation callback as executed
 				sessionCreationCallbackCalled.Progress(1)
 			},
-			OnSessionClosed: func() {
+			OnSessionClosed: func(_ string) {
 				// Ensure this callback is called during the
 				assert.Equal(t,
 					3, currentStep,
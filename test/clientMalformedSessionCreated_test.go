@@ -0,0 +1,74 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientMalformedSessionCreated tests whether the client surfaces
+// an error when it fails decoding the session created by the server
+func TestClientMalformedSessionCreated(t *testing.T) {
+	creationFailed := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	var creationErr error
+
+	// Initialize webwire server encoding sessions
+	// in a format the client doesn't expect
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				assert.NoError(t, conn.CreateSession(nil))
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			SessionCodec: wwr.BinarySessionCodec{},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{
+			OnSessionCreated: func(_ *wwr.Session) {
+				t.Error("OnSessionCreated unexpectedly called")
+			},
+			OnSessionCreationFailed: func(err error) {
+				creationErr = err
+				creationFailed.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t,
+		creationFailed.Wait(),
+		"OnSessionCreationFailed not called",
+	)
+	require.Error(t, creationErr)
+	require.Nil(t, client.connection.Session())
+}
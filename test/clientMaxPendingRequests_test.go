@@ -0,0 +1,92 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientMaxPendingRequests tests rejecting requests
+// when the maximum number of pending requests is reached
+func TestClientMaxPendingRequests(t *testing.T) {
+	maxPending := 2
+	requestsArrived := tmdwg.NewTimedWaitGroup(maxPending, 1*time.Second)
+	repliesReceived := tmdwg.NewTimedWaitGroup(maxPending, 2*time.Second)
+	release := make(chan struct{})
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				requestsArrived.Progress(1)
+
+				// Block the request until released
+				<-release
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			MaxPendingRequests:    uint(maxPending),
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Fill the pending requests map
+	for i := 0; i < maxPending; i++ {
+		go func() {
+			_, err := client.connection.Request(
+				context.Background(),
+				"pending",
+				wwr.NewPayload(wwr.EncodingBinary, []byte("sample")),
+			)
+			assert.NoError(t, err)
+			repliesReceived.Progress(1)
+		}()
+	}
+	require.NoError(t, requestsArrived.Wait(), "Requests didn't arrive")
+	require.Equal(t, maxPending, client.connection.PendingRequests())
+
+	// Expect the next request to be rejected
+	_, err := client.connection.Request(
+		context.Background(),
+		"rejected",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("sample")),
+	)
+	require.Error(t, err)
+	require.IsType(t, wwr.TooManyPendingErr{}, err)
+	require.Equal(t, maxPending, client.connection.PendingRequests())
+
+	// Release the pending requests
+	close(release)
+	require.NoError(t, repliesReceived.Wait(), "Replies not received")
+
+	// Expect requests to be accepted again
+	_, err = client.connection.Request(
+		context.Background(),
+		"accepted",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("sample")),
+	)
+	require.NoError(t, err)
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientMaxReconnectionAttempts tests whether the client gives up
+// reconnecting and disables itself after reaching the maximum number
+// of reconnection attempts
+func TestClientMaxReconnectionAttempts(t *testing.T) {
+	reconnectionFailed := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	var hookCalls int32
+
+	// Initialize client for an unreachable server
+	client := newCallbackPoweredClient(
+		"127.0.0.1:65000",
+		wwrclt.Options{
+			Autoconnect:             wwr.Disabled,
+			ReconnectionInterval:    5 * time.Millisecond,
+			MaxReconnectionAttempts: 3,
+			DefaultRequestTimeout:   10 * time.Second,
+		},
+		callbackPoweredClientHooks{
+			OnReconnectionFailed: func() {
+				atomic.AddInt32(&hookCalls, 1)
+				reconnectionFailed.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+
+	// Expect the request to fail once the client gave up reconnecting
+	// rather than when the request timed out
+	client.connection.SetAutoconnect(true)
+	start := time.Now()
+	_, err := client.connection.Request(
+		context.Background(),
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+	)
+	require.Error(t, err)
+	require.IsType(t, wwr.DisconnectedErr{}, err)
+	require.True(t, time.Since(start) < 5*time.Second)
+
+	require.NoError(t,
+		reconnectionFailed.Wait(),
+		"OnReconnectionFailed not called",
+	)
+	require.Equal(t, int32(1), atomic.LoadInt32(&hookCalls))
+
+	// Expect the client to be disabled
+	require.Equal(t, wwrclt.Disabled, client.connection.Status())
+	require.False(t, client.connection.AutoconnectEnabled())
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// setupNeverConnectedClient sets up a server and a client with autoconnect
+// disabled which is never connected to the server
+func setupNeverConnectedClient(t *testing.T) *callbackPoweredClient {
+	// Initialize a reachable server
+	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{})
+
+	// Initialize client without connecting it
+	return newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect:           wwr.Disabled,
+			DefaultRequestTimeout: 50 * time.Millisecond,
+		},
+		callbackPoweredClientHooks{},
+	)
+}
+
+// TestClientReqNeverConnectedNoAutoconn tests disconnected error
+// when trying to send a request before ever connecting the client
+// while autoconn is disabled
+func TestClientReqNeverConnectedNoAutoconn(t *testing.T) {
+	client := setupNeverConnectedClient(t)
+
+	// Try to send a request and expect a DisconnectedErr error
+	_, err := client.connection.Request(
+		context.Background(),
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+	)
+	require.Error(t, err)
+	require.IsType(t, wwr.DisconnectedErr{}, err)
+	require.Equal(t, 0, client.connection.PendingRequests())
+	require.Equal(t, wwrclt.Disconnected, client.connection.Status())
+}
+
+// TestClientSigNeverConnectedNoAutoconn tests disconnected error
+// when trying to send a signal before ever connecting the client
+// while autoconn is disabled
+func TestClientSigNeverConnectedNoAutoconn(t *testing.T) {
+	client := setupNeverConnectedClient(t)
+
+	// Try to send a signal and expect a DisconnectedErr error
+	err := client.connection.Signal(
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+	)
+	require.Error(t, err)
+	require.IsType(t, wwr.DisconnectedErr{}, err)
+	require.Equal(t, wwrclt.Disconnected, client.connection.Status())
+}
@@ -59,7 +59,7 @@ func TestClientOnSessionClosed(t *testing.T) {
 			DefaultRequestTimeout: 2 * time.Second,
 		},
 		callbackPoweredClientHooks{
-			OnSessionClosed: func() {
+			OnSessionClosed: func(_ string) {
 				hookCalled.Progress(1)
 			},
 		},
@@ -0,0 +1,154 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// setupRefusingServer sets up a server replying to all requests
+// which refuses incoming connections while refuse is set to 1
+// and publishes the accepted connections to the given channel
+func setupRefusingServer(
+	t *testing.T,
+	refuse *int32,
+	connected chan<- wwr.Connection,
+) wwr.Server {
+	return setupServer(
+		t,
+		&serverImpl{
+			beforeUpgrade: func(
+				_ http.ResponseWriter,
+				_ *http.Request,
+			) wwr.ConnectionOptions {
+				if atomic.LoadInt32(refuse) == 1 {
+					return wwr.RefuseConnection("unavailable")
+				}
+				return wwr.AcceptConnection(wwr.UnlimitedConcurrency)
+			},
+			onClientConnected: func(conn wwr.Connection) error {
+				select {
+				case connected <- conn:
+				default:
+				}
+				return nil
+			},
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+}
+
+// TestClientQueueWhileReconnecting tests whether requests issued
+// while the client is reconnecting are queued and sent once the connection
+// is reestablished while requests exceeding the queue size fail immediately
+func TestClientQueueWhileReconnecting(t *testing.T) {
+	refuse := int32(0)
+	connected := make(chan wwr.Connection, 1)
+	disconnected := make(chan struct{}, 1)
+	server := setupRefusingServer(t, &refuse, connected)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout:  5 * time.Second,
+			ReconnectionInterval:   20 * time.Millisecond,
+			QueueWhileReconnecting: wwr.Enabled,
+			ReconnectionQueueSize:  2,
+		},
+		callbackPoweredClientHooks{
+			OnDisconnected: func(_ error) {
+				disconnected <- struct{}{}
+			},
+		},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Drop the connection and keep the client from reconnecting
+	atomic.StoreInt32(&refuse, 1)
+	(<-connected).Close()
+	<-disconnected
+
+	// Queue two requests
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = client.connection.Request(
+				context.Background(),
+				"q",
+				nil,
+			)
+		}(i)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	// Expect the request exceeding the queue size to fail immediately
+	_, err := client.connection.Request(context.Background(), "q", nil)
+	require.IsType(t, wwr.DisconnectedErr{}, err)
+
+	// Let the client reconnect and expect the queued requests to succeed
+	atomic.StoreInt32(&refuse, 0)
+	wg.Wait()
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+}
+
+// TestClientQueueWhileReconnectingTimeout tests whether queued requests
+// respect their context when the client fails to reconnect in time
+func TestClientQueueWhileReconnectingTimeout(t *testing.T) {
+	refuse := int32(0)
+	connected := make(chan wwr.Connection, 1)
+	disconnected := make(chan struct{}, 1)
+	server := setupRefusingServer(t, &refuse, connected)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout:  5 * time.Second,
+			ReconnectionInterval:   20 * time.Millisecond,
+			QueueWhileReconnecting: wwr.Enabled,
+		},
+		callbackPoweredClientHooks{
+			OnDisconnected: func(_ error) {
+				disconnected <- struct{}{}
+			},
+		},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Drop the connection and keep the client from reconnecting
+	atomic.StoreInt32(&refuse, 1)
+	(<-connected).Close()
+	<-disconnected
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		100*time.Millisecond,
+	)
+	defer cancel()
+	start := time.Now()
+	_, err := client.connection.Request(ctx, "q", nil)
+	require.IsType(t, wwr.DeadlineExceededErr{}, err)
+	require.True(t, time.Since(start) < time.Second)
+}
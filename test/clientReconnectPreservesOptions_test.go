@@ -0,0 +1,92 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientReconnectPreservesOptions tests whether the client options
+// and hooks are still in effect after an automatic reconnection
+func TestClientReconnectPreservesOptions(t *testing.T) {
+	defaultRequestTimeout := 200 * time.Millisecond
+	disconnected := tmdwg.NewTimedWaitGroup(1, 2*time.Second)
+	signalReceived := tmdwg.NewTimedWaitGroup(1, 2*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				switch msg.Name() {
+				case "drop":
+					// Close the connection after the reply is sent
+					conn.Close()
+				case "signal":
+					assert.NoError(t, conn.Signal(
+						"",
+						wwr.NewPayload(wwr.EncodingBinary, []byte("sample")),
+					))
+				case "slow":
+					time.Sleep(defaultRequestTimeout * 5)
+				}
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: defaultRequestTimeout,
+			ReconnectionInterval:  10 * time.Millisecond,
+		},
+		callbackPoweredClientHooks{
+			OnDisconnected: func(_ error) {
+				disconnected.Progress(1)
+			},
+			OnSignal: func(_ wwr.Message) {
+				signalReceived.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Drop the connection and await the automatic reconnection
+	_, err := client.connection.Request(context.Background(), "drop", nil)
+	require.NoError(t, err)
+	require.NoError(t, disconnected.Wait(), "Client not disconnected")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for client.connection.Status() != wwrclt.Connected {
+		require.True(t, time.Now().Before(deadline), "Client not reconnected")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Expect the hooks to still be in effect
+	_, err = client.connection.Request(context.Background(), "signal", nil)
+	require.NoError(t, err)
+	require.NoError(t, signalReceived.Wait(), "OnSignal hook not invoked")
+
+	// Expect the default request timeout to still be honored
+	start := time.Now()
+	_, err = client.connection.Request(context.Background(), "slow", nil)
+	require.Error(t, err)
+	require.True(t, wwr.IsTimeoutErr(err), "Expected a timeout error")
+	require.True(t, time.Since(start) < defaultRequestTimeout*4)
+}
@@ -0,0 +1,68 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// awaitReconnectionFailure makes the given client autoconnect
+// to an unreachable server and returns the time it took
+// to give up reconnecting
+func awaitReconnectionFailure(
+	t *testing.T,
+	opts wwrclt.Options,
+) time.Duration {
+	opts.Autoconnect = wwr.Disabled
+	opts.DefaultRequestTimeout = 10 * time.Second
+
+	client := newCallbackPoweredClient(
+		"127.0.0.1:65000",
+		opts,
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	client.connection.SetAutoconnect(true)
+	start := time.Now()
+	_, err := client.connection.Request(
+		context.Background(),
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+	)
+	require.IsType(t, wwr.DisconnectedErr{}, err)
+	return time.Since(start)
+}
+
+// TestClientReconnectionBackoff tests whether the reconnection interval
+// grows after each failed reconnection attempt
+func TestClientReconnectionBackoff(t *testing.T) {
+	// Expect the client to wait 10 + 20 + 40 milliseconds
+	// between the 4 reconnection attempts
+	elapsed := awaitReconnectionFailure(t, wwrclt.Options{
+		ReconnectionInterval:    10 * time.Millisecond,
+		ReconnectionMultiplier:  2,
+		MaxReconnectionAttempts: 4,
+	})
+	require.True(t, elapsed >= 70*time.Millisecond)
+}
+
+// TestClientReconnectionBackoffMaxInterval tests whether the reconnection
+// interval doesn't grow beyond the maximum interval
+func TestClientReconnectionBackoffMaxInterval(t *testing.T) {
+	// Expect the client to wait 10 + 20 + 20 milliseconds
+	// between the 4 reconnection attempts instead of 10 + 100 + 1000
+	elapsed := awaitReconnectionFailure(t, wwrclt.Options{
+		ReconnectionInterval:    10 * time.Millisecond,
+		ReconnectionMultiplier:  10,
+		ReconnectionMaxInterval: 20 * time.Millisecond,
+		MaxReconnectionAttempts: 4,
+	})
+	require.True(t, elapsed >= 50*time.Millisecond)
+	require.True(t, elapsed < 1*time.Second)
+}
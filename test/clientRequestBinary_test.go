@@ -0,0 +1,65 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientRequestBinary tests requests with binary payloads
+// and verifies the encoding of the binary reply is explicitly set
+func TestClientRequestBinary(t *testing.T) {
+	expectedRequestPayload := wwr.NewPayload(
+		wwr.EncodingBinary,
+		[]byte{0, 1, 2, 3, 255},
+	)
+	expectedReplyPayload := wwr.NewPayload(
+		wwr.EncodingBinary,
+		[]byte{255, 3, 2, 1, 0},
+	)
+
+	// Initialize webwire server given only the request
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				// Verify request payload
+				comparePayload(t, expectedRequestPayload, msg.Payload())
+				return expectedReplyPayload, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+
+	require.NoError(t, client.connection.Connect())
+
+	// Send request and await reply
+	reply, err := client.connection.Request(
+		context.Background(),
+		"",
+		expectedRequestPayload,
+	)
+	require.NoError(t, err)
+
+	// Verify reply
+	require.Equal(t, wwr.EncodingBinary, reply.Encoding())
+	comparePayload(t, expectedReplyPayload, reply)
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientRequestErrorPayload tests server-side request errors
+// returned alongside a payload properly delivering the payload
+// to the client together with the error
+func TestClientRequestErrorPayload(t *testing.T) {
+	expectedReplyError := wwr.ReqErr{
+		Code:    "VALIDATION_ERROR",
+		Message: "Invalid input",
+	}
+	expectedDetails := []byte(`{"field":"name","reason":"required"}`)
+
+	// Initialize webwire server given only the request
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if msg.Name() == "attached" {
+					// Attach the details to the error itself
+					replyErr := expectedReplyError
+					replyErr.Payload = wwr.NewPayload(
+						wwr.EncodingUtf8,
+						expectedDetails,
+					)
+					return nil, &replyErr
+				}
+				// Fail the request by returning both an error and a payload
+				return wwr.NewPayload(
+					wwr.EncodingUtf8,
+					expectedDetails,
+				), expectedReplyError
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+
+	require.NoError(t, client.connection.Connect())
+
+	for _, name := range []string{"returned", "attached"} {
+		// Send request and await reply
+		reply, err := client.connection.Request(
+			context.Background(),
+			name,
+			wwr.NewPayload(wwr.EncodingUtf8, []byte("sample")),
+		)
+
+		// Verify returned error and its payload
+		require.Error(t, err)
+		require.Nil(t, reply)
+		require.IsType(t, wwr.ReqErr{}, err)
+		replyErr := err.(wwr.ReqErr)
+		require.Equal(t, expectedReplyError.Code, replyErr.Code)
+		require.Equal(t, expectedReplyError.Message, replyErr.Message)
+		require.NotNil(t, replyErr.Payload)
+		require.Equal(t, wwr.EncodingUtf8, replyErr.Payload.Encoding())
+		require.Equal(t, expectedDetails, replyErr.Payload.Data())
+	}
+}
@@ -61,5 +61,6 @@ func TestClientRequestError(t *testing.T) {
 	require.IsType(t, wwr.ReqErr{}, err)
 	require.Equal(t, err.(wwr.ReqErr).Code, expectedReplyError.Code)
 	require.Equal(t, err.(wwr.ReqErr).Message, expectedReplyError.Message)
+	require.Nil(t, err.(wwr.ReqErr).Payload)
 	require.Nil(t, reply)
 }
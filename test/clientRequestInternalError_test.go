@@ -54,5 +54,6 @@ func TestClientRequestInternalError(t *testing.T) {
 	// Verify returned error
 	require.Error(t, reqErr)
 	require.IsType(t, wwr.ReqInternalErr{}, reqErr)
+	require.Equal(t, "", reqErr.(wwr.ReqInternalErr).Message)
 	require.Nil(t, reply)
 }
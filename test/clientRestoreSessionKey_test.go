@@ -0,0 +1,58 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientRestoreSessionKeyInexistent tests whether connecting
+// with an inexistent session key to be restored establishes
+// an anonymous connection returning a SessionRestoreErr error
+func TestClientRestoreSessionKeyInexistent(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Expect the connection to be anonymous
+				require.False(t, conn.HasSession())
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+			RestoreSessionKey:     "inexistent",
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	// Expect the restoration to fail with a wrapped SessNotFoundErr error
+	err := client.connection.Connect()
+	require.Error(t, err)
+	require.IsType(t, wwr.SessionRestoreErr{}, err)
+	require.True(t, errors.As(err, &wwr.SessNotFoundErr{}))
+
+	// Expect the connection to be established anonymously
+	require.Equal(t, wwrclt.Connected, client.connection.Status())
+	require.Nil(t, client.connection.Session())
+	_, err = client.connection.Request(context.Background(), "q", nil)
+	require.NoError(t, err)
+}
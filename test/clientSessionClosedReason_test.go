@@ -0,0 +1,72 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientSessionClosedReason tests server-initiated session closure
+// with a reason expecting the reason to be passed
+// to the OnSessionClosed hook of the client
+func TestClientSessionClosedReason(t *testing.T) {
+	hookCalled := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Try to create a new session
+				err := conn.CreateSession(nil)
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{
+			OnSessionClosed: func(reason string) {
+				assert.Equal(t, "expired", reason)
+				hookCalled.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Create a session
+	_, err := client.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	require.NoError(t, err)
+	sessionKey := client.connection.Session().Key
+
+	// Close the session from the server side providing a reason
+	_, _, err = server.CloseSessionWithReason(sessionKey, "expired")
+	require.NoError(t, err)
+
+	require.NoError(t, hookCalled.Wait(), "Hook not called")
+	require.Nil(t, client.connection.Session())
+}
@@ -0,0 +1,88 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientSessionTimes tests whether the creation and last lookup times
+// of the session are exposed to the client
+// after both the creation and the restoration of a session
+func TestClientSessionTimes(t *testing.T) {
+	creation := time.Now().Add(-2 * time.Hour).UTC()
+	lastLookup := time.Now().Add(-1 * time.Hour).UTC()
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Try to create a new session
+				err := conn.CreateSession(nil)
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager: &callbackPoweredSessionManager{
+				SessionLookup: func(key string) (
+					wwr.SessionLookupResult,
+					error,
+				) {
+					return wwr.NewSessionLookupResult(
+						creation,   // Creation
+						lastLookup, // LastLookup
+						nil,        // Info
+					), nil
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Create a new session
+	_, err := client.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	require.NoError(t, err)
+
+	// Verify the times of the created session
+	createdSession := client.connection.Session()
+	require.NotNil(t, createdSession)
+	require.False(t, createdSession.Creation.IsZero())
+	require.False(t, createdSession.LastLookup.IsZero())
+
+	// Close the session and restore another one
+	require.NoError(t, client.connection.CloseSession())
+	require.NoError(t, client.connection.RestoreSession([]byte("somekey")))
+
+	// Verify the times of the restored session
+	restoredSession := client.connection.Session()
+	require.NotNil(t, restoredSession)
+	require.True(t, creation.Equal(restoredSession.Creation))
+	require.True(t, lastLookup.Equal(restoredSession.LastLookup))
+}
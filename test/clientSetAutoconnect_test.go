@@ -0,0 +1,96 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientSetAutoconnect tests toggling autoconnect at runtime
+// expecting requests on disconnected clients to either fail or connect
+// automatically accordingly
+func TestClientSetAutoconnect(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{})
+
+	// Initialize client with autoconnect disabled
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect:           wwr.Disabled,
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.False(t, client.connection.AutoconnectEnabled())
+
+	request := func() error {
+		_, err := client.connection.Request(
+			context.Background(),
+			"",
+			wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+		)
+		return err
+	}
+
+	// Expect the request to fail while autoconnect is disabled
+	err := request()
+	require.Error(t, err)
+	require.IsType(t, wwr.DisconnectedErr{}, err)
+
+	// Expect the request to automatically connect after enabling autoconnect
+	client.connection.SetAutoconnect(true)
+	require.True(t, client.connection.AutoconnectEnabled())
+	require.NoError(t, request())
+	require.Equal(t, wwrclt.Connected, client.connection.Status())
+
+	// Expect autoconnect to remain disabled after a manual reconnection
+	client.connection.SetAutoconnect(false)
+	client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+	require.False(t, client.connection.AutoconnectEnabled())
+}
+
+// TestClientSetAutoconnectStopsReconnection tests whether disabling
+// autoconnect stops ongoing reconnection attempts
+func TestClientSetAutoconnectStopsReconnection(t *testing.T) {
+	// Initialize client for an unreachable server
+	client := newCallbackPoweredClient(
+		"127.0.0.1:65000",
+		wwrclt.Options{
+			ReconnectionInterval:  5 * time.Millisecond,
+			DefaultRequestTimeout: 10 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.True(t, client.connection.AutoconnectEnabled())
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := client.connection.Request(
+			context.Background(),
+			"",
+			wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+		)
+		result <- err
+	}()
+
+	// Disable autoconnect while the request awaits the connection
+	time.Sleep(50 * time.Millisecond)
+	client.connection.SetAutoconnect(false)
+
+	select {
+	case err := <-result:
+		require.Error(t, err)
+		require.IsType(t, wwr.DisconnectedErr{}, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the reconnection attempts to stop")
+	}
+}
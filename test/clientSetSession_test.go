@@ -0,0 +1,110 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientSetSession tests injecting and clearing sessions locally
+// and their verification by the server during the next connection
+func TestClientSetSession(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				err := conn.CreateSession(nil)
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	newClient := func() *callbackPoweredClient {
+		return newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+				Autoconnect:           wwr.Disabled,
+			},
+			callbackPoweredClientHooks{},
+		)
+	}
+
+	// Create a session on the server
+	initialClient := newClient()
+	require.NoError(t, initialClient.connection.Connect())
+	_, err := initialClient.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("auth")),
+	)
+	require.NoError(t, err)
+	createdSession := initialClient.connection.Session()
+	initialClient.connection.Close()
+
+	// Inject the session into a disconnected client
+	client := newClient()
+	defer client.connection.Close()
+	require.NoError(t, client.connection.SetSession(createdSession))
+	compareSessions(t, createdSession, client.connection.Session())
+
+	// Expect the injected session to be cleared
+	require.NoError(t, client.connection.ClearSession())
+	require.Nil(t, client.connection.Session())
+
+	// Expect invalid sessions to be rejected
+	require.Error(t, client.connection.SetSession(nil))
+	require.Error(t, client.connection.SetSession(&wwr.Session{}))
+
+	// Expect the injected session to be restored on connection
+	require.NoError(t, client.connection.SetSession(createdSession))
+	require.NoError(t, client.connection.Connect())
+	compareSessions(t, createdSession, client.connection.Session())
+
+	// Expect local session modifications to fail while connected
+	require.Error(t, client.connection.SetSession(createdSession))
+	require.Error(t, client.connection.ClearSession())
+	compareSessions(t, createdSession, client.connection.Session())
+}
+
+// TestClientSetSessionInexistent tests whether a locally injected
+// session unknown to the server is reset when the client connects
+// while the connection is still established
+func TestClientSetSessionInexistent(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{})
+
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.SetSession(&wwr.Session{
+		Key:      "inexistent",
+		Creation: time.Now(),
+	}))
+
+	// Expect the connection to be established despite the failed restoration
+	err := client.connection.Connect()
+	require.IsType(t, wwr.SessionRestoreErr{}, err)
+	require.Equal(t, wwrclt.Connected, client.connection.Status())
+	require.Nil(t, client.connection.Session())
+}
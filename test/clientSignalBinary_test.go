@@ -0,0 +1,62 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+	"github.com/qbeon/webwire-go/message"
+)
+
+// TestClientSignalBinary tests client-side signals with binary payloads
+func TestClientSignalBinary(t *testing.T) {
+	expectedSignalPayload := wwr.NewPayload(
+		wwr.EncodingBinary,
+		[]byte{0, 1, 2, 3, 255},
+	)
+	signalArrived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server given only the signal handler
+	server := setupServer(
+		t,
+		&serverImpl{
+			onSignal: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) {
+				// Verify signal message type and payload
+				assert.Equal(t, message.MsgSignalBinary, msg.MessageType())
+				assert.Equal(t, wwr.EncodingBinary, msg.Payload().Encoding())
+				comparePayload(t, expectedSignalPayload, msg.Payload())
+
+				// Synchronize, notify signal arrival
+				signalArrived.Progress(1)
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+
+	require.NoError(t, client.connection.Connect())
+
+	// Send signal
+	require.NoError(t, client.connection.Signal("", expectedSignalPayload))
+
+	// Synchronize, await signal arrival
+	require.NoError(t, signalArrived.Wait(), "Signal wasn't processed")
+}
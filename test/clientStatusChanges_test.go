@@ -0,0 +1,143 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// awaitStatusChange returns the next status received from the given
+// status changes channel or fails the test if none is received in time
+func awaitStatusChange(
+	t *testing.T,
+	changes <-chan wwrclt.Status,
+) wwrclt.Status {
+	select {
+	case status := <-changes:
+		return status
+	case <-time.After(2 * time.Second):
+		t.Fatal("No status change received")
+	}
+	return wwrclt.Disabled
+}
+
+// TestClientStatusChanges tests whether the client publishes
+// status changes including connection losses and automatic reconnections
+func TestClientStatusChanges(t *testing.T) {
+	connected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	var serverSideConn wwr.Connection
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) error {
+				if serverSideConn == nil {
+					serverSideConn = conn
+					connected.Progress(1)
+				}
+				return nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect:          wwr.Disabled,
+			ReconnectionInterval: 5 * time.Millisecond,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	changes := client.connection.StatusChanges()
+
+	require.NoError(t, client.connection.Connect())
+	require.Equal(t, wwrclt.Connected, awaitStatusChange(t, changes))
+	require.NoError(t, connected.Wait(), "Client not connected")
+
+	// Expect the client to report the connection loss
+	// and the automatic reconnection
+	client.connection.SetAutoconnect(true)
+	serverSideConn.Close()
+	require.Equal(t, wwrclt.Disconnected, awaitStatusChange(t, changes))
+	require.Equal(t, wwrclt.Reconnecting, awaitStatusChange(t, changes))
+	require.Equal(t, wwrclt.Connected, awaitStatusChange(t, changes))
+
+	// Expect the client to report being disabled when closed
+	client.connection.Close()
+	require.Equal(t, wwrclt.Disabled, awaitStatusChange(t, changes))
+}
+
+// TestClientStatusChangesReconnectionAttempts tests whether the client
+// reports each failed automatic reconnection attempt
+func TestClientStatusChangesReconnectionAttempts(t *testing.T) {
+	// Initialize client for an unreachable server
+	client := newCallbackPoweredClient(
+		"127.0.0.1:65000",
+		wwrclt.Options{
+			Autoconnect:             wwr.Disabled,
+			ReconnectionInterval:    5 * time.Millisecond,
+			MaxReconnectionAttempts: 3,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	changes := client.connection.StatusChanges()
+
+	// Expect each reconnection attempt to be reported
+	// until the client gives up reconnecting
+	client.connection.SetAutoconnect(true)
+	_, err := client.connection.Request(
+		context.Background(),
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+	)
+	require.Error(t, err)
+	for i := 0; i < 3; i++ {
+		require.Equal(t, wwrclt.Reconnecting, awaitStatusChange(t, changes))
+		require.Equal(t, wwrclt.Disconnected, awaitStatusChange(t, changes))
+	}
+	require.Equal(t, wwrclt.Disabled, client.connection.Status())
+}
+
+// TestClientStatusChangesSlowConsumer tests whether status changes
+// never block the client if they're not consumed
+// and the latest status is retained
+func TestClientStatusChangesSlowConsumer(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{})
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect: wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	// Change the status more often than the changes are buffered
+	for i := 0; i < 20; i++ {
+		require.NoError(t, client.connection.Connect())
+		client.connection.Close()
+	}
+	require.NoError(t, client.connection.Connect())
+
+	// Expect the latest status to be the last one received
+	changes := client.connection.StatusChanges()
+	var latest wwrclt.Status
+	for len(changes) > 0 {
+		latest = <-changes
+	}
+	require.Equal(t, wwrclt.Connected, latest)
+}
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// setupTLSServer sets up a webwire server serving TLS using a self-signed
+// certificate and returns it along with a pool trusting the certificate
+func setupTLSServer(t *testing.T) (wwr.Server, *x509.CertPool) {
+	certPEM, keyPEM := generateSelfSignedCert(t)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(certPEM))
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				return msg.Payload(), nil
+			},
+		},
+		wwr.ServerOptions{
+			TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		},
+	)
+
+	return server, roots
+}
+
+// TestClientTLS tests whether the client connects to a TLS server
+// trusting a self-signed certificate
+func TestClientTLS(t *testing.T) {
+	server, roots := setupTLSServer(t)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect:      wwr.Disabled,
+			TLSConfig:        &tls.Config{RootCAs: roots},
+			HandshakeTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	reply, err := client.connection.Request(
+		context.Background(),
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("secure")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []byte("secure"), reply.Data())
+}
+
+// TestClientTLSUntrusted tests whether the client refuses to connect
+// to a TLS server using an untrusted certificate
+func TestClientTLSUntrusted(t *testing.T) {
+	server, _ := setupTLSServer(t)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect: wwr.Disabled,
+			TLSConfig:   &tls.Config{},
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.Error(t, client.connection.Connect())
+	require.Equal(t, wwrclt.Disconnected, client.connection.Status())
+}
+
+// TestClientProxy tests whether the client consults
+// the configured proxy function when connecting
+func TestClientProxy(t *testing.T) {
+	var proxyCalls int32
+
+	// Initialize webwire server
+	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{})
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect: wwr.Disabled,
+			Proxy: func(*http.Request) (*url.URL, error) {
+				atomic.AddInt32(&proxyCalls, 1)
+				// Connect directly
+				return nil, nil
+			},
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+	require.True(t, atomic.LoadInt32(&proxyCalls) > 0)
+}
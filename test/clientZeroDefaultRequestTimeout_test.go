@@ -0,0 +1,81 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+	reqman "github.com/qbeon/webwire-go/requestManager"
+)
+
+// TestClientZeroDefaultRequestTimeout tests requests issued by a client
+// with an undefined (zero) default request timeout
+func TestClientZeroDefaultRequestTimeout(t *testing.T) {
+	// Expect the default request timeout to be applied
+	opts := wwrclt.Options{}
+	opts.SetDefaults()
+	require.Equal(t, 60*time.Second, opts.DefaultRequestTimeout)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Delay the reply to ensure the request doesn't time out
+				// immediately
+				time.Sleep(100 * time.Millisecond)
+				return wwr.NewPayload(wwr.EncodingUtf8, []byte("reply")), nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client leaving the default request timeout undefined
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Send request and await reply
+	reply, err := client.connection.Request(
+		context.Background(),
+		"",
+		wwr.NewPayload(wwr.EncodingUtf8, []byte("request")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []byte("reply"), reply.Data())
+}
+
+// TestRequestManagerZeroTimeout tests awaiting the reply of a request
+// created with a zero timeout which must wait indefinitely
+func TestRequestManagerZeroTimeout(t *testing.T) {
+	manager := reqman.NewRequestManager(0)
+	request, err := manager.Create(0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		100*time.Millisecond,
+	)
+	defer cancel()
+
+	// Expect the user-defined deadline to be exceeded
+	// instead of the request timing out immediately
+	reply, err := request.AwaitReply(ctx)
+	require.Error(t, err)
+	require.IsType(t, wwr.DeadlineExceededErr{}, err)
+	require.Nil(t, reply)
+	require.Equal(t, 0, manager.PendingRequests())
+}
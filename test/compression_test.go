@@ -0,0 +1,163 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// countingProxy forwards TCP connections to the given address
+// counting the bytes sent from the target back to the clients
+type countingProxy struct {
+	listener net.Listener
+	received int64
+	// accepting is closed when the accept loop returns
+	accepting chan struct{}
+	// active keeps track of the forwarded connections
+	active sync.WaitGroup
+}
+
+// newCountingProxy starts a new counting proxy forwarding to the given address
+func newCountingProxy(t *testing.T, target string) *countingProxy {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	proxy := &countingProxy{
+		listener:  listener,
+		accepting: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(proxy.accepting)
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			proxy.active.Add(1)
+			upstream, err := net.Dial("tcp", target)
+			if err != nil {
+				conn.Close()
+				proxy.active.Done()
+				return
+			}
+			go func() {
+				io.Copy(upstream, conn)
+				upstream.Close()
+			}()
+			go func() {
+				n, _ := io.Copy(conn, upstream)
+				atomic.AddInt64(&proxy.received, n)
+				conn.Close()
+				proxy.active.Done()
+			}()
+		}
+	}()
+
+	return proxy
+}
+
+// close stops accepting new connections and blocks until
+// all forwarded connections are closed
+func (proxy *countingProxy) close() {
+	proxy.listener.Close()
+	// Join the accept loop before waiting to ensure no further
+	// connections are added to the wait group concurrently
+	<-proxy.accepting
+	proxy.active.Wait()
+}
+
+// measureEchoTraffic returns the number of bytes the client receives
+// when the server echoes the given payload
+// using the given compression setting on both sides
+func measureEchoTraffic(
+	t *testing.T,
+	compression wwr.OptionValue,
+	data []byte,
+) int64 {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				return msg.Payload(), nil
+			},
+		},
+		wwr.ServerOptions{
+			Compression: compression,
+		},
+	)
+
+	proxy := newCountingProxy(t, server.Addr().String())
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		proxy.listener.Addr().String(),
+		wwrclt.Options{
+			Autoconnect:           wwr.Disabled,
+			DefaultRequestTimeout: 2 * time.Second,
+			Compression:           compression,
+		},
+		callbackPoweredClientHooks{},
+	)
+	require.NoError(t, client.connection.Connect())
+
+	reply, err := client.connection.Request(
+		context.Background(),
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, data),
+	)
+	require.NoError(t, err)
+	require.Equal(t, data, reply.Data())
+
+	// Close the connection to ensure all received bytes are counted
+	client.connection.Close()
+	proxy.close()
+
+	return atomic.LoadInt64(&proxy.received)
+}
+
+// TestCompression tests whether a highly compressible payload
+// results in less traffic when compression is enabled
+func TestCompression(t *testing.T) {
+	data := bytes.Repeat([]byte("compressible "), 4096)
+
+	uncompressed := measureEchoTraffic(t, wwr.Disabled, data)
+	compressed := measureEchoTraffic(t, wwr.Enabled, data)
+
+	require.True(t, uncompressed > int64(len(data)))
+	require.True(
+		t,
+		compressed < uncompressed/4,
+		"compressed: %d, uncompressed: %d",
+		compressed,
+		uncompressed,
+	)
+}
+
+// TestCompressionInvalidLevel tests whether the server refuses
+// an invalid compression level
+func TestCompressionInvalidLevel(t *testing.T) {
+	_, err := wwr.NewServer(
+		&serverImpl{},
+		wwr.ServerOptions{
+			Address:          "127.0.0.1:0",
+			Compression:      wwr.Enabled,
+			CompressionLevel: 42,
+		},
+	)
+	require.Error(t, err)
+}
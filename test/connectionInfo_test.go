@@ -18,7 +18,7 @@ func TestConnectionInfo(t *testing.T) {
 	server := setupServer(
 		t,
 		&serverImpl{
-			onClientConnected: func(conn wwr.Connection) {
+			onClientConnected: func(conn wwr.Connection) error {
 				info := conn.Info()
 				assert.WithinDuration(
 					t,
@@ -28,6 +28,7 @@ func TestConnectionInfo(t *testing.T) {
 				)
 				assert.Equal(t, "Go-http-client/1.1", info.UserAgent)
 				assert.NotNil(t, info.RemoteAddr)
+				return nil
 			},
 		},
 		wwr.ServerOptions{},
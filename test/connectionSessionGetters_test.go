@@ -18,7 +18,7 @@ func TestConnectionSessionGetters(t *testing.T) {
 	server := setupServer(
 		t,
 		&serverImpl{
-			onClientConnected: func(conn wwr.Connection) {
+			onClientConnected: func(conn wwr.Connection) error {
 				// Before session creation
 				assert.Nil(t, conn.Session())
 				assert.Equal(t, time.Time{}, conn.SessionCreation())
@@ -48,6 +48,7 @@ func TestConnectionSessionGetters(t *testing.T) {
 				someNumber := conn.SessionInfo("some-number")
 				assert.NotNil(t, someNumber)
 				assert.IsType(t, int(0), someNumber)
+				return nil
 			},
 		},
 		wwr.ServerOptions{},
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestConnectionState tests whether the state stored on a connection
+// persists across concurrently handled requests of the same connection
+func TestConnectionState(t *testing.T) {
+	concurrentRequests := 16
+	finished := tmdwg.NewTimedWaitGroup(concurrentRequests, 2*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) error {
+				// Store the state once at connection time
+				conn.SetState("user", "alice")
+				return nil
+			},
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				// Expect the state stored at connection time
+				assert.Equal(t, "alice", conn.State("user"))
+
+				// Concurrently store and remove request specific state
+				key := string(msg.Payload().Data())
+				conn.SetState(key, key)
+				assert.Equal(t, key, conn.State(key))
+				conn.SetState(key, nil)
+				assert.Nil(t, conn.State(key))
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	for i := 0; i < concurrentRequests; i++ {
+		go func(i int) {
+			defer finished.Progress(1)
+			_, err := client.connection.Request(
+				context.Background(),
+				"",
+				wwr.NewPayload(
+					wwr.EncodingBinary,
+					[]byte(fmt.Sprintf("request_%d", i)),
+				),
+			)
+			assert.NoError(t, err)
+		}(i)
+	}
+
+	require.NoError(t, finished.Wait(), "Expectation timed out")
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestCreateSessionDisabled tests whether the SessionsDisabledErr error
+// returned by CreateSession when sessions are disabled is logged
+// and propagated to the client when returned by the request handler
+func TestCreateSessionDisabled(t *testing.T) {
+	warnLog := &syncLogWriter{}
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, conn.CreateSession(nil)
+			},
+		},
+		wwr.ServerOptions{
+			Sessions: wwr.Disabled,
+			WarnLog:  log.New(warnLog, "WARN: ", 0),
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	require.Error(t, err)
+	require.IsType(t, wwr.SessionsDisabledErr{}, err)
+	require.Contains(t, warnLog.String(), "sessions are disabled")
+}
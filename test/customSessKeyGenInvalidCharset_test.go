@@ -0,0 +1,104 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestCustomSessKeyGenInvalidCharset tests custom session key generators
+// returning keys containing characters outside the permitted character set
+func TestCustomSessKeyGenInvalidCharset(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Try to create a new session
+				err := conn.CreateSession(nil)
+				assert.IsType(t, wwr.InvalidSessionKeyErr{}, err)
+
+				// Ensure the session lock was released
+				assert.NoError(t, conn.CloseSession())
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			SessionKeyGenerator: &sessionKeyGen{
+				generate: func() string {
+					// Return a key that's neither valid UTF8
+					// nor a safe file name
+					return "../invalid\xff"
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	// Send authentication request and await reply
+	_, err := client.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+	)
+	require.NoError(t, err)
+
+	// Expect no session to be created
+	require.Nil(t, client.connection.Session())
+}
+
+// TestRestoreSessionInvalidKey tests whether restoring a session
+// by a key containing invalid characters fails without the key
+// being passed to the session manager
+func TestRestoreSessionInvalidKey(t *testing.T) {
+	// Initialize server
+	server := setupServer(
+		t,
+		&serverImpl{},
+		wwr.ServerOptions{
+			SessionManager: &callbackPoweredSessionManager{
+				SessionLookup: func(key string) (
+					wwr.SessionLookupResult,
+					error,
+				) {
+					t.Errorf("Unexpected lookup of invalid session key %q", key)
+					return nil, nil
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	err := client.connection.RestoreSession([]byte("../../etc/passwd"))
+	require.Error(t, err)
+	require.IsType(t, wwr.SessNotFoundErr{}, err)
+}
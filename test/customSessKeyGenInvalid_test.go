@@ -25,18 +25,13 @@ func TestCustomSessKeyGenInvalid(t *testing.T) {
 				conn wwr.Connection,
 				_ wwr.Message,
 			) (wwr.Payload, error) {
-				defer func() {
-					if err := recover(); err == nil {
-						t.Errorf("Expected server to panic " +
-							"on invalid session key",
-						)
-					}
-				}()
-
 				// Try to create a new session
 				err := conn.CreateSession(nil)
-				assert.NoError(t, err)
-				return nil, err
+				assert.IsType(t, wwr.InvalidSessionKeyErr{}, err)
+
+				// Ensure the session lock was released
+				assert.NoError(t, conn.CloseSession())
+				return nil, nil
 			},
 		},
 		wwr.ServerOptions{
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// emptyPayloads returns the various representations of an empty payload
+func emptyPayloads() map[string]wwr.Payload {
+	return map[string]wwr.Payload{
+		"nil":          nil,
+		"zero":         &wwr.EncodedPayload{},
+		"binary nil":   wwr.NewPayload(wwr.EncodingBinary, nil),
+		"utf8 empty":   wwr.NewPayload(wwr.EncodingUtf8, []byte{}),
+		"utf16 nil":    wwr.NewPayload(wwr.EncodingUtf16, nil),
+		"utf16 empty":  wwr.NewPayload(wwr.EncodingUtf16, []byte{}),
+		"binary empty": wwr.NewPayload(wwr.EncodingBinary, []byte{}),
+	}
+}
+
+// requireEmpty expects the given received payload to be empty
+func requireEmpty(t *testing.T, payload wwr.Payload) {
+	require.NotNil(t, payload)
+	require.True(t, payload.IsEmpty())
+	require.Equal(t, 0, payload.Len())
+}
+
+// TestEmptyPayload tests whether empty payloads are transmitted
+// as empty payloads in each direction regardless of their representation
+func TestEmptyPayload(t *testing.T) {
+	for name, payload := range emptyPayloads() {
+		payload := payload
+		t.Run(name, func(t *testing.T) {
+			serverSignals := make(chan wwr.Payload, 1)
+			clientSignals := make(chan wwr.Payload, 1)
+			serverRequests := make(chan wwr.Payload, 1)
+			clientRequests := make(chan wwr.Payload, 1)
+			connected := make(chan wwr.Connection, 1)
+
+			// Initialize webwire server
+			server := setupServer(
+				t,
+				&serverImpl{
+					onClientConnected: func(conn wwr.Connection) error {
+						connected <- conn
+						return nil
+					},
+					onSignal: func(
+						_ context.Context,
+						_ wwr.Connection,
+						msg wwr.Message,
+					) {
+						serverSignals <- msg.Payload()
+					},
+					onRequest: func(
+						_ context.Context,
+						_ wwr.Connection,
+						msg wwr.Message,
+					) (wwr.Payload, error) {
+						serverRequests <- msg.Payload()
+						return payload, nil
+					},
+				},
+				wwr.ServerOptions{},
+			)
+
+			// Initialize client
+			client := newCallbackPoweredClient(
+				server.Addr().String(),
+				wwrclt.Options{
+					DefaultRequestTimeout: 2 * time.Second,
+					Autoconnect:           wwr.Disabled,
+				},
+				callbackPoweredClientHooks{
+					OnSignal: func(msg wwr.Message) {
+						clientSignals <- msg.Payload()
+					},
+					OnServerRequest: func(
+						_ context.Context,
+						msg wwr.Message,
+					) (wwr.Payload, error) {
+						clientRequests <- msg.Payload()
+						return payload, nil
+					},
+				},
+			)
+			defer client.connection.Close()
+			require.NoError(t, client.connection.Connect())
+			conn := <-connected
+
+			// Client request and server reply
+			reply, err := client.connection.Request(
+				context.Background(),
+				"q",
+				payload,
+			)
+			require.NoError(t, err)
+			requireEmpty(t, <-serverRequests)
+			requireEmpty(t, reply)
+
+			// Client signal
+			require.NoError(t, client.connection.Signal("s", payload))
+			requireEmpty(t, <-serverSignals)
+
+			// Server signal
+			require.NoError(t, conn.Signal("s", payload))
+			requireEmpty(t, <-clientSignals)
+
+			// Server request and client reply
+			reply, err = conn.Request(context.Background(), "q", payload)
+			require.NoError(t, err)
+			requireEmpty(t, <-clientRequests)
+			requireEmpty(t, reply)
+		})
+	}
+}
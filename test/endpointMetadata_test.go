@@ -13,7 +13,7 @@ import (
 
 // TestEndpointMetadata tests server endpoint metadata
 func TestEndpointMetadata(t *testing.T) {
-	expectedVersion := "1.4"
+	expectedVersion := "1.5"
 
 	// Initialize webwire server
 	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{})
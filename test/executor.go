@@ -0,0 +1,33 @@
+package test
+
+// fixedPoolExecutor represents a fixed size worker pool executor
+// for testing purposes
+type fixedPoolExecutor struct {
+	tasks chan func()
+}
+
+// newFixedPoolExecutor constructs a new fixed size worker pool executor
+// launching the given number of workers
+func newFixedPoolExecutor(size int) *fixedPoolExecutor {
+	exec := &fixedPoolExecutor{
+		tasks: make(chan func(), 1024),
+	}
+	for i := 0; i < size; i++ {
+		go func() {
+			for task := range exec.tasks {
+				task()
+			}
+		}()
+	}
+	return exec
+}
+
+// Submit implements the webwire.Executor interface
+func (exec *fixedPoolExecutor) Submit(task func()) {
+	exec.tasks <- task
+}
+
+// stop stops all workers after the currently queued tasks are executed
+func (exec *fixedPoolExecutor) stop() {
+	close(exec.tasks)
+}
@@ -0,0 +1,134 @@
+package test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestExecutorFixedPool tests a custom executor with a single worker
+// expecting requests of multiple clients to be executed serially
+func TestExecutorFixedPool(t *testing.T) {
+	clientsNum := 4
+	executor := newFixedPoolExecutor(1)
+	defer executor.stop()
+
+	var concurrent int32
+	var maxConcurrent int32
+	requestsFinished := tmdwg.NewTimedWaitGroup(clientsNum, 5*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				current := atomic.AddInt32(&concurrent, 1)
+				if current > atomic.LoadInt32(&maxConcurrent) {
+					atomic.StoreInt32(&maxConcurrent, current)
+				}
+				time.Sleep(20 * time.Millisecond)
+				atomic.AddInt32(&concurrent, -1)
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			Executor: executor,
+		},
+	)
+
+	// Send requests from multiple clients concurrently
+	for i := 0; i < clientsNum; i++ {
+		client := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 5 * time.Second,
+			},
+			callbackPoweredClientHooks{},
+		)
+		defer client.connection.Close()
+		require.NoError(t, client.connection.Connect())
+
+		go func() {
+			_, err := client.connection.Request(
+				context.Background(),
+				"",
+				wwr.NewPayload(wwr.EncodingBinary, []byte("sample")),
+			)
+			assert.NoError(t, err)
+			requestsFinished.Progress(1)
+		}()
+	}
+
+	require.NoError(t, requestsFinished.Wait(), "Requests not finished")
+	require.Equal(t, int32(1), atomic.LoadInt32(&maxConcurrent))
+}
+
+// TestExecutorFixedPoolServerRequest tests whether a request handler
+// awaiting the reply to a server request doesn't block the resolution
+// of the reply when it occupies the only worker of the pool
+func TestExecutorFixedPoolServerRequest(t *testing.T) {
+	executor := newFixedPoolExecutor(1)
+	defer executor.stop()
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				ctx context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Ask the client for confirmation
+				return conn.Request(
+					ctx,
+					"confirm",
+					wwr.NewPayload(wwr.EncodingBinary, []byte("proceed?")),
+				)
+			},
+		},
+		wwr.ServerOptions{
+			Executor:             executor,
+			ServerRequestTimeout: 2 * time.Second,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 3 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{
+			OnServerRequest: func(
+				_ context.Context,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return wwr.NewPayload(wwr.EncodingBinary, []byte("yes")), nil
+			},
+		},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	reply, err := client.connection.Request(
+		context.Background(),
+		"action",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("data")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []byte("yes"), reply.Data())
+}
@@ -0,0 +1,86 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestIdleTimeout tests whether connections not sending any messages
+// for the duration of the idle timeout are closed by the server
+// while active connections are kept alive
+func TestIdleTimeout(t *testing.T) {
+	idleTimeout := 200 * time.Millisecond
+	serverDisconnected := make(chan wwr.Connection, 1)
+	clientDisconnected := make(chan error, 1)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientDisconnected: func(conn wwr.Connection) {
+				serverDisconnected <- conn
+			},
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			IdleTimeout: idleTimeout,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{
+			OnDisconnected: func(err error) {
+				clientDisconnected <- err
+			},
+		},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Keep the connection active for longer than the idle timeout
+	for i := 0; i < 4; i++ {
+		time.Sleep(idleTimeout / 2)
+		_, err := client.connection.Request(
+			context.Background(),
+			"ping",
+			nil,
+		)
+		require.NoError(t, err)
+	}
+	require.Len(t, serverDisconnected, 0)
+
+	// Expect the idle connection to be closed
+	select {
+	case err := <-clientDisconnected:
+		require.Equal(t, wwr.ConnClosedErr{
+			Code:   1001,
+			Reason: "Idle timeout",
+		}, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Client not disconnected")
+	}
+
+	select {
+	case <-serverDisconnected:
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnClientDisconnected not called")
+	}
+}
@@ -0,0 +1,26 @@
+package test
+
+import (
+	"bytes"
+	"sync"
+)
+
+// syncLogWriter represents a thread-safe log output buffer for testing purposes
+type syncLogWriter struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+// Write implements the io.Writer interface
+func (wrt *syncLogWriter) Write(data []byte) (int, error) {
+	wrt.lock.Lock()
+	defer wrt.lock.Unlock()
+	return wrt.buf.Write(data)
+}
+
+// String returns everything written so far
+func (wrt *syncLogWriter) String() string {
+	wrt.lock.Lock()
+	defer wrt.lock.Unlock()
+	return wrt.buf.String()
+}
@@ -27,7 +27,7 @@ func TestMaxConcSessConn(t *testing.T) {
 	server := setupServer(
 		t,
 		&serverImpl{
-			onClientConnected: func(conn wwr.Connection) {
+			onClientConnected: func(conn wwr.Connection) error {
 				// Created the session for the first connecting client only
 				sessionKeyLock.Lock()
 				defer sessionKeyLock.Unlock()
@@ -35,6 +35,7 @@ func TestMaxConcSessConn(t *testing.T) {
 					assert.NoError(t, conn.CreateSession(nil))
 					sessionKey = conn.SessionKey()
 				}
+				return nil
 			},
 		},
 		wwr.ServerOptions{
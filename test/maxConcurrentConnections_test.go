@@ -0,0 +1,76 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestMaxConcurrentConnections tests refusal of incoming connections
+// exceeding the maximum number of concurrent connections
+// and the release of slots on disconnection
+func TestMaxConcurrentConnections(t *testing.T) {
+	maxConns := 3
+
+	// Initialize server
+	server := setupServer(
+		t,
+		&serverImpl{},
+		wwr.ServerOptions{
+			MaxConcurrentConnections: uint(maxConns),
+		},
+	)
+	serverAddr := server.Addr().String()
+
+	newClient := func() *callbackPoweredClient {
+		return newCallbackPoweredClient(
+			serverAddr,
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+				Autoconnect:           wwr.Disabled,
+			},
+			callbackPoweredClientHooks{},
+		)
+	}
+
+	// Connect as many clients as allowed
+	clients := make([]*callbackPoweredClient, maxConns)
+	for i := 0; i < maxConns; i++ {
+		clt := newClient()
+		defer clt.connection.Close()
+		clients[i] = clt
+		require.NoError(t, clt.connection.Connect())
+	}
+
+	// Expect superfluous connections to be refused before the upgrade
+	superfluous := newClient()
+	defer superfluous.connection.Close()
+	require.Error(t, superfluous.connection.Connect())
+
+	resp, err := http.Get("http://" + serverAddr + "/")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	// Disconnect one of the clients and expect its slot to be released
+	clients[0].connection.Close()
+
+	deadline := time.Now().Add(1 * time.Second)
+	for {
+		resp, err := http.Get("http://" + serverAddr + "/")
+		require.NoError(t, err)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			break
+		}
+		require.True(t, time.Now().Before(deadline), "Slot not released")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	require.NoError(t, superfluous.connection.Connect())
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestMaxMessageSize tests whether the server accepts messages
+// within the size limit and closes connections sending bigger messages
+func TestMaxMessageSize(t *testing.T) {
+	disconnected := tmdwg.NewTimedWaitGroup(1, 2*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientDisconnected: func(_ wwr.Connection) {
+				disconnected.Progress(1)
+			},
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				assert.Len(t, msg.Payload().Data(), 512)
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			MaxMessageSize: 1024,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	// Expect messages within the limit to be accepted
+	_, err := client.connection.Request(
+		context.Background(),
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, make([]byte, 512)),
+	)
+	require.NoError(t, err)
+
+	// Expect the connection to be closed
+	// when a message exceeds the limit
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		200*time.Millisecond,
+	)
+	defer cancel()
+	_, err = client.connection.Request(
+		ctx,
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, make([]byte, 2048)),
+	)
+	require.Error(t, err)
+	require.NoError(t, disconnected.Wait(), "Client not disconnected")
+}
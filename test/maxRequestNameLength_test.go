@@ -0,0 +1,63 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestMaxRequestNameLength tests whether requests with names exceeding
+// the configured limit are rejected with a protocol error before dispatch
+func TestMaxRequestNameLength(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				// Expect only the request within the limit to be dispatched
+				assert.Equal(t, "eightchr", msg.Name())
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			MaxRequestNameLength: 8,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Expect a name within the limit to be accepted
+	_, err := client.connection.Request(
+		context.Background(),
+		"eightchr",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+	)
+	require.NoError(t, err)
+
+	// Expect a name exceeding the limit to be rejected
+	_, err = client.connection.Request(
+		context.Background(),
+		"ninechars",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+	)
+	require.IsType(t, wwr.ProtocolErr{}, err)
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestMaxRequestPayloadSize tests whether requests with payloads exceeding
+// the configured limit are rejected before the handler is invoked
+func TestMaxRequestPayloadSize(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				// Expect only the request within the limit to be dispatched
+				assert.Len(t, msg.Payload().Data(), 16)
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			MaxRequestPayloadSize: 16,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Expect a payload within the limit to be accepted
+	_, err := client.connection.Request(
+		context.Background(),
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, make([]byte, 16)),
+	)
+	require.NoError(t, err)
+
+	// Expect a payload exceeding the limit to be rejected
+	_, err = client.connection.Request(
+		context.Background(),
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, make([]byte, 17)),
+	)
+	require.IsType(t, wwr.PayloadTooLargeErr{}, err)
+}
@@ -0,0 +1,50 @@
+package test
+
+import (
+	"context"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// callbackPoweredMessageHooks represents callback-powered
+// message hooks for testing purposes
+type callbackPoweredMessageHooks struct {
+	Before func(ctx context.Context, message wwr.Message)
+	After  func(ctx context.Context, message wwr.Message, err error)
+
+	SignalReceived func(client wwr.Connection, message wwr.Message)
+}
+
+// BeforeMessage implements the webwire.MessageHooks interface
+// calling the configured callback
+func (hooks *callbackPoweredMessageHooks) BeforeMessage(
+	ctx context.Context,
+	message wwr.Message,
+) {
+	if hooks.Before != nil {
+		hooks.Before(ctx, message)
+	}
+}
+
+// AfterMessage implements the webwire.MessageHooks interface
+// calling the configured callback
+func (hooks *callbackPoweredMessageHooks) AfterMessage(
+	ctx context.Context,
+	message wwr.Message,
+	err error,
+) {
+	if hooks.After != nil {
+		hooks.After(ctx, message, err)
+	}
+}
+
+// OnSignalReceived implements the webwire.MessageHooks interface
+// calling the configured callback
+func (hooks *callbackPoweredMessageHooks) OnSignalReceived(
+	client wwr.Connection,
+	message wwr.Message,
+) {
+	if hooks.SignalReceived != nil {
+		hooks.SignalReceived(client, message)
+	}
+}
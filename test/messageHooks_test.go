@@ -0,0 +1,105 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestMessageHooks tests the before and after message hooks
+// being invoked for both requests and signals
+func TestMessageHooks(t *testing.T) {
+	signalHandled := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	lock := sync.Mutex{}
+	var events []string
+
+	record := func(event string) {
+		lock.Lock()
+		events = append(events, event)
+		lock.Unlock()
+	}
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				record("request:" + msg.Name())
+				return nil, wwr.ReqErr{Code: "SAMPLE_ERROR"}
+			},
+			onSignal: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) {
+				record("signal:" + msg.Name())
+			},
+		},
+		wwr.ServerOptions{
+			Hooks: &callbackPoweredMessageHooks{
+				Before: func(_ context.Context, msg wwr.Message) {
+					record("before:" + msg.Name())
+				},
+				After: func(_ context.Context, msg wwr.Message, err error) {
+					if err != nil {
+						record("after:" + msg.Name() + ":" + err.Error())
+					} else {
+						record("after:" + msg.Name())
+					}
+					if msg.Name() == "sig" {
+						signalHandled.Progress(1)
+					}
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Send a request
+	_, err := client.connection.Request(
+		context.Background(),
+		"req",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("sample")),
+	)
+	require.Error(t, err)
+
+	// Send a signal
+	require.NoError(t, client.connection.Signal(
+		"sig",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("sample")),
+	))
+	require.NoError(t, signalHandled.Wait(), "Signal hooks not invoked")
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Equal(t, []string{
+		"before:req",
+		"request:req",
+		"after:req:" + wwr.ReqErr{Code: "SAMPLE_ERROR"}.Error(),
+		"before:sig",
+		"signal:sig",
+		"after:sig",
+	}, events)
+}
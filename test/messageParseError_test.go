@@ -0,0 +1,77 @@
+package test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+)
+
+// TestMessageParseError tests whether the parse error handler receives
+// the raw bytes of malformed messages alongside the parser error
+func TestMessageParseError(t *testing.T) {
+	parseErrorHandled := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	var rawMessage []byte
+
+	// Message with a name length flag bigger than the name
+	malformed := []byte{
+		message.MsgRequestBinary, // Message type identifier
+		0, 0, 0, 0, 0, 0, 0, 1,   // Request identifier
+		3,     // Name length flag
+		0x041, // Name
+	}
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{},
+		wwr.ServerOptions{
+			ParseErrorHandler: &callbackPoweredParseErrorHandler{
+				ParseError: func(
+					conn wwr.Connection,
+					raw []byte,
+					err error,
+				) {
+					assert.NotNil(t, conn)
+					assert.Error(t, err)
+					rawMessage = raw
+					parseErrorHandled.Progress(1)
+				},
+			},
+		},
+	)
+
+	// Setup a regular websocket connection
+	endpointURL := url.URL{
+		Scheme: "ws",
+		Host:   server.Addr().String(),
+		Path:   "/",
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(endpointURL.String(), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Read the handshake before sending the malformed message
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, handshake, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, message.MsgHandshake, handshake[0])
+
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, malformed))
+
+	// Expect the protocol violation to still be answered
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, response, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, message.MsgReplyProtocolError, response[0])
+
+	require.NoError(t, parseErrorHandled.Wait(), "Parse error not handled")
+	require.Equal(t, malformed, rawMessage)
+}
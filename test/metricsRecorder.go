@@ -0,0 +1,34 @@
+package test
+
+import (
+	"net"
+	"time"
+)
+
+// callbackPoweredMetricsRecorder represents a callback-powered
+// metrics recorder for testing purposes
+type callbackPoweredMetricsRecorder struct {
+	SessionLookup func(duration time.Duration)
+	Upgrade       func(remoteAddr net.Addr, duration time.Duration)
+}
+
+// RecordSessionLookup implements the webwire.MetricsRecorder interface
+// calling the configured callback
+func (rec *callbackPoweredMetricsRecorder) RecordSessionLookup(
+	duration time.Duration,
+) {
+	if rec.SessionLookup != nil {
+		rec.SessionLookup(duration)
+	}
+}
+
+// RecordUpgrade implements the webwire.MetricsRecorder interface
+// calling the configured callback
+func (rec *callbackPoweredMetricsRecorder) RecordUpgrade(
+	remoteAddr net.Addr,
+	duration time.Duration,
+) {
+	if rec.Upgrade != nil {
+		rec.Upgrade(remoteAddr, duration)
+	}
+}
@@ -0,0 +1,163 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestObserverMode tests whether the server rejects requests,
+// signals and session creation of observer connections
+// while observers still receive signals
+func TestObserverMode(t *testing.T) {
+	connected := make(chan wwr.Connection, 1)
+	signalReceived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) error {
+				connected <- conn
+				return nil
+			},
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				t.Error("Request of an observer not rejected")
+				return nil, nil
+			},
+			onSignal: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) {
+				t.Error("Signal of an observer not ignored")
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect:           wwr.Disabled,
+			DefaultRequestTimeout: 2 * time.Second,
+			ObserverMode:          wwr.Enabled,
+		},
+		callbackPoweredClientHooks{
+			OnSignal: func(msg wwr.Message) {
+				assert.Equal(t, "broadcast", msg.Name())
+				signalReceived.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	conn := <-connected
+	require.True(t, conn.IsObserver())
+	require.True(t, conn.Info().Observer)
+
+	// Expect requests to be rejected
+	_, err := client.connection.Request(
+		context.Background(),
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+	)
+	require.IsType(t, wwr.ObserverErr{}, err)
+
+	// Expect signals to be ignored
+	require.NoError(t, client.connection.Signal(
+		"notify",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+	))
+
+	// Expect session creation to be refused
+	require.IsType(t, wwr.ObserverErr{}, conn.CreateSession(nil))
+	require.False(t, conn.HasSession())
+
+	// Expect broadcasts to be received
+	for _, c := range server.Connections() {
+		require.NoError(t, c.Signal(
+			"broadcast",
+			wwr.NewPayload(wwr.EncodingBinary, []byte("update")),
+		))
+	}
+	require.NoError(t, signalReceived.Wait(), "Signal not received")
+}
+
+// TestObserverSessionLimit tests whether observers restoring a session
+// count towards the session connection limit
+// unless the UnlimitedObservers option is enabled
+func TestObserverSessionLimit(t *testing.T) {
+	for _, unlimited := range []wwr.OptionValue{wwr.Disabled, wwr.Enabled} {
+		// Initialize webwire server
+		server := setupServer(
+			t,
+			&serverImpl{
+				onRequest: func(
+					_ context.Context,
+					conn wwr.Connection,
+					_ wwr.Message,
+				) (wwr.Payload, error) {
+					return nil, conn.CreateSession(nil)
+				},
+			},
+			wwr.ServerOptions{
+				MaxSessionConnections: 1,
+				UnlimitedObservers:    unlimited,
+			},
+		)
+
+		// Create the session occupying the only slot
+		owner := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				Autoconnect:           wwr.Disabled,
+				DefaultRequestTimeout: 2 * time.Second,
+			},
+			callbackPoweredClientHooks{},
+		)
+		defer owner.connection.Close()
+		require.NoError(t, owner.connection.Connect())
+		_, err := owner.connection.Request(
+			context.Background(),
+			"login",
+			wwr.NewPayload(wwr.EncodingBinary, []byte("auth")),
+		)
+		require.NoError(t, err)
+
+		// Try to restore the session as an observer
+		observer := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				Autoconnect:           wwr.Disabled,
+				DefaultRequestTimeout: 2 * time.Second,
+				ObserverMode:          wwr.Enabled,
+			},
+			callbackPoweredClientHooks{},
+		)
+		defer observer.connection.Close()
+		require.NoError(t, observer.connection.Connect())
+
+		err = observer.connection.RestoreSession(
+			[]byte(owner.connection.Session().Key),
+		)
+		if unlimited == wwr.Enabled {
+			require.NoError(t, err)
+		} else {
+			require.IsType(t, wwr.MaxSessConnsReachedErr{}, err)
+		}
+	}
+}
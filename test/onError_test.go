@@ -0,0 +1,93 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// reportedError represents an error reported to the OnError hook
+type reportedError struct {
+	err    error
+	errCtx wwr.ErrorContext
+}
+
+// TestOnError tests whether internal errors are reported to the OnError hook
+// along with the operation and the affected connection
+func TestOnError(t *testing.T) {
+	handlerErr := errors.New("handler failure")
+	lookupErr := errors.New("storage unavailable")
+
+	var lock sync.Mutex
+	var reported []reportedError
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, handlerErr
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager: &callbackPoweredSessionManager{
+				SessionLookup: func(string) (wwr.SessionLookupResult, error) {
+					return nil, lookupErr
+				},
+			},
+			OnError: func(err error, errCtx wwr.ErrorContext) {
+				lock.Lock()
+				defer lock.Unlock()
+				reported = append(reported, reportedError{err, errCtx})
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(context.Background(), "failing", nil)
+	require.Error(t, err)
+
+	err = client.connection.RestoreSession([]byte("somekey"))
+	require.Error(t, err)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Len(t, reported, 2)
+
+	// Expect the request handler failure to be reported
+	require.Equal(t, handlerErr, reported[0].err)
+	require.Equal(t, wwr.ErrOpRequest, reported[0].errCtx.Operation)
+	require.Equal(t, "failing", reported[0].errCtx.MessageName)
+	require.NotNil(t, reported[0].errCtx.Connection)
+
+	// Expect the session lookup failure to be reported
+	require.Equal(t, lookupErr, reported[1].err)
+	require.Equal(t, wwr.ErrOpSessionRestoration, reported[1].errCtx.Operation)
+	require.Equal(t, "somekey", reported[1].errCtx.SessionKey)
+	require.Equal(
+		t,
+		reported[0].errCtx.Connection,
+		reported[1].errCtx.Connection,
+	)
+}
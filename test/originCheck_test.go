@@ -0,0 +1,71 @@
+package test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// dialWithOrigin tries to establish a raw websocket connection
+// to the given server sending the given origin
+func dialWithOrigin(server wwr.Server, origin string) error {
+	endpointURL := url.URL{
+		Scheme: "ws",
+		Host:   server.Addr().String(),
+		Path:   "/",
+	}
+	header := http.Header{}
+	if origin != "" {
+		header.Set("Origin", origin)
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(endpointURL.String(), header)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// TestOriginCheckDefault tests whether the server accepts same-origin
+// and non-browser upgrade requests only by default
+func TestOriginCheckDefault(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{})
+
+	require.NoError(t, dialWithOrigin(server, ""))
+	require.NoError(t, dialWithOrigin(
+		server,
+		"http://"+server.Addr().String(),
+	))
+	require.Equal(
+		t,
+		websocket.ErrBadHandshake,
+		dialWithOrigin(server, "http://evil.example"),
+	)
+}
+
+// TestOriginCheckAllowedOrigins tests whether the server accepts
+// upgrade requests from allowed origins only
+func TestOriginCheckAllowedOrigins(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{
+		CheckOrigin: wwr.AllowedOrigins([]string{"https://app.example"}),
+	})
+
+	require.NoError(t, dialWithOrigin(server, ""))
+	require.NoError(t, dialWithOrigin(server, "https://APP.example"))
+	require.Equal(
+		t,
+		websocket.ErrBadHandshake,
+		dialWithOrigin(server, "http://"+server.Addr().String()),
+	)
+	require.Equal(
+		t,
+		websocket.ErrBadHandshake,
+		dialWithOrigin(server, "http://evil.example"),
+	)
+}
@@ -12,12 +12,13 @@ import (
 )
 
 // TestOverridingConnectionSession tests overriding of a connection session
+// with session replacement disabled
 func TestOverridingConnectionSession(t *testing.T) {
 	// Initialize server
 	server := setupServer(
 		t,
 		&serverImpl{
-			onClientConnected: func(conn wwr.Connection) {
+			onClientConnected: func(conn wwr.Connection) error {
 				assert.NoError(t, conn.CreateSession(nil))
 				sessionKey := conn.SessionKey()
 
@@ -26,9 +27,12 @@ func TestOverridingConnectionSession(t *testing.T) {
 
 				// Ensure the session didn't change
 				assert.Equal(t, sessionKey, conn.SessionKey())
+				return nil
 			},
 		},
-		wwr.ServerOptions{},
+		wwr.ServerOptions{
+			ReplaceSessions: wwr.Disabled,
+		},
 	)
 
 	// Initialize client
@@ -0,0 +1,23 @@
+package test
+
+import (
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// callbackPoweredParseErrorHandler represents a callback-powered
+// message parse error handler for testing purposes
+type callbackPoweredParseErrorHandler struct {
+	ParseError func(conn wwr.Connection, raw []byte, err error)
+}
+
+// OnMessageParseError implements the webwire.ParseErrorHandler interface
+// calling the configured callback
+func (handler *callbackPoweredParseErrorHandler) OnMessageParseError(
+	conn wwr.Connection,
+	raw []byte,
+	err error,
+) {
+	if handler.ParseError != nil {
+		handler.ParseError(conn, raw, err)
+	}
+}
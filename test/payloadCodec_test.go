@@ -0,0 +1,129 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// testCodecMessage represents a structured message exchanged
+// through codec encoded payloads
+type testCodecMessage struct {
+	Text   string
+	Number int
+}
+
+// testCodec represents a JSON based payload codec of another name
+type testCodec struct {
+	wwr.JSONCodec
+}
+
+// Name implements the webwire.Codec interface
+func (testCodec) Name() string {
+	return "test"
+}
+
+// TestPayloadCodec tests exchanging structured values
+// through codec encoded request and reply payloads
+func TestPayloadCodec(t *testing.T) {
+	codec := wwr.JSONCodec{}
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				var request testCodecMessage
+				assert.NoError(t, msg.Payload().DecodeInto(&request))
+				assert.Equal(t, testCodecMessage{"request", 1}, request)
+
+				return wwr.NewCodecPayload(
+					codec,
+					testCodecMessage{"reply", 2},
+				)
+			},
+		},
+		wwr.ServerOptions{
+			Codec: codec,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+			Codec:                 codec,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	payload, err := wwr.NewCodecPayload(
+		codec,
+		testCodecMessage{"request", 1},
+	)
+	require.NoError(t, err)
+
+	reply, err := client.connection.Request(
+		context.Background(),
+		"codec",
+		payload,
+	)
+	require.NoError(t, err)
+
+	var decoded testCodecMessage
+	require.NoError(t, reply.DecodeInto(&decoded))
+	require.Equal(t, testCodecMessage{"reply", 2}, decoded)
+}
+
+// TestPayloadCodecMismatch tests connecting to a server
+// using another payload codec than the client
+func TestPayloadCodecMismatch(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{},
+		wwr.ServerOptions{
+			Codec: wwr.JSONCodec{},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+			Codec:                 testCodec{},
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	err := client.connection.Connect()
+	require.Error(t, err)
+	require.IsType(t, wwr.CodecMismatchErr{}, err)
+}
+
+// TestPayloadNoCodec tests decoding payloads without a configured codec
+func TestPayloadNoCodec(t *testing.T) {
+	var decoded testCodecMessage
+	err := wwr.NewPayload(wwr.EncodingBinary, []byte("{}")).DecodeInto(
+		&decoded,
+	)
+	require.Error(t, err)
+	require.IsType(t, wwr.NoCodecErr{}, err)
+}
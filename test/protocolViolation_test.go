@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
 
 	"github.com/stretchr/testify/require"
 
@@ -27,7 +28,7 @@ func TestProtocolViolation(t *testing.T) {
 
 	// Setup a regular websocket connection
 	setupAndSend := func(
-		message []byte,
+		msgData []byte,
 	) (response []byte, writeErr, readErr error) {
 		endpointUrl := url.URL{
 			Scheme: "ws",
@@ -38,7 +39,14 @@ func TestProtocolViolation(t *testing.T) {
 		require.NoError(t, err)
 		defer conn.Close()
 
-		writeErr = conn.WriteMessage(websocket.BinaryMessage, message)
+		// Read and verify the handshake sent by the server
+		// before sending the actual message
+		conn.SetReadDeadline(time.Now().Add(defaultReadTimeout))
+		_, handshake, err := conn.ReadMessage()
+		require.NoError(t, err)
+		require.Equal(t, message.MsgHandshake, handshake[0])
+
+		writeErr = conn.WriteMessage(websocket.BinaryMessage, msgData)
 		if writeErr != nil {
 			return nil, writeErr, nil
 		}
@@ -65,18 +73,16 @@ func TestProtocolViolation(t *testing.T) {
 	// Test a message with an invalid name length flag (bigger than name)
 	// and expect the server to return a protocol violation error response
 	func() {
-		msg := []byte{
-			message.MsgRequestBinary, // Message type identifier
-			0, 0, 0, 0, 0, 0, 0, 0,   // Request identifier
-			3,     // Name length flag
-			0x041, // Name
-		}
+		identifier := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
+		msg := message.NewRequest(identifier, "A", pld.Payload{})
+		msg[message.NameLenOffset] = 3
 		response, writeErr, readErr := setupAndSend(msg)
 		require.NoError(t, writeErr)
 		require.NoError(t, readErr)
-		require.Equal(t, []byte{
-			message.MsgReplyProtocolError, // Message type identifier
-			0, 0, 0, 0, 0, 0, 0, 0,        // Request identifier
-		}, response)
+
+		reply, err := message.Parse(response)
+		require.NoError(t, err)
+		require.Equal(t, message.MsgReplyProtocolError, reply.Type)
+		require.Equal(t, identifier, reply.Identifier)
 	}()
 }
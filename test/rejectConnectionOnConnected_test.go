@@ -0,0 +1,82 @@
+package test
+
+import (
+	"fmt"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+	"github.com/qbeon/webwire-go/message"
+)
+
+// TestRejectConnectionOnConnected tests rejecting a connection
+// from the OnClientConnected hook of the server
+func TestRejectConnectionOnConnected(t *testing.T) {
+	clientDisconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	disconnectedHookCalled := int32(0)
+
+	// Initialize webwire server rejecting all connections
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(_ wwr.Connection) error {
+				return fmt.Errorf("unauthorized connection")
+			},
+			onClientDisconnected: func(_ wwr.Connection) {
+				atomic.StoreInt32(&disconnectedHookCalled, 1)
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Verify the close reason using a regular websocket connection
+	endpointURL := url.URL{
+		Scheme: "ws",
+		Host:   server.Addr().String(),
+		Path:   "/",
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(endpointURL.String(), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, handshake, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, message.MsgHandshake, handshake[0])
+
+	_, _, err = conn.ReadMessage()
+	require.Error(t, err)
+	require.IsType(t, &websocket.CloseError{}, err)
+	closeErr := err.(*websocket.CloseError)
+	require.Equal(t, websocket.ClosePolicyViolation, closeErr.Code)
+	require.Equal(t, "unauthorized connection", closeErr.Text)
+
+	// Verify a webwire client is disconnected
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{
+			OnDisconnected: func(_ error) {
+				clientDisconnected.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+	require.NoError(t,
+		clientDisconnected.Wait(),
+		"Client not disconnected",
+	)
+	require.Equal(t, int32(0), atomic.LoadInt32(&disconnectedHookCalled))
+}
@@ -0,0 +1,23 @@
+package test
+
+import (
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// callbackPoweredRequestFilter represents a callback-powered
+// request filter for testing purposes
+type callbackPoweredRequestFilter struct {
+	Filter func(conn wwr.Connection, message wwr.Message) error
+}
+
+// FilterRequest implements the webwire.RequestFilter interface
+// calling the configured callback
+func (filter *callbackPoweredRequestFilter) FilterRequest(
+	conn wwr.Connection,
+	message wwr.Message,
+) error {
+	if filter.Filter != nil {
+		return filter.Filter(conn, message)
+	}
+	return nil
+}
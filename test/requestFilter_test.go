@@ -0,0 +1,85 @@
+package test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestRequestFilter tests rejecting requests in the request filter
+// expecting the request handler to not be invoked
+// and the filter error to be returned to the client
+func TestRequestFilter(t *testing.T) {
+	var handled int32
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				atomic.AddInt32(&handled, 1)
+				return wwr.NewPayload(
+					wwr.EncodingBinary,
+					[]byte("handled"),
+				), nil
+			},
+		},
+		wwr.ServerOptions{
+			RequestFilter: &callbackPoweredRequestFilter{
+				Filter: func(_ wwr.Connection, msg wwr.Message) error {
+					if msg.Name() == "forbidden" {
+						return wwr.ReqErr{
+							Code:    "FORBIDDEN",
+							Message: "request rejected",
+						}
+					}
+					return nil
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Send a rejected request
+	reply, err := client.connection.Request(
+		context.Background(),
+		"forbidden",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("sample")),
+	)
+	require.Nil(t, reply)
+	require.Error(t, err)
+	require.IsType(t, wwr.ReqErr{}, err)
+	require.Equal(t, "FORBIDDEN", err.(wwr.ReqErr).Code)
+	require.Equal(t, int32(0), atomic.LoadInt32(&handled))
+
+	// Send an admitted request
+	reply, err = client.connection.Request(
+		context.Background(),
+		"allowed",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("sample")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []byte("handled"), reply.Data())
+	require.Equal(t, int32(1), atomic.LoadInt32(&handled))
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+type middlewareCtxKey struct{}
+
+// TestRequestMiddleware tests whether the request middleware is applied
+// in order around the request handler and can short-circuit requests
+func TestRequestMiddleware(t *testing.T) {
+	lock := sync.Mutex{}
+	var events []string
+
+	record := func(event string) {
+		lock.Lock()
+		events = append(events, event)
+		lock.Unlock()
+	}
+
+	// Records the invocation and passes a value down the chain
+	outer := func(next wwr.RequestHandler) wwr.RequestHandler {
+		return func(
+			ctx context.Context,
+			conn wwr.Connection,
+			msg wwr.Message,
+		) (wwr.Payload, error) {
+			record("outer:" + msg.Name())
+			ctx = context.WithValue(ctx, middlewareCtxKey{}, "outer")
+			reply, err := next(ctx, conn, msg)
+			record("outer:done")
+			return reply, err
+		}
+	}
+
+	// Short-circuits blocked requests
+	inner := func(next wwr.RequestHandler) wwr.RequestHandler {
+		return func(
+			ctx context.Context,
+			conn wwr.Connection,
+			msg wwr.Message,
+		) (wwr.Payload, error) {
+			record("inner:" + msg.Name())
+			if msg.Name() == "blocked" {
+				return nil, wwr.ReqErr{Code: "BLOCKED"}
+			}
+			return next(ctx, conn, msg)
+		}
+	}
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				ctx context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				assert.Equal(t, "outer", ctx.Value(middlewareCtxKey{}))
+				record("handler:" + msg.Name())
+				return wwr.NewPayload(wwr.EncodingBinary, []byte("reply")), nil
+			},
+		},
+		wwr.ServerOptions{
+			RequestMiddleware: []wwr.RequestMiddleware{outer, inner},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Expect the request to pass through the middleware
+	reply, err := client.connection.Request(
+		context.Background(),
+		"allowed",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []byte("reply"), reply.Data())
+
+	// Expect the request to be short-circuited by the inner middleware
+	_, err = client.connection.Request(
+		context.Background(),
+		"blocked",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+	)
+	require.IsType(t, wwr.ReqErr{}, err)
+	require.Equal(t, "BLOCKED", err.(wwr.ReqErr).Code)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Equal(t, []string{
+		"outer:allowed",
+		"inner:allowed",
+		"handler:allowed",
+		"outer:done",
+		"outer:blocked",
+		"inner:blocked",
+		"outer:done",
+	}, events)
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestRequestRejections tests whether the client fails requests
+// with the typed error of the corresponding rejection reply of the server
+func TestRequestRejections(t *testing.T) {
+	rejections := map[string]error{
+		"unauthenticated": wwr.UnauthenticatedErr{},
+		"payloadTooLarge": wwr.PayloadTooLargeErr{},
+		"rateLimited":     wwr.RateLimitedErr{},
+		"observer":        wwr.ObserverErr{},
+	}
+	errorLog := &syncLogWriter{}
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{},
+		wwr.ServerOptions{
+			RequestFilter: &callbackPoweredRequestFilter{
+				Filter: func(_ wwr.Connection, msg wwr.Message) error {
+					return rejections[msg.Name()]
+				},
+			},
+			ErrorLog: log.New(errorLog, "ERR: ", 0),
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	for name, rejection := range rejections {
+		_, err := client.connection.Request(
+			context.Background(),
+			name,
+			wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+		)
+		require.IsType(t, rejection, err, name)
+	}
+
+	// Expect the rejections not to be logged as internal errors
+	require.Empty(t, errorLog.String())
+}
@@ -0,0 +1,90 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestRequireSessionForRequests tests rejecting requests from connections
+// without a session while still allowing logging in using a signal
+func TestRequireSessionForRequests(t *testing.T) {
+	sessionCreated := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onSignal: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) {
+				// Log in creating a new session
+				assert.NoError(t, conn.CreateSession(nil))
+			},
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				assert.True(t, conn.HasSession())
+				return wwr.NewPayload(
+					wwr.EncodingBinary,
+					[]byte("reply"),
+				), nil
+			},
+		},
+		wwr.ServerOptions{
+			RequireSessionForRequests: wwr.Enabled,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{
+			OnSessionCreated: func(_ *wwr.Session) {
+				sessionCreated.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Expect anonymous requests to be rejected
+	_, err := client.connection.Request(
+		context.Background(),
+		"anonymous",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("sample")),
+	)
+	require.Error(t, err)
+	require.IsType(t, wwr.UnauthenticatedErr{}, err)
+
+	// Log in using a signal
+	require.NoError(t, client.connection.Signal(
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	))
+	require.NoError(t, sessionCreated.Wait(), "Session not created")
+
+	// Expect authenticated requests to be accepted
+	reply, err := client.connection.Request(
+		context.Background(),
+		"authenticated",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("sample")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []byte("reply"), reply.Data())
+}
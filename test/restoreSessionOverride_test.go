@@ -0,0 +1,116 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// TestRestoreSessionOverride tests restoring a session on a connection
+// that already has another session expecting the connection to be moved
+// from the previous session to the restored one
+func TestRestoreSessionOverride(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Try to create a new session
+				err := conn.CreateSession(nil)
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Create session B using a regular client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+	_, err := client.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	require.NoError(t, err)
+	sessionKeyB := client.connection.Session().Key
+
+	// Setup a regular websocket connection
+	endpointURL := url.URL{
+		Scheme: "ws",
+		Host:   server.Addr().String(),
+		Path:   "/",
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(endpointURL.String(), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	readMessage := func(expectedType byte) message.Message {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		var parsed message.Message
+		_, err = parsed.Parse(data)
+		require.NoError(t, err)
+		require.Equal(t, expectedType, parsed.Type)
+		return parsed
+	}
+	readMessage(message.MsgHandshake)
+
+	// Create session A on the websocket connection
+	require.NoError(t, conn.WriteMessage(
+		websocket.BinaryMessage,
+		message.NewRequestMessage(
+			[8]byte{1},
+			"login",
+			pld.Binary,
+			[]byte("credentials"),
+		),
+	))
+	created := readMessage(message.MsgSessionCreated)
+	var sessionA wwr.JSONEncodedSession
+	require.NoError(t, json.Unmarshal(created.Payload.Data, &sessionA))
+	readMessage(message.MsgReplyBinary)
+
+	require.Equal(t, 1, server.SessionConnectionsNum(sessionA.Key))
+	require.Equal(t, 1, server.SessionConnectionsNum(sessionKeyB))
+
+	// Restore session B on the connection already having session A
+	require.NoError(t, conn.WriteMessage(
+		websocket.BinaryMessage,
+		message.NewNamelessRequestMessage(
+			message.MsgRestoreSession,
+			[8]byte{2},
+			[]byte(sessionKeyB),
+		),
+	))
+	readMessage(message.MsgReplyUtf8)
+
+	// Expect the connection to be moved from session A to session B
+	require.Equal(t, -1, server.SessionConnectionsNum(sessionA.Key))
+	require.Equal(t, 2, server.SessionConnectionsNum(sessionKeyB))
+	require.Equal(t, 1, server.ActiveSessionsNum())
+}
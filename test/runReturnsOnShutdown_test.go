@@ -0,0 +1,69 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestRunReturnsOnShutdown tests whether server.Run returns nil
+// rather than an error after a clean shutdown
+func TestRunReturnsOnShutdown(t *testing.T) {
+	server, err := wwr.NewServer(
+		&serverImpl{
+			beforeUpgrade: func(
+				_ http.ResponseWriter,
+				_ *http.Request,
+			) wwr.ConnectionOptions {
+				return wwr.AcceptConnection(wwr.UnlimitedConcurrency)
+			},
+			onClientConnected:    func(_ wwr.Connection) error { return nil },
+			onClientDisconnected: func(_ wwr.Connection) {},
+		},
+		wwr.ServerOptions{
+			Address:        "127.0.0.1:0",
+			SessionManager: wwr.NewInMemorySessionManager(),
+		},
+	)
+	require.NoError(t, err)
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- server.Run()
+	}()
+
+	// Ensure the server is up and running before shutting it down
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	require.NoError(t, server.Shutdown())
+
+	select {
+	case err := <-runErr:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run didn't return after shutdown")
+	}
+
+	// Ensure the server doesn't accept requests anymore
+	_, err = client.connection.Request(
+		context.Background(),
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+	)
+	require.Error(t, err)
+}
@@ -0,0 +1,25 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// TestServerAddrInUse tests whether constructing a server on an address
+// that's already in use fails with a ListenErr error
+func TestServerAddrInUse(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{})
+
+	// Try to initialize another server on the same address
+	second, err := wwr.NewServer(&serverImpl{}, wwr.ServerOptions{
+		Address: server.Addr().String(),
+	})
+	require.Nil(t, second)
+	require.IsType(t, wwr.ListenErr{}, err)
+	require.True(t, err.(wwr.ListenErr).IsAddrInUse())
+	require.Equal(t, server.Addr().String(), err.(wwr.ListenErr).Address)
+}
@@ -0,0 +1,77 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestServerConnections tests reading the session keys of both anonymous
+// and authenticated connections returned by server.Connections
+func TestServerConnections(t *testing.T) {
+	connected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(_ wwr.Connection) error {
+				connected.Progress(1)
+				return nil
+			},
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Try to create a new session
+				err := conn.CreateSession(nil)
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+	require.NoError(t, connected.Wait(), "Client not connected")
+
+	// Verify the anonymous connection has no session key
+	connections := server.Connections()
+	require.Len(t, connections, 1)
+	require.False(t, connections[0].HasSession())
+	require.Equal(t, "", connections[0].SessionKey())
+
+	// Create a session
+	_, err := client.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	require.NoError(t, err)
+
+	// Verify the session key of the authenticated connection
+	connections = server.Connections()
+	require.Len(t, connections, 1)
+	require.Equal(t,
+		client.connection.Session().Key,
+		connections[0].SessionKey(),
+	)
+}
@@ -13,7 +13,7 @@ type serverImpl struct {
 		resp http.ResponseWriter,
 		req *http.Request,
 	) wwr.ConnectionOptions
-	onClientConnected    func(connection wwr.Connection)
+	onClientConnected    func(connection wwr.Connection) error
 	onClientDisconnected func(connection wwr.Connection)
 	onSignal             func(
 		ctx context.Context,
@@ -42,8 +42,8 @@ func (srv *serverImpl) BeforeUpgrade(
 }
 
 // OnClientConnected implements the webwire.ServerImplementation interface
-func (srv *serverImpl) OnClientConnected(conn wwr.Connection) {
-	srv.onClientConnected(conn)
+func (srv *serverImpl) OnClientConnected(conn wwr.Connection) error {
+	return srv.onClientConnected(conn)
 }
 
 // OnClientDisconnected implements the webwire.ServerImplementation interface
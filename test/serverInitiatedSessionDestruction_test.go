@@ -104,7 +104,7 @@ func TestServerInitiatedSessionDestruction(t *testing.T) {
 				// Mark the client-side session creation callback executed
 				sessionCreationCallbackCalled.Progress(1)
 			},
-			OnSessionClosed: func() {
+			OnSessionClosed: func(_ string) {
 				// Ensure this callback is called during the
 				assert.Equal(t,
 					3, currentStep,
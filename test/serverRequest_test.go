@@ -0,0 +1,136 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// setupServerRequestTest sets up a server and a connected client
+// replying to server requests using the given hook
+// and returns the server-side connection of the client
+func setupServerRequestTest(
+	t *testing.T,
+	onServerRequest func(context.Context, wwr.Message) (wwr.Payload, error),
+) (*callbackPoweredClient, wwr.Connection) {
+	connected := make(chan wwr.Connection, 1)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) error {
+				connected <- conn
+				return nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{
+			OnServerRequest: onServerRequest,
+		},
+	)
+	require.NoError(t, client.connection.Connect())
+
+	return client, <-connected
+}
+
+// TestServerRequest tests sending requests from the server to the client
+// and receiving the replies
+func TestServerRequest(t *testing.T) {
+	client, conn := setupServerRequestTest(t, func(
+		_ context.Context,
+		message wwr.Message,
+	) (wwr.Payload, error) {
+		switch message.Name() {
+		case "confirm":
+			assert.Equal(t, []byte("proceed?"), message.Payload().Data())
+			return wwr.NewPayload(wwr.EncodingUtf8, []byte("yes")), nil
+		case "decline":
+			return nil, wwr.ReqErr{Code: "DECLINED", Message: "no"}
+		case "no code":
+			return nil, wwr.ReqErr{Message: "no"}
+		case "invalid code":
+			return nil, &wwr.ReqErr{Code: "DE\nCLINED", Message: "no"}
+		case "nil error":
+			var err *wwr.ReqErr
+			return nil, err
+		}
+		return nil, fmt.Errorf("unexpected server request")
+	})
+	defer client.connection.Close()
+
+	// Expect the reply of the client to be returned
+	reply, err := conn.Request(
+		context.Background(),
+		"confirm",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("proceed?")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, wwr.EncodingUtf8, reply.Encoding())
+	require.Equal(t, []byte("yes"), reply.Data())
+
+	// Expect error replies to be returned as request errors
+	_, err = conn.Request(context.Background(), "decline", nil)
+	require.Error(t, err)
+	require.Equal(t, wwr.ReqErr{Code: "DECLINED", Message: "no"}, err)
+
+	// Expect internal client errors to not be exposed
+	_, err = conn.Request(context.Background(), "unknown", nil)
+	require.Error(t, err)
+	require.Equal(t, wwr.ClientInternalErr{}, err)
+
+	// Expect invalid request errors to be replied to as internal errors
+	for _, name := range []string{"no code", "invalid code", "nil error"} {
+		_, err = conn.Request(context.Background(), name, nil)
+		require.Error(t, err)
+		require.Equal(t, wwr.ClientInternalErr{}, err)
+	}
+}
+
+// TestServerRequestDeadline tests server requests exceeding
+// the deadline of the context and server requests pending
+// while the connection is closed
+func TestServerRequestDeadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	client, conn := setupServerRequestTest(t, func(
+		_ context.Context,
+		_ wwr.Message,
+	) (wwr.Payload, error) {
+		<-release
+		return nil, nil
+	})
+
+	// Expect the request to fail when the deadline is exceeded
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := conn.Request(ctx, "slow", nil)
+	require.Error(t, err)
+	require.IsType(t, wwr.DeadlineExceededErr{}, err)
+
+	// Expect the request to fail when the connection is closed
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		client.connection.Close()
+	}()
+	_, err = conn.Request(context.Background(), "slow", nil)
+	require.Error(t, err)
+	require.IsType(t, wwr.DisconnectedErr{}, err)
+}
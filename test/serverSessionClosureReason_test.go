@@ -0,0 +1,90 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// TestServerSessionClosureReason tests server-initiated session closure
+// with a reason expecting the reason to be sent to the client
+func TestServerSessionClosureReason(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Try to create a new session
+				err := conn.CreateSession(nil)
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Setup a regular websocket connection
+	endpointURL := url.URL{
+		Scheme: "ws",
+		Host:   server.Addr().String(),
+		Path:   "/",
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(endpointURL.String(), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	readMessage := func(expectedType byte) message.Message {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		var parsed message.Message
+		_, err = parsed.Parse(data)
+		require.NoError(t, err)
+		require.Equal(t, expectedType, parsed.Type)
+		return parsed
+	}
+	readMessage(message.MsgHandshake)
+
+	// Create a session
+	require.NoError(t, conn.WriteMessage(
+		websocket.BinaryMessage,
+		message.NewRequestMessage(
+			[8]byte{1},
+			"login",
+			pld.Binary,
+			[]byte("credentials"),
+		),
+	))
+	created := readMessage(message.MsgSessionCreated)
+	var session wwr.JSONEncodedSession
+	require.NoError(t, json.Unmarshal(created.Payload.Data, &session))
+	readMessage(message.MsgReplyBinary)
+
+	// Close the session from the server side providing a reason
+	affectedConns, closeErrors, err := server.CloseSessionWithReason(
+		session.Key,
+		"closed by admin",
+	)
+	require.NoError(t, err)
+	require.Len(t, affectedConns, 1)
+	require.Len(t, closeErrors, 1)
+	require.NoError(t, closeErrors[0])
+
+	// Expect the reason to be sent along with the notification
+	closed := readMessage(message.MsgSessionClosed)
+	require.Equal(t, "closed by admin", string(closed.Payload.Data))
+}
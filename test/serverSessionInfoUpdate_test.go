@@ -0,0 +1,118 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestServerSessionInfoUpdate tests updating the session info
+// of all connections of a session from the server side
+func TestServerSessionInfoUpdate(t *testing.T) {
+	hookCalled := tmdwg.NewTimedWaitGroup(2, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Try to create a new session
+				err := conn.CreateSession(wwr.GenericSessionInfoParser(
+					map[string]interface{}{"status": "initial"},
+				))
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	clientOptions := wwrclt.Options{
+		DefaultRequestTimeout: 2 * time.Second,
+		SessionInfoParser:     wwr.GenericSessionInfoParser,
+	}
+	clientHooks := callbackPoweredClientHooks{
+		OnSessionInfoChanged: func(info wwr.SessionInfo) {
+			assert.Equal(t, "updated", info.Value("status"))
+			hookCalled.Progress(1)
+		},
+	}
+
+	// Initialize clients
+	clientA := newCallbackPoweredClient(
+		server.Addr().String(),
+		clientOptions,
+		clientHooks,
+	)
+	defer clientA.connection.Close()
+
+	clientB := newCallbackPoweredClient(
+		server.Addr().String(),
+		clientOptions,
+		clientHooks,
+	)
+	defer clientB.connection.Close()
+
+	require.NoError(t, clientA.connection.Connect())
+	require.NoError(t, clientB.connection.Connect())
+
+	// Create a session on the first client
+	_, err := clientA.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	require.NoError(t, err)
+	sessionKey := clientA.connection.Session().Key
+
+	// Restore the same session on the second client
+	require.NoError(t,
+		clientB.connection.RestoreSession([]byte(sessionKey)),
+	)
+	require.Equal(t, "initial", clientB.connection.SessionInfo("status"))
+
+	// Update the session info on all connections of the session
+	affectedConns, updateErrors, err := server.UpdateSessionInfo(
+		sessionKey,
+		wwr.GenericSessionInfoParser(
+			map[string]interface{}{"status": "updated"},
+		),
+	)
+	require.NoError(t, err)
+	require.Len(t, affectedConns, 2)
+	require.Len(t, updateErrors, 2)
+	for _, updateErr := range updateErrors {
+		require.NoError(t, updateErr)
+	}
+
+	// Verify the server-side session info
+	for _, conn := range affectedConns {
+		require.Equal(t, "updated", conn.SessionInfo("status"))
+	}
+
+	// Verify the client-side session info
+	require.NoError(t, hookCalled.Wait(), "Hook not called")
+	require.Equal(t, "updated", clientA.connection.SessionInfo("status"))
+	require.Equal(t, "updated", clientB.connection.SessionInfo("status"))
+
+	// Verify updating an inexistent session affects no connections
+	affectedConns, updateErrors, err = server.UpdateSessionInfo(
+		"inexistent",
+		nil,
+	)
+	require.NoError(t, err)
+	require.Nil(t, affectedConns)
+	require.Nil(t, updateErrors)
+}
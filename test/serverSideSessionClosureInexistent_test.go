@@ -57,7 +57,7 @@ func TestServerSideSessionClosureInexistent(t *testing.T) {
 				Autoconnect:           wwr.Disabled,
 			},
 			callbackPoweredClientHooks{
-				OnSessionClosed: func() {
+				OnSessionClosed: func(_ string) {
 					onSessionClosedHooksExecuted.Progress(1)
 				},
 			},
@@ -56,7 +56,9 @@ func TestServerSideSessionClosure(t *testing.T) {
 				Autoconnect:           wwr.Disabled,
 			},
 			callbackPoweredClientHooks{
-				OnSessionClosed: func() {
+				OnSessionClosed: func(reason string) {
+					// Expect no reason when none was provided
+					assert.Empty(t, reason)
 					onSessionClosedHooksExecuted.Progress(1)
 				},
 			},
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestServerSignalWhere tests signaling the connections of sessions
+// matching a predicate expecting the connections of non-matching sessions
+// to not receive the signal
+func TestServerSignalWhere(t *testing.T) {
+	premiumSignaled := tmdwg.NewTimedWaitGroup(2, 1*time.Second)
+	var freeSignaled int32
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				// Create a session for the plan named by the request
+				err := conn.CreateSession(wwr.GenericSessionInfoParser(
+					map[string]interface{}{"plan": msg.Name()},
+				))
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	newClient := func(
+		plan string,
+		onSignal func(wwr.Message),
+	) *callbackPoweredClient {
+		client := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+			},
+			callbackPoweredClientHooks{
+				OnSignal: onSignal,
+			},
+		)
+		require.NoError(t, client.connection.Connect())
+		_, err := client.connection.Request(context.Background(), plan, nil)
+		require.NoError(t, err)
+		return client
+	}
+
+	// Initialize 2 premium clients and 1 free client on separate sessions
+	for i := 0; i < 2; i++ {
+		premium := newClient("premium", func(msg wwr.Message) {
+			assert.Equal(t, "offer", msg.Name())
+			premiumSignaled.Progress(1)
+		})
+		defer premium.connection.Close()
+	}
+	free := newClient("free", func(_ wwr.Message) {
+		atomic.AddInt32(&freeSignaled, 1)
+	})
+	defer free.connection.Close()
+	require.Equal(t, 3, server.ActiveSessionsNum())
+
+	// Signal the premium sessions only
+	results, err := server.SignalWhere(
+		func(session *wwr.Session) bool {
+			return session.Info.Value("plan") == "premium"
+		},
+		"offer",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("sample")),
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		require.NoError(t, result.Err)
+	}
+
+	require.NoError(t, premiumSignaled.Wait(), "Premium clients not signaled")
+
+	// Give a misdirected signal the chance to arrive
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int32(0), atomic.LoadInt32(&freeSignaled))
+}
@@ -25,12 +25,13 @@ func TestServerSignal(t *testing.T) {
 	server := setupServer(
 		t,
 		&serverImpl{
-			onClientConnected: func(conn wwr.Connection) {
+			onClientConnected: func(conn wwr.Connection) error {
 				// Send signal
 				assert.NoError(t, conn.Signal(
 					"",
 					expectedSignalPayload,
 				))
+				return nil
 			},
 		},
 		wwr.ServerOptions{},
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestServerStats tests whether the aggregated server stats
+// match the individual accessors
+func TestServerStats(t *testing.T) {
+	connected := tmdwg.NewTimedWaitGroup(2, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(_ wwr.Connection) error {
+				connected.Progress(1)
+				return nil
+			},
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Try to create a new session
+				err := conn.CreateSession(nil)
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize an authenticated and an anonymous client
+	authenticated := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer authenticated.connection.Close()
+
+	anonymous := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer anonymous.connection.Close()
+
+	require.NoError(t, authenticated.connection.Connect())
+	require.NoError(t, anonymous.connection.Connect())
+	require.NoError(t, connected.Wait(), "Clients not connected")
+
+	_, err := authenticated.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	require.NoError(t, err)
+
+	stats := server.Stats()
+	require.Equal(t, server.ActiveSessionsNum(), stats.ActiveSessions)
+	require.Equal(t, 1, stats.ActiveSessions)
+	require.Equal(t, len(server.Connections()), stats.Connections)
+	require.Equal(t, 2, stats.Connections)
+	require.Equal(t, 1, stats.AuthenticatedConnections)
+	require.Equal(t, 1, stats.AnonymousConnections)
+	require.False(t, stats.ShuttingDown)
+}
@@ -0,0 +1,134 @@
+package test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// generateSelfSignedCert generates a PEM encoded self-signed
+// certificate and private key valid for the loopback address
+func generateSelfSignedCert(t *testing.T) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"webwire"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(
+		rand.Reader,
+		&template,
+		&template,
+		&key.PublicKey,
+		key,
+	)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM
+}
+
+// dialTLS establishes a raw websocket connection to the given TLS server
+// trusting the given certificate and reads the handshake message
+func dialTLS(t *testing.T, server wwr.Server, certPEM []byte) {
+	require.Equal(t, "wss", server.Scheme())
+
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(certPEM))
+
+	dialer := websocket.Dialer{
+		TLSClientConfig: &tls.Config{RootCAs: roots},
+	}
+	endpointURL := url.URL{
+		Scheme: server.Scheme(),
+		Host:   server.Addr().String(),
+		Path:   "/",
+	}
+	conn, _, err := dialer.Dial(endpointURL.String(), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Expect the server to send the handshake message
+	msgType, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, websocket.BinaryMessage, msgType)
+	require.NotEmpty(t, msg)
+}
+
+// TestServerTLSConfig tests whether the built-in HTTP server serves
+// websocket connections over TLS given a TLS configuration
+func TestServerTLSConfig(t *testing.T) {
+	certPEM, keyPEM := generateSelfSignedCert(t)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	// Initialize webwire server
+	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+	})
+
+	dialTLS(t, server, certPEM)
+}
+
+// TestServerTLSCertFiles tests whether the built-in HTTP server serves
+// websocket connections over TLS given a certificate and key file pair
+func TestServerTLSCertFiles(t *testing.T) {
+	certPEM, keyPEM := generateSelfSignedCert(t)
+
+	dir, err := ioutil.TempDir("", "webwire-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, certPEM, 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, keyPEM, 0600))
+
+	// Initialize webwire server
+	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{
+		CertFile: certFile,
+		KeyFile:  keyFile,
+	})
+
+	dialTLS(t, server, certPEM)
+}
+
+// TestServerTLSMissingKeyFile tests whether creating a server
+// with a certificate file but no key file fails
+func TestServerTLSMissingKeyFile(t *testing.T) {
+	server, err := wwr.NewServer(&serverImpl{}, wwr.ServerOptions{
+		Address:  "127.0.0.1:0",
+		CertFile: "cert.pem",
+	})
+	require.Error(t, err)
+	require.Nil(t, server)
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSessionConnectionDetails tests the connection details
+// of a session with multiple connections
+func TestSessionConnectionDetails(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Try to create a new session
+				err := conn.CreateSession(nil)
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize clients representing two devices
+	clientA := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer clientA.connection.Close()
+
+	clientB := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer clientB.connection.Close()
+
+	require.NoError(t, clientA.connection.Connect())
+	require.NoError(t, clientB.connection.Connect())
+
+	// Create the session on the first device
+	_, err := clientA.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	require.NoError(t, err)
+	sessionKey := clientA.connection.Session().Key
+
+	// Restore the session on the second device
+	require.NoError(t,
+		clientB.connection.RestoreSession([]byte(sessionKey)),
+	)
+
+	// Verify the details of both connections
+	details := server.SessionConnectionDetails(sessionKey)
+	require.Len(t, details, 2)
+	require.NotEqual(t,
+		details[0].RemoteAddr.String(),
+		details[1].RemoteAddr.String(),
+	)
+	for _, detail := range details {
+		info := detail.Connection.Info()
+		require.Equal(t, info.RemoteAddr, detail.RemoteAddr)
+		require.Equal(t, info.ConnectionTime, detail.ConnectionTime)
+		require.Equal(t, "Go-http-client/1.1", detail.UserAgent)
+		require.WithinDuration(t,
+			time.Now(),
+			detail.ConnectionTime,
+			2*time.Second,
+		)
+	}
+
+	// Expect no details for inexistent sessions
+	require.Nil(t, server.SessionConnectionDetails("inexistent"))
+}
@@ -0,0 +1,98 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSessionConnectionsSignal tests whether request handlers can signal
+// the other connections of the session through conn.SessionConnections
+func TestSessionConnectionsSignal(t *testing.T) {
+	signaled := make(chan wwr.Message, 2)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if msg.Name() == "login" {
+					return nil, conn.CreateSession(nil)
+				}
+
+				// Notify the other devices of the user
+				for _, other := range conn.SessionConnections() {
+					assert.True(t, conn != other)
+					assert.NoError(t, other.Signal("notify", msg.Payload()))
+				}
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	newClient := func() *callbackPoweredClient {
+		clt := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+				Autoconnect:           wwr.Disabled,
+			},
+			callbackPoweredClientHooks{
+				OnSignal: func(msg wwr.Message) {
+					signaled <- msg
+				},
+			},
+		)
+		require.NoError(t, clt.connection.Connect())
+		return clt
+	}
+
+	// Create a session on the first client
+	// and restore it on the second one
+	clientA := newClient()
+	defer clientA.connection.Close()
+	_, err := clientA.connection.Request(context.Background(), "login", nil)
+	require.NoError(t, err)
+
+	clientB := newClient()
+	defer clientB.connection.Close()
+	require.NoError(t, clientB.connection.RestoreSession(
+		[]byte(clientA.connection.Session().Key),
+	))
+
+	// Expect only the second client to be signaled
+	payload := wwr.NewPayload(wwr.EncodingBinary, []byte("new message"))
+	_, err = clientA.connection.Request(context.Background(), "send", payload)
+	require.NoError(t, err)
+
+	select {
+	case msg := <-signaled:
+		require.Equal(t, "notify", msg.Name())
+		require.Equal(t, payload.Data(), msg.Payload().Data())
+	case <-time.After(1 * time.Second):
+		t.Fatal("Signal not received")
+	}
+
+	// Expect anonymous connections to have no session connections
+	anonymous := newClient()
+	defer anonymous.connection.Close()
+	_, err = anonymous.connection.Request(context.Background(), "send", payload)
+	require.NoError(t, err)
+
+	select {
+	case <-signaled:
+		t.Fatal("Unexpected signal received")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
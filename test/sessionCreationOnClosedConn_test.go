@@ -19,11 +19,12 @@ func TestSessionCreationOnClosedConn(t *testing.T) {
 	server := setupServer(
 		t,
 		&serverImpl{
-			onClientConnected: func(conn wwr.Connection) {
+			onClientConnected: func(conn wwr.Connection) error {
 				conn.Close()
 				err := conn.CreateSession(nil)
 				assert.Error(t, err)
 				assert.IsType(t, wwr.DisconnectedErr{}, err)
+				return nil
 			},
 			onClientDisconnected: func(conn wwr.Connection) {
 				err := conn.CreateSession(nil)
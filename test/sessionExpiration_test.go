@@ -0,0 +1,152 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// setupExpiringSessionServer sets up a server creating sessions
+// on request which expire after the given TTL
+// persisting them using the given session manager
+func setupExpiringSessionServer(
+	t *testing.T,
+	ttl time.Duration,
+	sliding wwr.OptionValue,
+	sessionManager wwr.SessionManager,
+) wwr.Server {
+	return setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				err := conn.CreateSession(nil)
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{
+			SessionTTL:        ttl,
+			SlidingSessionTTL: sliding,
+			SessionManager:    sessionManager,
+		},
+	)
+}
+
+// createExpiringSession creates a session on a new client
+// and returns the session key after closing the client
+func createExpiringSession(t *testing.T, server wwr.Server) *wwr.Session {
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	require.NoError(t, client.connection.Connect())
+	_, err := client.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("auth")),
+	)
+	require.NoError(t, err)
+	session := client.connection.Session()
+	client.connection.Close()
+	return session
+}
+
+// restoreExpiringSession tries to restore the session identified
+// by the given key on a new client
+func restoreExpiringSession(
+	t *testing.T,
+	server wwr.Server,
+	key string,
+) (*wwr.Session, error) {
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+	err := client.connection.RestoreSession([]byte(key))
+	return client.connection.Session(), err
+}
+
+// TestSessionExpiration tests whether sessions expire after the configured
+// session TTL and are no longer restorable
+func TestSessionExpiration(t *testing.T) {
+	ttl := 300 * time.Millisecond
+	server := setupExpiringSessionServer(t, ttl, wwr.OptionUnset, nil)
+
+	// Expect the expiration to be exposed to the client
+	created := createExpiringSession(t, server)
+	require.False(t, created.Expiration.IsZero())
+	require.Equal(t,
+		created.Creation.Add(ttl).UnixNano(),
+		created.Expiration.UnixNano(),
+	)
+
+	// Expect the session to be restorable before it expires
+	restored, err := restoreExpiringSession(t, server, created.Key)
+	require.NoError(t, err)
+	require.Equal(t,
+		created.Expiration.UnixNano(),
+		restored.Expiration.UnixNano(),
+	)
+
+	// Expect the session to not be restorable after it expired
+	time.Sleep(ttl)
+	_, err = restoreExpiringSession(t, server, created.Key)
+	require.Error(t, err)
+	require.IsType(t, wwr.SessNotFoundErr{}, err)
+}
+
+// TestSessionSlidingExpiration tests whether sessions with a sliding TTL
+// remain restorable as long as they're looked up within the TTL
+func TestSessionSlidingExpiration(t *testing.T) {
+	ttl := 500 * time.Millisecond
+	sessionManager := wwr.NewInMemorySessionManager()
+	server := setupExpiringSessionServer(t, ttl, wwr.Enabled, sessionManager)
+
+	// Expect sliding sessions to initially expire after the TTL
+	created := createExpiringSession(t, server)
+	require.Equal(t,
+		created.Creation.Add(ttl).UnixNano(),
+		created.Expiration.UnixNano(),
+	)
+
+	// Expect each restoration to extend the expiration of the session
+	// and the session manager to persist the extended expiration
+	lastExpiration := created.Expiration
+	for i := 0; i < 3; i++ {
+		time.Sleep(ttl / 2)
+		restored, err := restoreExpiringSession(t, server, created.Key)
+		require.NoError(t, err)
+		require.True(t, restored.Expiration.After(lastExpiration))
+		lastExpiration = restored.Expiration
+	}
+
+	// Expect the session to expire if it's not looked up within the TTL
+	time.Sleep(ttl + 100*time.Millisecond)
+	_, err := restoreExpiringSession(t, server, created.Key)
+	require.Error(t, err)
+	require.IsType(t, wwr.SessNotFoundErr{}, err)
+
+	// Expect the session manager to have deleted the expired session
+	// on its own based on the persisted expiration
+	result, err := sessionManager.OnSessionLookup(created.Key)
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
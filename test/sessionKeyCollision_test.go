@@ -0,0 +1,157 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSessionKeyCollision tests whether colliding session keys
+// are regenerated and whether session creation fails if the generator
+// can't produce a unique key
+func TestSessionKeyCollision(t *testing.T) {
+	var lock sync.Mutex
+	keys := []string{"keyA", "keyA", "keyB"}
+	createErrs := make(chan error, 3)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				err := conn.CreateSession(nil)
+				createErrs <- err
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{
+			SessionKeyGenerator: &sessionKeyGen{
+				generate: func() string {
+					lock.Lock()
+					defer lock.Unlock()
+					// Repeat the last key once all keys are used
+					key := keys[0]
+					if len(keys) > 1 {
+						keys = keys[1:]
+					}
+					return key
+				},
+			},
+		},
+	)
+
+	newClient := func() *callbackPoweredClient {
+		clt := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+				Autoconnect:           wwr.Disabled,
+			},
+			callbackPoweredClientHooks{},
+		)
+		require.NoError(t, clt.connection.Connect())
+		return clt
+	}
+
+	// Expect the first client to get the first key
+	clientA := newClient()
+	defer clientA.connection.Close()
+	_, err := clientA.connection.Request(context.Background(), "login", nil)
+	require.NoError(t, err)
+	require.NoError(t, <-createErrs)
+	require.Equal(t, "keyA", clientA.connection.Session().Key)
+
+	// Expect the colliding key to be regenerated for the second client
+	clientB := newClient()
+	defer clientB.connection.Close()
+	_, err = clientB.connection.Request(context.Background(), "login", nil)
+	require.NoError(t, err)
+	require.NoError(t, <-createErrs)
+	require.Equal(t, "keyB", clientB.connection.Session().Key)
+
+	// Expect the session creation to fail when the generator
+	// keeps returning colliding keys
+	clientC := newClient()
+	defer clientC.connection.Close()
+	_, err = clientC.connection.Request(context.Background(), "login", nil)
+	require.Error(t, err)
+	require.IsType(t, wwr.SessionKeyCollisionErr{}, <-createErrs)
+	require.Nil(t, clientC.connection.Session())
+}
+
+// TestSessionKeyCollisionConcurrent tests whether concurrently created
+// sessions never share a key even if the generator keeps returning
+// the same key
+func TestSessionKeyCollisionConcurrent(t *testing.T) {
+	clientsNum := 8
+	start := make(chan struct{})
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				<-start
+				return nil, conn.CreateSession(nil)
+			},
+		},
+		wwr.ServerOptions{
+			SessionKeyGenerator: &sessionKeyGen{
+				generate: func() string { return "samekey" },
+			},
+		},
+	)
+
+	// Concurrently create sessions on all clients
+	results := make(chan error, clientsNum)
+	creation := sync.WaitGroup{}
+	creation.Add(clientsNum)
+	for i := 0; i < clientsNum; i++ {
+		clt := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+				Autoconnect:           wwr.Disabled,
+			},
+			callbackPoweredClientHooks{},
+		)
+		defer clt.connection.Close()
+		require.NoError(t, clt.connection.Connect())
+		go func() {
+			defer creation.Done()
+			_, err := clt.connection.Request(
+				context.Background(),
+				"login",
+				nil,
+			)
+			results <- err
+		}()
+	}
+	close(start)
+	creation.Wait()
+	close(results)
+
+	// Expect only a single session to be created
+	succeeded := 0
+	for err := range results {
+		if err == nil {
+			succeeded++
+		}
+	}
+	require.Equal(t, 1, succeeded)
+	require.Equal(t, 1, server.SessionConnectionsNum("samekey"))
+}
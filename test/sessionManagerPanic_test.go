@@ -0,0 +1,83 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSessionManagerPanic tests recovering from panics
+// in the session manager hooks
+func TestSessionManagerPanic(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Expect the session creation to succeed
+				// even though the session manager hook panics
+				err := conn.CreateSession(nil)
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager: &callbackPoweredSessionManager{
+				SessionCreated: func(_ wwr.Connection) error {
+					panic("expected OnSessionCreated panic")
+				},
+				SessionLookup: func(_ string) (wwr.SessionLookupResult, error) {
+					panic("expected OnSessionLookup panic")
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Create a session
+	_, err := client.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	require.NoError(t, err)
+
+	// Close the session to be able to try restoring one
+	require.NoError(t, client.connection.CloseSession())
+
+	// Expect the session restoration to fail with an internal error
+	err = client.connection.RestoreSession([]byte("somekey"))
+	require.Error(t, err)
+	require.IsType(t, wwr.ReqInternalErr{}, err)
+
+	// Ensure the connection survived the panics
+	require.Equal(t, wwrclt.Connected, client.connection.Status())
+	_, err = client.connection.Request(
+		context.Background(),
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("test")),
+	)
+	require.NoError(t, err)
+}
@@ -0,0 +1,223 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSessionRegistryChurn tests whether the session registry remains
+// consistent while many clients concurrently connect, restore, switch,
+// close sessions and disconnect on overlapping session keys
+// and the registry is concurrently inspected.
+// Meant to be run with the race detector enabled
+func TestSessionRegistryChurn(t *testing.T) {
+	sessionsNum := 4
+	churnersNum := 16
+	iterations := 10
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, conn.CreateSession(nil)
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	newClient := func() *callbackPoweredClient {
+		return newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				Autoconnect:           wwr.Disabled,
+				DefaultRequestTimeout: 2 * time.Second,
+			},
+			callbackPoweredClientHooks{},
+		)
+	}
+
+	// Create the sessions which remain connected throughout the test
+	keys := make([]string, sessionsNum)
+	for i := range keys {
+		owner := newClient()
+		defer owner.connection.Close()
+		require.NoError(t, owner.connection.Connect())
+		_, err := owner.connection.Request(
+			context.Background(),
+			"login",
+			wwr.NewPayload(wwr.EncodingBinary, []byte("auth")),
+		)
+		require.NoError(t, err)
+		keys[i] = owner.connection.Session().Key
+	}
+
+	// Concurrently inspect the registry until the churn is over
+	stopInspection := make(chan struct{})
+	inspectionDone := make(chan struct{})
+	go func() {
+		defer close(inspectionDone)
+		for {
+			select {
+			case <-stopInspection:
+				return
+			default:
+			}
+			for _, key := range keys {
+				server.SessionConnectionsNum(key)
+				for _, conn := range server.SessionConnections(key) {
+					conn.SessionKey()
+				}
+			}
+			server.ActiveSessionsNum()
+		}
+	}()
+
+	// Rapidly connect, restore, switch sessions and disconnect
+	churn := sync.WaitGroup{}
+	churn.Add(churnersNum)
+	for i := 0; i < churnersNum; i++ {
+		go func(i int) {
+			defer churn.Done()
+			client := newClient()
+			defer client.connection.Close()
+			for j := 0; j < iterations; j++ {
+				first := keys[(i+j)%sessionsNum]
+				second := keys[(i+j+1)%sessionsNum]
+
+				// Reconnecting restores the previous session
+				if !assert.NoError(t, client.connection.Connect()) {
+					return
+				}
+				assert.NoError(t, client.connection.CloseSession())
+				assert.NoError(t, client.connection.RestoreSession(
+					[]byte(first),
+				))
+				assert.NoError(t, client.connection.CloseSession())
+				assert.NoError(t, client.connection.RestoreSession(
+					[]byte(second),
+				))
+				client.connection.Close()
+			}
+		}(i)
+	}
+	churn.Wait()
+	close(stopInspection)
+	<-inspectionDone
+
+	// Expect only the connections of the session owners to remain
+	// once all churned connections are closed on the server
+	deadline := time.Now().Add(2 * time.Second)
+	for _, key := range keys {
+		for server.SessionConnectionsNum(key) != 1 &&
+			time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		require.Equal(t, 1, server.SessionConnectionsNum(key))
+	}
+	require.Equal(t, sessionsNum, server.ActiveSessionsNum())
+}
+
+// TestSessionRegistryChurnRestoreAtLimit tests whether overlapping
+// session restorations on a session that's about to reach its connection
+// limit are accepted only until the limit is reached while all others
+// are rejected without affecting the server
+func TestSessionRegistryChurnRestoreAtLimit(t *testing.T) {
+	restorersNum := 16
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, conn.CreateSession(nil)
+			},
+		},
+		wwr.ServerOptions{
+			MaxSessionConnections: 2,
+		},
+	)
+
+	newClient := func() *callbackPoweredClient {
+		return newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				Autoconnect:           wwr.Disabled,
+				DefaultRequestTimeout: 2 * time.Second,
+			},
+			callbackPoweredClientHooks{},
+		)
+	}
+
+	// Create the session occupying the first slot
+	owner := newClient()
+	defer owner.connection.Close()
+	require.NoError(t, owner.connection.Connect())
+	_, err := owner.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("auth")),
+	)
+	require.NoError(t, err)
+	key := owner.connection.Session().Key
+
+	// Connect all restorers before restoring to maximize the overlap
+	restorers := make([]*callbackPoweredClient, restorersNum)
+	for i := range restorers {
+		restorers[i] = newClient()
+		defer restorers[i].connection.Close()
+		require.NoError(t, restorers[i].connection.Connect())
+	}
+
+	// Concurrently restore the session competing for the last slot
+	start := make(chan struct{})
+	results := make(chan error, restorersNum)
+	restoration := sync.WaitGroup{}
+	restoration.Add(restorersNum)
+	for _, restorer := range restorers {
+		go func(restorer *callbackPoweredClient) {
+			defer restoration.Done()
+			<-start
+			results <- restorer.connection.RestoreSession([]byte(key))
+		}(restorer)
+	}
+	close(start)
+	restoration.Wait()
+	close(results)
+
+	// Expect exactly one restoration to succeed
+	succeeded := 0
+	for err := range results {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.IsType(t, wwr.MaxSessConnsReachedErr{}, err)
+	}
+	require.Equal(t, 1, succeeded)
+	require.Equal(t, 2, server.SessionConnectionsNum(key))
+
+	// Expect the server to remain operational
+	_, err = owner.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("auth")),
+	)
+	require.NoError(t, err)
+}
@@ -0,0 +1,73 @@
+package test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+)
+
+// TestSessionRegistryCleanupAbruptDisconnect tests whether a session
+// connection is deregistered from the session registry
+// when the connection is abruptly killed without a close frame
+func TestSessionRegistryCleanupAbruptDisconnect(t *testing.T) {
+	sessionCreated := tmdwg.NewTimedWaitGroup(1, 2*time.Second)
+	disconnected := tmdwg.NewTimedWaitGroup(1, 2*time.Second)
+	connections := make(chan wwr.Connection, 1)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) error {
+				assert.NoError(t, conn.CreateSession(nil))
+				connections <- conn
+				sessionCreated.Progress(1)
+				return nil
+			},
+			onClientDisconnected: func(_ wwr.Connection) {
+				disconnected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Establish a regular websocket connection
+	endpointURL := url.URL{
+		Scheme: "ws",
+		Host:   server.Addr().String(),
+		Path:   "/",
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(endpointURL.String(), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, handshake, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, message.MsgHandshake, handshake[0])
+
+	require.NoError(t, sessionCreated.Wait(), "Session not created")
+	connection := <-connections
+	sessionKey := connection.SessionKey()
+	require.Equal(t, 1, server.SessionConnectionsNum(sessionKey))
+	require.Equal(t, 1, server.ActiveSessionsNum())
+
+	// Kill the connection without sending a close frame
+	require.NoError(t, conn.UnderlyingConn().Close())
+	require.NoError(t, disconnected.Wait(), "Client not disconnected")
+
+	require.Equal(t, -1, server.SessionConnectionsNum(sessionKey))
+	require.Equal(t, 0, server.ActiveSessionsNum())
+
+	// Ensure no session can be registered for the closed connection
+	require.IsType(t, wwr.DisconnectedErr{}, connection.CreateSession(nil))
+	require.Equal(t, 0, server.ActiveSessionsNum())
+}
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSessionReplacement tests creating a session twice on one connection
+// expecting the first session to be closed and destroyed
+// once the second one is created
+func TestSessionReplacement(t *testing.T) {
+	sessionClosed := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	sessionDestroyed := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	sessionsCreated := tmdwg.NewTimedWaitGroup(2, 1*time.Second)
+	lock := sync.Mutex{}
+	createdKeys := make([]string, 0, 2)
+	var destroyedKey string
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				if err := conn.CreateSession(nil); err != nil {
+					return nil, err
+				}
+				lock.Lock()
+				createdKeys = append(createdKeys, conn.SessionKey())
+				lock.Unlock()
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager: &callbackPoweredSessionManager{
+				SessionClosed: func(sessionKey string) error {
+					lock.Lock()
+					destroyedKey = sessionKey
+					lock.Unlock()
+					sessionDestroyed.Progress(1)
+					return nil
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{
+			OnSessionCreated: func(_ *wwr.Session) {
+				sessionsCreated.Progress(1)
+			},
+			OnSessionClosed: func(_ string) {
+				sessionClosed.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Create a session twice
+	for i := 0; i < 2; i++ {
+		_, err := client.connection.Request(
+			context.Background(),
+			"login",
+			wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+		)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, sessionsCreated.Wait(), "Sessions not created")
+	require.NoError(t, sessionClosed.Wait(), "First session not closed")
+	require.NoError(t,
+		sessionDestroyed.Wait(),
+		"First session not destroyed",
+	)
+
+	// Expect only the second session to remain active
+	lock.Lock()
+	defer lock.Unlock()
+	require.Len(t, createdKeys, 2)
+	require.NotEqual(t, createdKeys[0], createdKeys[1])
+	require.Equal(t, createdKeys[0], destroyedKey)
+	require.Equal(t, createdKeys[1], client.connection.Session().Key)
+	require.Equal(t, 1, server.ActiveSessionsNum())
+	require.Equal(t, -1, server.SessionConnectionsNum(createdKeys[0]))
+	require.Equal(t, 1, server.SessionConnectionsNum(createdKeys[1]))
+}
+
+// TestSessionReplacementFailure tests whether the active session
+// of a connection remains untouched if the creation
+// of the replacing session fails
+func TestSessionReplacementFailure(t *testing.T) {
+	generated := 0
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, conn.CreateSession(nil)
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager: &callbackPoweredSessionManager{
+				SessionClosed: func(sessionKey string) error {
+					t.Errorf("Unexpected destruction of session %s", sessionKey)
+					return nil
+				},
+			},
+			SessionKeyGenerator: &sessionKeyGen{
+				generate: func() string {
+					// Generate an invalid key for the replacing session
+					generated++
+					if generated > 1 {
+						return ""
+					}
+					return "validsessionkey"
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{
+			OnSessionClosed: func(_ string) {
+				t.Error("Unexpected session closure notification")
+			},
+		},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Create the session
+	_, err := client.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	require.NoError(t, err)
+
+	// Try to replace the session
+	_, err = client.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	assert.Error(t, err)
+
+	// Expect the first session to remain active
+	require.Equal(t, "validsessionkey", client.connection.Session().Key)
+	require.Equal(t, 1, server.SessionConnectionsNum("validsessionkey"))
+}
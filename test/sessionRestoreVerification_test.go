@@ -0,0 +1,99 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSessionRestoreVerification tests session restoration
+// with an additional verification proof
+func TestSessionRestoreVerification(t *testing.T) {
+	validKey := "validsessionkey"
+	validProof := []byte("validproof")
+
+	// Initialize server
+	server := setupServer(
+		t,
+		&serverImpl{},
+		wwr.ServerOptions{
+			SessionManager: &callbackPoweredSessionManager{
+				SessionLookup: func(key string) (
+					wwr.SessionLookupResult,
+					error,
+				) {
+					if key != validKey {
+						return nil, nil
+					}
+					return wwr.NewSessionLookupResult(
+						time.Now(), // Creation
+						time.Now(), // LastLookup
+						nil,        // Info
+					), nil
+				},
+			},
+			SessionRestoreVerifier: &callbackPoweredSessionRestoreVerifier{
+				Verify: func(
+					_ wwr.Connection,
+					key string,
+					session wwr.SessionLookupResult,
+					proof []byte,
+				) error {
+					assert.Equal(t, validKey, key)
+					assert.NotNil(t, session)
+					if !bytes.Equal(validProof, proof) {
+						return wwr.ReqErr{
+							Code:    "INVALID_PROOF",
+							Message: "invalid session restoration proof",
+						}
+					}
+					return nil
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Expect the restoration to be rejected when no proof is provided
+	err := client.connection.RestoreSession([]byte(validKey))
+	require.Error(t, err)
+	require.IsType(t, wwr.ReqErr{}, err)
+	require.Equal(t, "INVALID_PROOF", err.(wwr.ReqErr).Code)
+	require.Nil(t, client.connection.Session())
+
+	// Expect the restoration to be rejected when a wrong proof is provided
+	// even though the session key is valid
+	err = client.connection.RestoreSessionWithProof(
+		[]byte(validKey),
+		[]byte("wrongproof"),
+	)
+	require.Error(t, err)
+	require.IsType(t, wwr.ReqErr{}, err)
+	require.Equal(t, "INVALID_PROOF", err.(wwr.ReqErr).Code)
+	require.Nil(t, client.connection.Session())
+
+	// Expect the restoration to succeed when the valid proof is provided
+	require.NoError(t, client.connection.RestoreSessionWithProof(
+		[]byte(validKey),
+		validProof,
+	))
+	require.Equal(t, validKey, client.connection.Session().Key)
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSessionUpdate tests updating the session of a connection
+// synchronizing the update to all connections of the session
+// and persisting it through the session manager
+func TestSessionUpdate(t *testing.T) {
+	hookCalled := tmdwg.NewTimedWaitGroup(2, 1*time.Second)
+	sessionManager := wwr.NewInMemorySessionManager()
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if msg.Name() == "promote" {
+					return nil, conn.UpdateSession(
+						wwr.GenericSessionInfoParser(
+							map[string]interface{}{"level": "admin"},
+						),
+					)
+				}
+				err := conn.CreateSession(wwr.GenericSessionInfoParser(
+					map[string]interface{}{"level": "user"},
+				))
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager: sessionManager,
+		},
+	)
+
+	clientOptions := wwrclt.Options{
+		DefaultRequestTimeout: 2 * time.Second,
+		SessionInfoParser:     wwr.GenericSessionInfoParser,
+	}
+	clientHooks := callbackPoweredClientHooks{
+		OnSessionInfoChanged: func(info wwr.SessionInfo) {
+			assert.Equal(t, "admin", info.Value("level"))
+			hookCalled.Progress(1)
+		},
+	}
+
+	// Initialize clients
+	clientA := newCallbackPoweredClient(
+		server.Addr().String(),
+		clientOptions,
+		clientHooks,
+	)
+	defer clientA.connection.Close()
+
+	clientB := newCallbackPoweredClient(
+		server.Addr().String(),
+		clientOptions,
+		clientHooks,
+	)
+	defer clientB.connection.Close()
+
+	require.NoError(t, clientA.connection.Connect())
+	require.NoError(t, clientB.connection.Connect())
+
+	// Create a session on the first client
+	_, err := clientA.connection.Request(
+		context.Background(),
+		"login",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
+	)
+	require.NoError(t, err)
+	session := clientA.connection.Session()
+
+	// Restore the same session on the second client
+	require.NoError(t,
+		clientB.connection.RestoreSession([]byte(session.Key)),
+	)
+
+	// Update the session
+	_, err = clientA.connection.Request(
+		context.Background(),
+		"promote",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("promote")),
+	)
+	require.NoError(t, err)
+
+	// Verify the update was synchronized to all clients
+	require.NoError(t, hookCalled.Wait(), "Hook not called")
+	for _, client := range []*callbackPoweredClient{clientA, clientB} {
+		require.Equal(t, "admin", client.connection.SessionInfo("level"))
+		require.True(t, client.connection.Session().LastLookup.After(
+			session.LastLookup,
+		))
+	}
+
+	// Verify the update was persisted
+	result, err := sessionManager.OnSessionLookup(session.Key)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.Equal(t, "admin", result.Info()["level"])
+}
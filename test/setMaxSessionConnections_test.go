@@ -0,0 +1,79 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSetMaxSessionConnections tests whether raising the connection limit
+// of a session lets a previously rejected connection restore the session
+func TestSetMaxSessionConnections(t *testing.T) {
+	// Initialize server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, conn.CreateSession(nil)
+			},
+		},
+		wwr.ServerOptions{
+			MaxSessionConnections: 1,
+		},
+	)
+
+	newClient := func() *callbackPoweredClient {
+		client := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+			},
+			callbackPoweredClientHooks{},
+		)
+		require.NoError(t, client.connection.Connect())
+		return client
+	}
+
+	// Connect the first client and create the session
+	owner := newClient()
+	defer owner.connection.Close()
+	_, err := owner.connection.Request(context.Background(), "login", nil)
+	require.NoError(t, err)
+	key := owner.connection.Session().Key
+	require.NotEmpty(t, key)
+
+	// Expect the second connection to be rejected
+	extra := newClient()
+	defer extra.connection.Close()
+	err = extra.connection.RestoreSession([]byte(key))
+	require.Error(t, err)
+	require.IsType(t, wwr.MaxSessConnsReachedErr{}, err)
+
+	// Expect overriding the limit of an inactive session to fail
+	require.Equal(
+		t,
+		wwr.SessNotFoundErr{},
+		server.SetMaxSessionConnections("inexistent", 2),
+	)
+
+	// Raise the limit and expect the second connection to be accepted
+	require.NoError(t, server.SetMaxSessionConnections(key, 2))
+	require.NoError(t, extra.connection.RestoreSession([]byte(key)))
+	require.Equal(t, 2, server.SessionConnectionsNum(key))
+
+	// Expect a third connection to be rejected by the raised limit
+	superfluous := newClient()
+	defer superfluous.connection.Close()
+	err = superfluous.connection.RestoreSession([]byte(key))
+	require.Error(t, err)
+	require.IsType(t, wwr.MaxSessConnsReachedErr{}, err)
+}
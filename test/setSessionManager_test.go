@@ -0,0 +1,70 @@
+package test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSetSessionManager tests replacing the session manager
+// of a running server
+func TestSetSessionManager(t *testing.T) {
+	var initialLookups int32
+	var replacementLookups int32
+
+	// Initialize server using a session manager that never finds any session
+	server := setupServer(
+		t,
+		&serverImpl{},
+		wwr.ServerOptions{
+			SessionManager: &callbackPoweredSessionManager{
+				SessionLookup: func(_ string) (wwr.SessionLookupResult, error) {
+					atomic.AddInt32(&initialLookups, 1)
+					return nil, nil
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Expect the initial session manager to not find the session
+	err := client.connection.RestoreSession([]byte("testkey"))
+	require.Error(t, err)
+	require.IsType(t, wwr.SessNotFoundErr{}, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&initialLookups))
+
+	// Replace the session manager by one that always finds the session
+	server.SetSessionManager(&callbackPoweredSessionManager{
+		SessionLookup: func(_ string) (wwr.SessionLookupResult, error) {
+			atomic.AddInt32(&replacementLookups, 1)
+			return wwr.NewSessionLookupResult(
+				time.Now(), // Creation
+				time.Now(), // LastLookup
+				nil,        // Info
+			), nil
+		},
+	})
+
+	// Expect the replacement session manager to find the session
+	require.NoError(t, client.connection.RestoreSession([]byte("testkey")))
+	require.Equal(t, int32(1), atomic.LoadInt32(&initialLookups))
+	require.Equal(t, int32(1), atomic.LoadInt32(&replacementLookups))
+	require.Equal(t, "testkey", client.connection.Session().Key)
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestShutdownOrdering tests that requests issued right after
+// the shutdown initiation are deterministically rejected
+// while in-flight requests are awaited before the server shuts down
+func TestShutdownOrdering(t *testing.T) {
+	inFlightStarted := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	releaseInFlight := make(chan struct{})
+	var lateHandled int32
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if msg.Name() != "inflight" {
+					atomic.StoreInt32(&lateHandled, 1)
+					return nil, nil
+				}
+				inFlightStarted.Progress(1)
+				<-releaseInFlight
+				return wwr.NewPayload(
+					wwr.EncodingBinary,
+					[]byte("finished"),
+				), nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	cltOpts := wwrclt.Options{
+		DefaultRequestTimeout: 2 * time.Second,
+		Autoconnect:           wwr.Disabled,
+	}
+	clientInFlight := newCallbackPoweredClient(
+		server.Addr().String(),
+		cltOpts,
+		callbackPoweredClientHooks{},
+	)
+	clientLate := newCallbackPoweredClient(
+		server.Addr().String(),
+		cltOpts,
+		callbackPoweredClientHooks{},
+	)
+	require.NoError(t, clientInFlight.connection.Connect())
+	require.NoError(t, clientLate.connection.Connect())
+
+	// Start the in-flight request
+	inFlightReplied := tmdwg.NewTimedWaitGroup(1, 2*time.Second)
+	go func() {
+		reply, err := clientInFlight.connection.Request(
+			context.Background(),
+			"inflight",
+			wwr.NewPayload(wwr.EncodingBinary, []byte("sample")),
+		)
+		assert.NoError(t, err)
+		if assert.NotNil(t, reply) {
+			assert.Equal(t, []byte("finished"), reply.Data())
+		}
+		inFlightReplied.Progress(1)
+	}()
+	require.NoError(t, inFlightStarted.Wait(), "In-flight request not handled")
+
+	// Initiate the shutdown
+	serverShutDown := tmdwg.NewTimedWaitGroup(1, 2*time.Second)
+	go func() {
+		assert.NoError(t, server.Shutdown())
+		serverShutDown.Progress(1)
+	}()
+
+	// Wait for the shutdown flag to be set
+	// which must happen before the in-flight request is awaited
+	deadline := time.Now().Add(1 * time.Second)
+	for !server.Stats().ShuttingDown {
+		require.True(t, time.Now().Before(deadline), "Shutdown not initiated")
+		time.Sleep(time.Millisecond)
+	}
+
+	// Issue the late request which must be rejected deterministically
+	_, err := clientLate.connection.Request(
+		context.Background(),
+		"late",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("sample")),
+	)
+	require.Error(t, err)
+	require.IsType(t, wwr.ReqSrvShutdownErr{}, err)
+	require.Equal(t, int32(0), atomic.LoadInt32(&lateHandled))
+
+	// The server must not be shut down while the in-flight request
+	// is still being processed
+	time.Sleep(50 * time.Millisecond)
+	require.False(t, serverShutDown.IsCompleted(), "Handler not awaited")
+
+	// Release the in-flight request and expect the server to shut down
+	close(releaseInFlight)
+	require.NoError(t, inFlightReplied.Wait(), "In-flight request failed")
+	require.NoError(t, serverShutDown.Wait(), "Server not shut down")
+}
@@ -0,0 +1,76 @@
+package test
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestShutdownPendingRequestsReport tests whether the server reports
+// the names of the requests still pending when the shutdown began
+func TestShutdownPendingRequestsReport(t *testing.T) {
+	requestReceived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	requestFinished := tmdwg.NewTimedWaitGroup(1, 2*time.Second)
+	warnLog := &syncLogWriter{}
+
+	// Initialize webwire server with a deliberately slow request handler
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				requestReceived.Progress(1)
+				time.Sleep(100 * time.Millisecond)
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			ReportPendingRequests: wwr.Enabled,
+			WarnLog:               log.New(warnLog, "WARN: ", 0),
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	go func() {
+		_, err := client.connection.Request(
+			context.Background(),
+			"slow_request",
+			wwr.NewPayload(wwr.EncodingBinary, []byte("testdata")),
+		)
+		assert.NoError(t, err)
+		requestFinished.Progress(1)
+	}()
+
+	// Shut the server down while the slow request is being processed
+	require.NoError(t, requestReceived.Wait(), "Request not received")
+	require.NoError(t, server.Shutdown())
+	require.NoError(t, requestFinished.Wait(), "Request not finished")
+
+	require.Contains(t,
+		warnLog.String(),
+		"Shutdown awaiting pending requests: slow_request",
+	)
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// shutdownProgress represents a report of the ShutdownProgress callback
+type shutdownProgress struct {
+	remainingOps int
+	elapsed      time.Duration
+}
+
+// TestShutdownProgress tests whether the shutdown progress is reported
+// periodically while awaiting the remaining operations
+// and a last time when the shutdown is completed
+func TestShutdownProgress(t *testing.T) {
+	handlerStarted := make(chan struct{})
+	finishHandler := make(chan struct{})
+
+	var lock sync.Mutex
+	var reports []shutdownProgress
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				close(handlerStarted)
+				<-finishHandler
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			ShutdownProgressInterval: 20 * time.Millisecond,
+			ShutdownProgress: func(remainingOps int, elapsed time.Duration) {
+				lock.Lock()
+				defer lock.Unlock()
+				reports = append(reports, shutdownProgress{
+					remainingOps: remainingOps,
+					elapsed:      elapsed,
+				})
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	go client.connection.Request(context.Background(), "slow", nil)
+	<-handlerStarted
+
+	// Let the shutdown await the pending request for a while
+	shutdownErr := make(chan error, 1)
+	go func() { shutdownErr <- server.Shutdown() }()
+	time.Sleep(200 * time.Millisecond)
+	close(finishHandler)
+	require.NoError(t, <-shutdownErr)
+
+	lock.Lock()
+	numReports := len(reports)
+	lock.Unlock()
+
+	// Expect the callback to stop firing after the shutdown
+	time.Sleep(100 * time.Millisecond)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Len(t, reports, numReports)
+	require.True(t, len(reports) > 2)
+
+	// Expect the pending request to be reported until the completion
+	for _, report := range reports[:len(reports)-1] {
+		require.Equal(t, 1, report.remainingOps)
+	}
+	last := reports[len(reports)-1]
+	require.Equal(t, 0, last.remainingOps)
+	require.True(t, last.elapsed >= 200*time.Millisecond)
+}
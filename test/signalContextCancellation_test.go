@@ -0,0 +1,73 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSignalContextCancellation tests the cancellation of the signal
+// handler context when the connection is closed during signal handling
+func TestSignalContextCancellation(t *testing.T) {
+	handlerStarted := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	handlerCancelled := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onSignal: func(
+				ctx context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) {
+				handlerStarted.Progress(1)
+				select {
+				case <-ctx.Done():
+					assert.Equal(t, context.Canceled, ctx.Err())
+					handlerCancelled.Progress(1)
+				case <-time.After(5 * time.Second):
+					t.Error("Signal handler context not cancelled")
+				}
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+
+	require.NoError(t, client.connection.Connect())
+
+	// Send signal and drop the connection during its handling
+	require.NoError(t, client.connection.Signal(
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("sample")),
+	))
+	require.NoError(t, handlerStarted.Wait(), "Signal handler not invoked")
+	client.connection.Close()
+	require.NoError(t, handlerCancelled.Wait(), "Context not cancelled")
+
+	// Ensure the signal handler is no longer considered a pending operation
+	// by awaiting the server shutdown which would otherwise block
+	serverShutDown := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	go func() {
+		assert.NoError(t, server.Shutdown())
+		serverShutDown.Progress(1)
+	}()
+	require.NoError(t, serverShutDown.Wait(), "Server shutdown blocked")
+}
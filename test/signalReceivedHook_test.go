@@ -0,0 +1,163 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSignalReceivedHook tests whether the signal received hook is invoked
+// for every signal before it's handled alongside the client connection
+func TestSignalReceivedHook(t *testing.T) {
+	signalsNum := 10
+	handled := tmdwg.NewTimedWaitGroup(signalsNum, 2*time.Second)
+	lock := sync.Mutex{}
+	audited := make(map[string]bool)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onSignal: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) {
+				// Expect the signal to be audited before it's handled
+				lock.Lock()
+				assert.True(t, audited[string(msg.Payload().Data())])
+				lock.Unlock()
+				handled.Progress(1)
+			},
+		},
+		wwr.ServerOptions{
+			Hooks: &callbackPoweredMessageHooks{
+				SignalReceived: func(conn wwr.Connection, msg wwr.Message) {
+					assert.Equal(t, "auditee", conn.ClientIdentifier())
+					assert.Equal(t, "audited", msg.Name())
+					lock.Lock()
+					audited[string(msg.Payload().Data())] = true
+					lock.Unlock()
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			ClientIdentifier: "auditee",
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	for i := 0; i < signalsNum; i++ {
+		require.NoError(t, client.connection.Signal(
+			"audited",
+			wwr.NewPayload(
+				wwr.EncodingBinary,
+				[]byte(fmt.Sprintf("signal_%d", i)),
+			),
+		))
+	}
+	require.NoError(t, handled.Wait(), "Signals not handled")
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Len(t, audited, signalsNum)
+}
+
+// TestSignalReceivedHookShutdown tests whether the signal received hook
+// is invoked for signals ignored due to the server shutting down
+func TestSignalReceivedHookShutdown(t *testing.T) {
+	release := make(chan struct{})
+	requestHandled := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	audited := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Keep the server from shutting down
+				requestHandled.Progress(1)
+				<-release
+				return nil, nil
+			},
+			onSignal: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) {
+				t.Error("Signal not ignored during the shutdown")
+			},
+		},
+		wwr.ServerOptions{
+			Hooks: &callbackPoweredMessageHooks{
+				SignalReceived: func(_ wwr.Connection, msg wwr.Message) {
+					assert.Equal(t, "late", msg.Name())
+					audited.Progress(1)
+				},
+			},
+		},
+	)
+
+	newClient := func() *callbackPoweredClient {
+		client := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+			},
+			callbackPoweredClientHooks{},
+		)
+		require.NoError(t, client.connection.Connect())
+		return client
+	}
+	requester := newClient()
+	defer requester.connection.Close()
+	signaler := newClient()
+	defer signaler.connection.Close()
+
+	// Start a request and shut the server down while it's being handled
+	go func() {
+		_, err := requester.connection.Request(
+			context.Background(),
+			"slow",
+			nil,
+		)
+		assert.NoError(t, err)
+	}()
+	require.NoError(t, requestHandled.Wait(), "Request not handled")
+
+	shutDown := make(chan error, 1)
+	go func() { shutDown <- server.Shutdown() }()
+
+	// Wait for the server to start shutting down
+	time.Sleep(50 * time.Millisecond)
+
+	// Expect the ignored signal to be audited
+	require.NoError(t, signaler.connection.Signal(
+		"late",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("test")),
+	))
+	require.NoError(t, audited.Wait(), "Signal not audited")
+
+	close(release)
+	require.NoError(t, <-shutDown)
+}
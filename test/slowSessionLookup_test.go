@@ -0,0 +1,65 @@
+package test
+
+import (
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSlowSessionLookup tests recording the duration of session lookups
+// and warning about slow session lookups
+func TestSlowSessionLookup(t *testing.T) {
+	lookupDelay := 100 * time.Millisecond
+	lookupRecorded := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	var recordedDuration time.Duration
+	warnLog := &syncLogWriter{}
+
+	// Initialize server using a slow session manager
+	server := setupServer(
+		t,
+		&serverImpl{},
+		wwr.ServerOptions{
+			SessionManager: &callbackPoweredSessionManager{
+				SessionLookup: func(_ string) (wwr.SessionLookupResult, error) {
+					time.Sleep(lookupDelay)
+					return nil, nil
+				},
+			},
+			SlowSessionLookupThreshold: 50 * time.Millisecond,
+			Metrics: &callbackPoweredMetricsRecorder{
+				SessionLookup: func(duration time.Duration) {
+					recordedDuration = duration
+					lookupRecorded.Progress(1)
+				},
+			},
+			WarnLog: log.New(warnLog, "WARN: ", 0),
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	err := client.connection.RestoreSession([]byte("somekey"))
+	require.IsType(t, wwr.SessNotFoundErr{}, err)
+
+	// Verify the recorded metric and the logged warning
+	require.NoError(t, lookupRecorded.Wait(), "Metric not recorded")
+	require.True(t, recordedDuration >= lookupDelay)
+	require.Contains(t, warnLog.String(), "Slow session lookup")
+}
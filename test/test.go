@@ -30,7 +30,7 @@ func setupServer(
 		}
 	}
 	if impl.onClientConnected == nil {
-		impl.onClientConnected = func(_ wwr.Connection) {}
+		impl.onClientConnected = func(_ wwr.Connection) error { return nil }
 	}
 	if impl.onClientDisconnected == nil {
 		impl.onClientDisconnected = func(_ wwr.Connection) {}
@@ -55,7 +55,7 @@ func setupServer(
 
 	// Use default session manager if no specific one is defined
 	if opts.SessionManager == nil {
-		opts.SessionManager = newInMemSessManager()
+		opts.SessionManager = wwr.NewInMemorySessionManager()
 	}
 
 	// Use default address
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestUpgradeMetrics tests whether the metrics recorder is notified
+// about successful connection upgrades before the client is connected
+func TestUpgradeMetrics(t *testing.T) {
+	upgraded := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	var upgradedAddr net.Addr
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) error {
+				// Expect the upgrade to be recorded beforehand
+				assert.True(t, upgraded.IsCompleted())
+				assert.Equal(t, upgradedAddr, conn.Info().RemoteAddr)
+				return nil
+			},
+		},
+		wwr.ServerOptions{
+			Metrics: &callbackPoweredMetricsRecorder{
+				Upgrade: func(remoteAddr net.Addr, duration time.Duration) {
+					assert.NotNil(t, remoteAddr)
+					assert.True(t, duration >= 0)
+					upgradedAddr = remoteAddr
+					upgraded.Progress(1)
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect: wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+	require.NoError(t, upgraded.Wait(), "Upgrade not recorded")
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestWatchSessions tests whether session registry changes are emitted
+// to the channel returned by server.WatchSessions
+func TestWatchSessions(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if msg.Name() == "logout" {
+					return nil, conn.CloseSession()
+				}
+				err := conn.CreateSession(nil)
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{},
+	)
+	events, cancel := server.WatchSessions()
+	defer cancel()
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Create and close the session
+	_, err := client.connection.Request(context.Background(), "login", nil)
+	require.NoError(t, err)
+	sessionKey := client.connection.Session().Key
+
+	_, err = client.connection.Request(context.Background(), "logout", nil)
+	require.NoError(t, err)
+
+	expected := []wwr.SessionEventType{
+		wwr.SessionEventCreated,
+		wwr.SessionEventConnectionAdded,
+		wwr.SessionEventConnectionRemoved,
+		wwr.SessionEventClosed,
+	}
+	for _, eventType := range expected {
+		select {
+		case event := <-events:
+			require.Equal(t, eventType, event.Type)
+			require.Equal(t, sessionKey, event.SessionKey)
+			require.NotNil(t, event.Connection)
+		case <-time.After(1 * time.Second):
+			t.Fatalf("Event %s not received", eventType)
+		}
+	}
+
+	// Expect the channel to be closed on shutdown
+	require.NoError(t, server.Shutdown())
+	select {
+	case _, open := <-events:
+		require.False(t, open)
+	case <-time.After(1 * time.Second):
+		t.Fatal("Channel not closed on shutdown")
+	}
+}
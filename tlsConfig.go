@@ -0,0 +1,44 @@
+package webwire
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+// newTLSConfig returns the TLS configuration for the built-in HTTP server
+// or nil if TLS isn't configured.
+// The certificate and key files, if any, are loaded in addition
+// to the certificates of the provided TLS configuration.
+// Only HTTP/1.1 is negotiated since WebSocket upgrades aren't supported
+// over HTTP/2
+func newTLSConfig(opts ServerOptions) (*tls.Config, error) {
+	if opts.TLSConfig == nil && opts.CertFile == "" && opts.KeyFile == "" {
+		return nil, nil
+	}
+
+	config := &tls.Config{}
+	if opts.TLSConfig != nil {
+		config = opts.TLSConfig.Clone()
+	}
+
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		if opts.CertFile == "" || opts.KeyFile == "" {
+			return nil, fmt.Errorf(
+				"TLS requires both a certificate and a key file",
+			)
+		}
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't load TLS key pair: %s", err)
+		}
+		config.Certificates = append(config.Certificates, cert)
+	}
+
+	if len(config.Certificates) < 1 && config.GetCertificate == nil {
+		return nil, fmt.Errorf("TLS configuration provides no certificate")
+	}
+
+	config.NextProtos = []string{"http/1.1"}
+
+	return config, nil
+}
@@ -0,0 +1,337 @@
+package wwrtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	webwire "github.com/qbeon/webwire-go"
+)
+
+// RequestHandler represents the function replying to the server-side
+// requests sent to a fake connection
+type RequestHandler func(
+	ctx context.Context,
+	name string,
+	payload webwire.Payload,
+) (webwire.Payload, error)
+
+// FakeConnection implements the webwire.Connection interface keeping its
+// state in memory and recording all method calls. It's meant for
+// unit-testing request and signal handlers in isolation without
+// running a real server and client.
+// Sessions created on a fake connection are neither persisted nor
+// synchronized to any client, a canned session can be assigned
+// through SetSession
+type FakeConnection struct {
+	recorder
+
+	lock           sync.Mutex
+	info           webwire.ClientInfo
+	active         bool
+	session        *webwire.Session
+	state          map[string]interface{}
+	errs           map[string]error
+	requestHandler RequestHandler
+	sessionKeyGen  webwire.SessionKeyGenerator
+
+	// sessionConnections are the canned connections sharing the session
+	sessionConnections []webwire.Connection
+}
+
+// NewFakeConnection creates a new active fake connection
+// described by the given client information.
+// The connection time is set to the current time if it's undefined
+func NewFakeConnection(info webwire.ClientInfo) *FakeConnection {
+	if info.ConnectionTime.IsZero() {
+		info.ConnectionTime = time.Now()
+	}
+	return &FakeConnection{
+		lock:          sync.Mutex{},
+		info:          info,
+		active:        true,
+		state:         make(map[string]interface{}),
+		errs:          make(map[string]error),
+		sessionKeyGen: webwire.NewDefaultSessionKeyGenerator(),
+	}
+}
+
+// SetSession assigns a copy of the given canned session to the connection
+// replacing the current one. A nil session removes the current session
+func (conn *FakeConnection) SetSession(session *webwire.Session) {
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	conn.session = session.Clone()
+}
+
+// SetSessionConnections sets the canned connections sharing the session
+// of the connection returned by SessionConnections
+func (conn *FakeConnection) SetSessionConnections(
+	connections []webwire.Connection,
+) {
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	conn.sessionConnections = connections
+}
+
+// SetError makes all subsequent calls of the given method fail with the
+// given error without affecting the state of the connection.
+// A nil error makes the method succeed again
+func (conn *FakeConnection) SetError(method string, err error) {
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	if err == nil {
+		delete(conn.errs, method)
+		return
+	}
+	conn.errs[method] = err
+}
+
+// SetRequestHandler sets the handler replying to the requests sent
+// through Request. Requests are replied with a nil payload by default
+func (conn *FakeConnection) SetRequestHandler(handler RequestHandler) {
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	conn.requestHandler = handler
+}
+
+// IsActive implements the webwire.Connection interface
+func (conn *FakeConnection) IsActive() bool {
+	conn.record("IsActive")
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	return conn.active
+}
+
+// Info implements the webwire.Connection interface
+func (conn *FakeConnection) Info() webwire.ClientInfo {
+	conn.record("Info")
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	return conn.info
+}
+
+// ClientIdentifier implements the webwire.Connection interface
+func (conn *FakeConnection) ClientIdentifier() string {
+	conn.record("ClientIdentifier")
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	return conn.info.ClientIdentifier
+}
+
+// IsObserver implements the webwire.Connection interface
+func (conn *FakeConnection) IsObserver() bool {
+	conn.record("IsObserver")
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	return conn.info.Observer
+}
+
+// Signal implements the webwire.Connection interface.
+// The signal is only recorded
+func (conn *FakeConnection) Signal(
+	name string,
+	payload webwire.Payload,
+) error {
+	conn.record("Signal", name, payload)
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	return conn.errs["Signal"]
+}
+
+// Request implements the webwire.Connection interface.
+// The request is replied by the handler set through SetRequestHandler
+func (conn *FakeConnection) Request(
+	ctx context.Context,
+	name string,
+	payload webwire.Payload,
+) (webwire.Payload, error) {
+	conn.record("Request", ctx, name, payload)
+	conn.lock.Lock()
+	handler := conn.requestHandler
+	err := conn.errs["Request"]
+	conn.lock.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	if handler == nil {
+		return nil, nil
+	}
+	return handler(ctx, name, payload)
+}
+
+// CreateSession implements the webwire.Connection interface.
+// It replaces the current session by a new session carrying the given info
+func (conn *FakeConnection) CreateSession(
+	attachment webwire.SessionInfo,
+) error {
+	conn.record("CreateSession", attachment)
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	if err := conn.errs["CreateSession"]; err != nil {
+		return err
+	}
+	if conn.info.Observer {
+		return webwire.ObserverErr{}
+	}
+	session := webwire.NewSession(attachment, conn.sessionKeyGen.Generate)
+	conn.session = &session
+	return nil
+}
+
+// updateSession replaces the info of the current session.
+// Expects the lock to be held by the caller
+func (conn *FakeConnection) updateSession(
+	method string,
+	info webwire.SessionInfo,
+) error {
+	if err := conn.errs[method]; err != nil {
+		return err
+	}
+	if conn.session == nil {
+		return fmt.Errorf("Can't update session, no session active")
+	}
+	conn.session.Info = info
+	return nil
+}
+
+// UpdateSessionInfo implements the webwire.Connection interface
+func (conn *FakeConnection) UpdateSessionInfo(
+	info webwire.SessionInfo,
+) error {
+	conn.record("UpdateSessionInfo", info)
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	return conn.updateSession("UpdateSessionInfo", info)
+}
+
+// UpdateSession implements the webwire.Connection interface.
+// It updates the session of this connection only
+func (conn *FakeConnection) UpdateSession(info webwire.SessionInfo) error {
+	conn.record("UpdateSession", info)
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	if err := conn.updateSession("UpdateSession", info); err != nil {
+		return err
+	}
+	conn.session.LastLookup = time.Now()
+	return nil
+}
+
+// CloseSession implements the webwire.Connection interface
+func (conn *FakeConnection) CloseSession() error {
+	conn.record("CloseSession")
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	if err := conn.errs["CloseSession"]; err != nil {
+		return err
+	}
+	conn.session = nil
+	return nil
+}
+
+// CloseSessionWithReason implements the webwire.Connection interface
+func (conn *FakeConnection) CloseSessionWithReason(reason string) error {
+	conn.record("CloseSessionWithReason", reason)
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	if err := conn.errs["CloseSessionWithReason"]; err != nil {
+		return err
+	}
+	conn.session = nil
+	return nil
+}
+
+// HasSession implements the webwire.Connection interface
+func (conn *FakeConnection) HasSession() bool {
+	conn.record("HasSession")
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	return conn.session != nil
+}
+
+// Session implements the webwire.Connection interface
+func (conn *FakeConnection) Session() *webwire.Session {
+	conn.record("Session")
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	return conn.session.Clone()
+}
+
+// SessionKey implements the webwire.Connection interface
+func (conn *FakeConnection) SessionKey() string {
+	conn.record("SessionKey")
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	if conn.session == nil {
+		return ""
+	}
+	return conn.session.Key
+}
+
+// SessionCreation implements the webwire.Connection interface
+func (conn *FakeConnection) SessionCreation() time.Time {
+	conn.record("SessionCreation")
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	if conn.session == nil {
+		return time.Time{}
+	}
+	return conn.session.Creation
+}
+
+// SessionInfo implements the webwire.Connection interface
+func (conn *FakeConnection) SessionInfo(name string) interface{} {
+	conn.record("SessionInfo", name)
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	if conn.session == nil || conn.session.Info == nil {
+		return nil
+	}
+	return conn.session.Info.Value(name)
+}
+
+// SessionConnections implements the webwire.Connection interface.
+// It returns the connections set through SetSessionConnections
+// or nil if there's no session
+func (conn *FakeConnection) SessionConnections() []webwire.Connection {
+	conn.record("SessionConnections")
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	if conn.session == nil {
+		return nil
+	}
+	connections := make([]webwire.Connection, len(conn.sessionConnections))
+	copy(connections, conn.sessionConnections)
+	return connections
+}
+
+// SetState implements the webwire.Connection interface
+func (conn *FakeConnection) SetState(key string, value interface{}) {
+	conn.record("SetState", key, value)
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	if value == nil {
+		delete(conn.state, key)
+		return
+	}
+	conn.state[key] = value
+}
+
+// State implements the webwire.Connection interface
+func (conn *FakeConnection) State(key string) interface{} {
+	conn.record("State", key)
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	return conn.state[key]
+}
+
+// Close implements the webwire.Connection interface
+func (conn *FakeConnection) Close() {
+	conn.record("Close")
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	conn.active = false
+}
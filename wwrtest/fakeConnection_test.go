@@ -0,0 +1,129 @@
+package wwrtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	webwire "github.com/qbeon/webwire-go"
+)
+
+// userInfo represents the session info of an authenticated user
+type userInfo struct {
+	username string
+}
+
+func (info *userInfo) Copy() webwire.SessionInfo {
+	return &userInfo{username: info.username}
+}
+
+func (info *userInfo) Fields() []string {
+	return []string{"username"}
+}
+
+func (info *userInfo) Value(fieldName string) interface{} {
+	if fieldName == "username" {
+		return info.username
+	}
+	return nil
+}
+
+// testMessage represents a request message received by a handler
+type testMessage struct {
+	name    string
+	payload webwire.Payload
+}
+
+func (msg testMessage) MessageType() byte        { return 0 }
+func (msg testMessage) Identifier() [8]byte      { return [8]byte{} }
+func (msg testMessage) Name() string             { return msg.name }
+func (msg testMessage) Payload() webwire.Payload { return msg.payload }
+
+// login represents a request handler creating a session for the user
+// given in the request payload
+func login(
+	_ context.Context,
+	conn webwire.Connection,
+	msg webwire.Message,
+) (webwire.Payload, error) {
+	username := string(msg.Payload().Data())
+	if username == "" {
+		return nil, webwire.ReqErr{Code: "INVALID_USERNAME"}
+	}
+	if err := conn.CreateSession(&userInfo{username: username}); err != nil {
+		return nil, err
+	}
+	return nil, conn.Signal("welcome", msg.Payload())
+}
+
+// TestFakeConnectionCreateSession tests whether the fake connection
+// records the session creation of a request handler
+func TestFakeConnectionCreateSession(t *testing.T) {
+	conn := NewFakeConnection(webwire.ClientInfo{})
+	payload := webwire.NewPayload(webwire.EncodingUtf8, []byte("alice"))
+
+	_, err := login(
+		context.Background(),
+		conn,
+		testMessage{name: "login", payload: payload},
+	)
+	require.NoError(t, err)
+
+	require.True(t, conn.Called("CreateSession", &userInfo{username: "alice"}))
+	require.True(t, conn.Called("Signal", "welcome", payload))
+	require.True(t, conn.HasSession())
+	require.Equal(t, "alice", conn.SessionInfo("username"))
+}
+
+// TestFakeConnectionErrors tests whether canned errors
+// are returned by the fake connection
+func TestFakeConnectionErrors(t *testing.T) {
+	conn := NewFakeConnection(webwire.ClientInfo{})
+	createErr := errors.New("session storage unavailable")
+	conn.SetError("CreateSession", createErr)
+
+	_, err := login(
+		context.Background(),
+		conn,
+		testMessage{
+			name:    "login",
+			payload: webwire.NewPayload(webwire.EncodingUtf8, []byte("bob")),
+		},
+	)
+	require.Equal(t, createErr, err)
+	require.False(t, conn.HasSession())
+	require.Len(t, conn.CallsOf("Signal"), 0)
+
+	// Expect observers to be unable to create sessions
+	observer := NewFakeConnection(webwire.ClientInfo{Observer: true})
+	require.IsType(t, webwire.ObserverErr{}, observer.CreateSession(nil))
+}
+
+// TestFakeConnectionCannedSession tests whether
+// the fake connection returns the canned session
+func TestFakeConnectionCannedSession(t *testing.T) {
+	conn := NewFakeConnection(webwire.ClientInfo{})
+	creation := time.Now().Add(-time.Hour)
+	conn.SetSession(&webwire.Session{
+		Key:        "testkey",
+		Creation:   creation,
+		LastLookup: creation,
+		Info:       &userInfo{username: "carol"},
+	})
+
+	require.Equal(t, "testkey", conn.SessionKey())
+	require.Equal(t, creation, conn.SessionCreation())
+	require.Equal(t, "carol", conn.SessionInfo("username"))
+
+	require.NoError(t, conn.CloseSession())
+	require.False(t, conn.HasSession())
+	require.Nil(t, conn.Session())
+
+	// Expect the fake connection to be usable with the fake server
+	srv := NewFakeServer(nil)
+	srv.AddConnection(conn)
+	require.Equal(t, 1, srv.AnonymousConnectionsNum())
+}
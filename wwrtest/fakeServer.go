@@ -0,0 +1,483 @@
+package wwrtest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	webwire "github.com/qbeon/webwire-go"
+)
+
+// FakeServer implements the webwire.Server interface keeping its state
+// in memory and recording all method calls. It's meant for unit-testing
+// code depending on a webwire.Server without running a real server.
+// The connections of the fake server are provided through AddConnection,
+// the sessions are derived from the session keys of the connections
+type FakeServer struct {
+	recorder
+
+	lock           sync.Mutex
+	addr           net.Addr
+	connections    []webwire.Connection
+	sessionManager webwire.SessionManager
+	shutdown       bool
+	stopped        chan struct{}
+
+	// changed is closed and replaced whenever the connections change
+	changed chan struct{}
+
+	// watchers are the channels returned by WatchSessions
+	watchers []chan webwire.SessionEvent
+}
+
+// NewFakeServer creates a new fake server instance
+// pretending to listen on the given address
+func NewFakeServer(addr net.Addr) *FakeServer {
+	if addr == nil {
+		addr = &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}
+	}
+	return &FakeServer{
+		lock:        sync.Mutex{},
+		addr:        addr,
+		connections: make([]webwire.Connection, 0),
+		stopped:     make(chan struct{}),
+		changed:     make(chan struct{}),
+	}
+}
+
+// notifyChanged wakes up all goroutines awaiting connection changes.
+// Expects the lock to be held by the caller
+func (srv *FakeServer) notifyChanged() {
+	close(srv.changed)
+	srv.changed = make(chan struct{})
+}
+
+// sessionConnections returns all connections of the given session.
+// Expects the lock to be held by the caller
+func (srv *FakeServer) sessionConnections(
+	sessionKey string,
+) []webwire.Connection {
+	var connections []webwire.Connection
+	for _, conn := range srv.connections {
+		if conn.HasSession() && conn.SessionKey() == sessionKey {
+			connections = append(connections, conn)
+		}
+	}
+	return connections
+}
+
+// AddConnection adds the given connection to the fake server
+func (srv *FakeServer) AddConnection(conn webwire.Connection) {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	srv.connections = append(srv.connections, conn)
+	srv.notifyChanged()
+}
+
+// RemoveConnection removes the given connection from the fake server.
+// Does nothing if the connection wasn't added
+func (srv *FakeServer) RemoveConnection(conn webwire.Connection) {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	for i, current := range srv.connections {
+		if current == conn {
+			srv.connections = append(
+				srv.connections[:i],
+				srv.connections[i+1:]...,
+			)
+			srv.notifyChanged()
+			return
+		}
+	}
+}
+
+// SessionManager returns the session manager
+// last set through SetSessionManager
+func (srv *FakeServer) SessionManager() webwire.SessionManager {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	return srv.sessionManager
+}
+
+// ServeHTTP implements the webwire.Server interface.
+// The fake server can't upgrade connections
+// and replies with 501 not implemented
+func (srv *FakeServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	srv.lock.Lock()
+	srv.record("ServeHTTP", resp, req)
+	srv.lock.Unlock()
+	http.Error(
+		resp,
+		"Fake server doesn't serve connections",
+		http.StatusNotImplemented,
+	)
+}
+
+// Run implements the webwire.Server interface.
+// It blocks until Shutdown is called
+func (srv *FakeServer) Run() error {
+	srv.lock.Lock()
+	srv.record("Run")
+	srv.lock.Unlock()
+	<-srv.stopped
+	return nil
+}
+
+// Addr implements the webwire.Server interface
+func (srv *FakeServer) Addr() net.Addr {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	srv.record("Addr")
+	return srv.addr
+}
+
+// Scheme implements the webwire.Server interface
+func (srv *FakeServer) Scheme() string {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	srv.record("Scheme")
+	return "ws"
+}
+
+// Shutdown implements the webwire.Server interface
+func (srv *FakeServer) Shutdown() error {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	srv.record("Shutdown")
+	if !srv.shutdown {
+		srv.shutdown = true
+		close(srv.stopped)
+		for _, watcher := range srv.watchers {
+			close(watcher)
+		}
+		srv.watchers = nil
+	}
+	return nil
+}
+
+// SetSessionManager implements the webwire.Server interface
+func (srv *FakeServer) SetSessionManager(
+	sessionManager webwire.SessionManager,
+) {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	srv.record("SetSessionManager", sessionManager)
+	srv.sessionManager = sessionManager
+}
+
+// Connections implements the webwire.Server interface
+func (srv *FakeServer) Connections() []webwire.Connection {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	srv.record("Connections")
+	connections := make([]webwire.Connection, len(srv.connections))
+	copy(connections, srv.connections)
+	return connections
+}
+
+// ActiveSessionsNum implements the webwire.Server interface
+func (srv *FakeServer) ActiveSessionsNum() int {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	srv.record("ActiveSessionsNum")
+	sessions := make(map[string]struct{})
+	for _, conn := range srv.connections {
+		if conn.HasSession() {
+			sessions[conn.SessionKey()] = struct{}{}
+		}
+	}
+	return len(sessions)
+}
+
+// WatchSessions implements the webwire.Server interface.
+// The returned channel receives the events passed to EmitSessionEvent
+// and is closed on Shutdown or when the watcher is cancelled
+func (srv *FakeServer) WatchSessions() (
+	<-chan webwire.SessionEvent,
+	func(),
+) {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	srv.record("WatchSessions")
+	watcher := make(chan webwire.SessionEvent, 256)
+	if srv.shutdown {
+		close(watcher)
+		return watcher, func() {}
+	}
+	srv.watchers = append(srv.watchers, watcher)
+	return watcher, func() { srv.unwatch(watcher) }
+}
+
+// unwatch removes the given watcher closing its channel
+// unless it was already removed
+func (srv *FakeServer) unwatch(watcher chan webwire.SessionEvent) {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	for i, subscribed := range srv.watchers {
+		if subscribed == watcher {
+			srv.watchers = append(srv.watchers[:i], srv.watchers[i+1:]...)
+			close(watcher)
+			return
+		}
+	}
+}
+
+// EmitSessionEvent sends the given event to all watchers subscribed through
+// WatchSessions. Blocks until all watchers received the event
+func (srv *FakeServer) EmitSessionEvent(event webwire.SessionEvent) {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	for _, watcher := range srv.watchers {
+		watcher <- event
+	}
+}
+
+// AnonymousConnectionsNum implements the webwire.Server interface
+func (srv *FakeServer) AnonymousConnectionsNum() int {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	srv.record("AnonymousConnectionsNum")
+	num := 0
+	for _, conn := range srv.connections {
+		if !conn.HasSession() {
+			num++
+		}
+	}
+	return num
+}
+
+// AwaitConnections implements the webwire.Server interface
+func (srv *FakeServer) AwaitConnections(ctx context.Context, num int) error {
+	srv.lock.Lock()
+	srv.record("AwaitConnections", ctx, num)
+	for len(srv.connections) != num {
+		changed := srv.changed
+		srv.lock.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return webwire.TranslateContextError(ctx.Err())
+		}
+		srv.lock.Lock()
+	}
+	srv.lock.Unlock()
+	return nil
+}
+
+// Stats implements the webwire.Server interface
+func (srv *FakeServer) Stats() webwire.ServerStats {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	srv.record("Stats")
+	stats := webwire.ServerStats{
+		Connections:  len(srv.connections),
+		ShuttingDown: srv.shutdown,
+	}
+	sessions := make(map[string]struct{})
+	for _, conn := range srv.connections {
+		if conn.HasSession() {
+			sessions[conn.SessionKey()] = struct{}{}
+			stats.AuthenticatedConnections++
+		} else {
+			stats.AnonymousConnections++
+		}
+	}
+	stats.ActiveSessions = len(sessions)
+	return stats
+}
+
+// SessionConnectionsNum implements the webwire.Server interface
+func (srv *FakeServer) SessionConnectionsNum(sessionKey string) int {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	srv.record("SessionConnectionsNum", sessionKey)
+	connections := srv.sessionConnections(sessionKey)
+	if connections == nil {
+		return -1
+	}
+	return len(connections)
+}
+
+// SessionConnections implements the webwire.Server interface
+func (srv *FakeServer) SessionConnections(
+	sessionKey string,
+) []webwire.Connection {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	srv.record("SessionConnections", sessionKey)
+	return srv.sessionConnections(sessionKey)
+}
+
+// SetMaxSessionConnections implements the webwire.Server interface.
+// The call is only recorded, a webwire.SessNotFoundErr error is returned
+// if no fake connection holds the given session
+func (srv *FakeServer) SetMaxSessionConnections(
+	sessionKey string,
+	max uint,
+) error {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	srv.record("SetMaxSessionConnections", sessionKey, max)
+	if srv.sessionConnections(sessionKey) == nil {
+		return webwire.SessNotFoundErr{}
+	}
+	return nil
+}
+
+// SessionConnectionDetails implements the webwire.Server interface
+func (srv *FakeServer) SessionConnectionDetails(
+	sessionKey string,
+) []webwire.ConnectionDetails {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	srv.record("SessionConnectionDetails", sessionKey)
+	var details []webwire.ConnectionDetails
+	for _, conn := range srv.sessionConnections(sessionKey) {
+		details = append(details, webwire.ConnectionDetails{
+			Connection: conn,
+			ClientInfo: conn.Info(),
+		})
+	}
+	return details
+}
+
+// signal signals all given connections
+func signal(
+	connections []webwire.Connection,
+	name string,
+	payload webwire.Payload,
+) (results []webwire.SignalResult, err error) {
+	errNum := 0
+	for _, conn := range connections {
+		signalErr := conn.Signal(name, payload)
+		if signalErr != nil {
+			errNum++
+		}
+		results = append(results, webwire.SignalResult{
+			Connection: conn,
+			Err:        signalErr,
+		})
+	}
+	if errNum > 0 {
+		err = fmt.Errorf("%d errors during signal transmission", errNum)
+	}
+	return results, err
+}
+
+// SignalSession implements the webwire.Server interface
+func (srv *FakeServer) SignalSession(
+	sessionKey,
+	name string,
+	payload webwire.Payload,
+) ([]webwire.SignalResult, error) {
+	srv.lock.Lock()
+	srv.record("SignalSession", sessionKey, name, payload)
+	connections := srv.sessionConnections(sessionKey)
+	srv.lock.Unlock()
+	return signal(connections, name, payload)
+}
+
+// SignalWhere implements the webwire.Server interface
+func (srv *FakeServer) SignalWhere(
+	predicate func(*webwire.Session) bool,
+	name string,
+	payload webwire.Payload,
+) ([]webwire.SignalResult, error) {
+	srv.lock.Lock()
+	srv.record("SignalWhere", predicate, name, payload)
+	var connections []webwire.Connection
+	matches := make(map[string]bool)
+	for _, conn := range srv.connections {
+		session := conn.Session()
+		if session == nil {
+			continue
+		}
+		matched, evaluated := matches[session.Key]
+		if !evaluated {
+			matched = predicate(session)
+			matches[session.Key] = matched
+		}
+		if matched {
+			connections = append(connections, conn)
+		}
+	}
+	srv.lock.Unlock()
+	return signal(connections, name, payload)
+}
+
+// UpdateSessionInfo implements the webwire.Server interface
+func (srv *FakeServer) UpdateSessionInfo(
+	sessionKey string,
+	info webwire.SessionInfo,
+) ([]webwire.Connection, []error, error) {
+	srv.lock.Lock()
+	srv.record("UpdateSessionInfo", sessionKey, info)
+	connections := srv.sessionConnections(sessionKey)
+	srv.lock.Unlock()
+
+	return forEach(connections, func(conn webwire.Connection) error {
+		var connInfo webwire.SessionInfo
+		if info != nil {
+			connInfo = info.Copy()
+		}
+		return conn.UpdateSessionInfo(connInfo)
+	})
+}
+
+// CloseSession implements the webwire.Server interface
+func (srv *FakeServer) CloseSession(
+	sessionKey string,
+) ([]webwire.Connection, []error, error) {
+	srv.lock.Lock()
+	srv.record("CloseSession", sessionKey)
+	connections := srv.sessionConnections(sessionKey)
+	srv.lock.Unlock()
+
+	return forEach(connections, func(conn webwire.Connection) error {
+		return conn.CloseSession()
+	})
+}
+
+// CloseSessionWithReason implements the webwire.Server interface
+func (srv *FakeServer) CloseSessionWithReason(
+	sessionKey,
+	reason string,
+) ([]webwire.Connection, []error, error) {
+	srv.lock.Lock()
+	srv.record("CloseSessionWithReason", sessionKey, reason)
+	connections := srv.sessionConnections(sessionKey)
+	srv.lock.Unlock()
+
+	return forEach(connections, func(conn webwire.Connection) error {
+		return conn.CloseSessionWithReason(reason)
+	})
+}
+
+// forEach applies the given operation to all given connections
+// returning the affected connections, the error of each operation
+// and a general error if at least one of the operations failed.
+// Returns (nil, nil, nil) if there are no connections
+func forEach(
+	connections []webwire.Connection,
+	operation func(webwire.Connection) error,
+) ([]webwire.Connection, []error, error) {
+	if len(connections) < 1 {
+		return nil, nil, nil
+	}
+	errs := make([]error, len(connections))
+	errNum := 0
+	for i, conn := range connections {
+		if errs[i] = operation(conn); errs[i] != nil {
+			errNum++
+		}
+	}
+	if errNum > 0 {
+		return connections, errs, fmt.Errorf(
+			"%d errors during the session operation",
+			errNum,
+		)
+	}
+	return connections, errs, nil
+}
@@ -0,0 +1,68 @@
+package wwrtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	webwire "github.com/qbeon/webwire-go"
+)
+
+// revokeSession represents application code depending on a webwire server
+func revokeSession(srv webwire.Server, sessionKey string) error {
+	_, _, err := srv.CloseSession(sessionKey)
+	return err
+}
+
+// TestFakeServerRecordsCalls tests whether the fake server records
+// the calls of the application code
+func TestFakeServerRecordsCalls(t *testing.T) {
+	srv := NewFakeServer(nil)
+
+	require.NoError(t, revokeSession(srv, "testkey"))
+
+	require.True(t, srv.Called("CloseSession", "testkey"))
+	require.False(t, srv.Called("CloseSession", "otherkey"))
+	require.Equal(t, []Call{
+		{Method: "CloseSession", Args: []interface{}{"testkey"}},
+	}, srv.Calls())
+}
+
+// TestFakeServerRunShutdown tests whether Run blocks until Shutdown is called
+func TestFakeServerRunShutdown(t *testing.T) {
+	srv := NewFakeServer(nil)
+
+	returned := make(chan error, 1)
+	go func() {
+		returned <- srv.Run()
+	}()
+
+	require.NoError(t, srv.Shutdown())
+	select {
+	case err := <-returned:
+		require.NoError(t, err)
+	case <-time.After(1 * time.Second):
+		t.Fatal("Run didn't return after Shutdown")
+	}
+	require.True(t, srv.Stats().ShuttingDown)
+}
+
+// TestFakeServerAwaitConnectionsTimeout tests whether AwaitConnections
+// fails when the context deadline is exceeded
+func TestFakeServerAwaitConnectionsTimeout(t *testing.T) {
+	srv := NewFakeServer(nil)
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		10*time.Millisecond,
+	)
+	defer cancel()
+	require.IsType(
+		t,
+		webwire.DeadlineExceededErr{},
+		srv.AwaitConnections(ctx, 1),
+	)
+	require.NoError(t, srv.AwaitConnections(context.Background(), 0))
+}
@@ -0,0 +1,66 @@
+package wwrtest
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Call represents a recorded method call of a testing double
+type Call struct {
+	// Method is the name of the called method
+	Method string
+
+	// Args are the arguments the method was called with
+	Args []interface{}
+}
+
+// recorder records the method calls of a testing double
+type recorder struct {
+	lock  sync.Mutex
+	calls []Call
+}
+
+// record records a call of the given method
+func (rec *recorder) record(method string, args ...interface{}) {
+	rec.lock.Lock()
+	defer rec.lock.Unlock()
+	rec.calls = append(rec.calls, Call{
+		Method: method,
+		Args:   args,
+	})
+}
+
+// Calls returns a copy of all recorded method calls in the order of their
+// invocation
+func (rec *recorder) Calls() []Call {
+	rec.lock.Lock()
+	defer rec.lock.Unlock()
+	calls := make([]Call, len(rec.calls))
+	copy(calls, rec.calls)
+	return calls
+}
+
+// CallsOf returns all recorded calls of the given method
+// in the order of their invocation
+func (rec *recorder) CallsOf(method string) []Call {
+	rec.lock.Lock()
+	defer rec.lock.Unlock()
+	var calls []Call
+	for _, call := range rec.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Called returns true if the given method was called
+// with exactly the given arguments at least once, otherwise returns false
+func (rec *recorder) Called(method string, args ...interface{}) bool {
+	for _, call := range rec.CallsOf(method) {
+		if reflect.DeepEqual(call.Args, args) {
+			return true
+		}
+	}
+	return false
+}